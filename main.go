@@ -10,10 +10,12 @@ import (
 	"github.com/ekristen/aws-nuke/v3/pkg/common"
 
 	_ "github.com/ekristen/aws-nuke/v3/pkg/commands/account"
+	_ "github.com/ekristen/aws-nuke/v3/pkg/commands/approve"
 	_ "github.com/ekristen/aws-nuke/v3/pkg/commands/completion"
 	_ "github.com/ekristen/aws-nuke/v3/pkg/commands/config"
 	_ "github.com/ekristen/aws-nuke/v3/pkg/commands/list"
 	_ "github.com/ekristen/aws-nuke/v3/pkg/commands/nuke"
+	_ "github.com/ekristen/aws-nuke/v3/pkg/commands/restore"
 	_ "github.com/ekristen/aws-nuke/v3/pkg/commands/version"
 
 	_ "github.com/ekristen/aws-nuke/v3/resources"
@@ -42,9 +44,12 @@ func main() {
 			logrus.Fatalf("Command %s not found.", s)
 		},
 		EnableShellCompletion: true,
+		// Errors are logged and mapped to their exit code below, instead of being printed by the cli package
+		ExitErrHandler: func(context.Context, *cli.Command, error) {},
 	}
 
 	if err := app.Run(context.Background(), os.Args); err != nil {
-		logrus.Fatal(err)
+		logrus.StandardLogger().Log(logrus.FatalLevel, err)
+		os.Exit(common.ExitCode(err))
 	}
 }
@@ -0,0 +1,289 @@
+package approval
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+	"github.com/ekristen/libnuke/pkg/types"
+
+	"github.com/ekristen/aws-nuke/v3/pkg/config"
+)
+
+var testSecret = []byte("shared-secret")
+
+type testResource struct {
+	name string
+}
+
+func (r *testResource) Remove(_ context.Context) error {
+	return nil
+}
+
+func (r *testResource) Properties() types.Properties {
+	return types.NewProperties().Set("Name", r.name)
+}
+
+func (r *testResource) String() string {
+	return r.name
+}
+
+func testQueue(names ...string) *queue.Queue {
+	q := queue.New()
+	for _, name := range names {
+		q.Items = append(q.Items, &queue.Item{
+			Type: "EC2Instance", Owner: "us-east-1", State: queue.ItemStateNew, Resource: &testResource{name},
+		})
+	}
+
+	q.Items = append(q.Items, &queue.Item{
+		Type: "EC2Instance", Owner: "us-east-1", State: queue.ItemStateFiltered, Resource: &testResource{"filtered"},
+	})
+
+	return q
+}
+
+func TestNewPlan(t *testing.T) {
+	plan := NewPlan("123456789012", "sandbox", "alice", testQueue("i-2", "i-1"))
+
+	assert.Len(t, plan.Resources, 2)
+	assert.Equal(t, "i-1", plan.Resources[0].Name)
+	assert.Equal(t, map[string]int{"EC2Instance": 2}, plan.Counts())
+
+	// Every plan has its own nonce, the same resources result in another digest in every run
+	other := NewPlan("123456789012", "", "bob", testQueue("i-1", "i-2"))
+	assert.NotEqual(t, plan.Nonce, other.Nonce)
+	assert.NotEqual(t, plan.Digest, other.Digest)
+
+	// With the same nonce the digest only depends on the account and the resources
+	other.Nonce = plan.Nonce
+	assert.Equal(t, plan.Digest, other.ComputeDigest())
+
+	other.AccountID = "210987654321"
+	assert.NotEqual(t, plan.Digest, other.ComputeDigest())
+}
+
+func TestReadPlan(t *testing.T) {
+	plan := NewPlan("123456789012", "sandbox", "alice", testQueue("i-1"))
+	path := filepath.Join(t.TempDir(), "plan.json")
+
+	raw, err := json.Marshal(plan)
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(path, raw, 0600))
+
+	read, err := ReadPlan(path)
+	assert.NoError(t, err)
+	assert.Equal(t, plan.Digest, read.Digest)
+
+	plan.Resources = append(plan.Resources, Resource{ResourceType: "S3Bucket", Region: "global", Name: "bucket"})
+	raw, err = json.Marshal(plan)
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(path, raw, 0600))
+
+	_, err = ReadPlan(path)
+	assert.ErrorContains(t, err, "has been modified")
+}
+
+func TestSignVerify(t *testing.T) {
+	token := &Token{Digest: "abc", Approver: "bob", IssuedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	raw, err := Sign(testSecret, token)
+	assert.NoError(t, err)
+
+	verified, err := Verify(testSecret, raw+"\n")
+	assert.NoError(t, err)
+	assert.Equal(t, token, verified)
+
+	_, err = Verify([]byte("other-secret"), raw)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	forged, err := Sign([]byte("other-secret"), &Token{Digest: "abc", Approver: "mallory"})
+	assert.NoError(t, err)
+	payload, _, _ := strings.Cut(forged, ".")
+	_, signature, _ := strings.Cut(raw, ".")
+	_, err = Verify(testSecret, payload+"."+signature)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	_, err = Verify(testSecret, "not-a-token")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	_, err = Sign(nil, token)
+	assert.ErrorIs(t, err, ErrNoSecret)
+}
+
+// publishedPlan waits until a plan has been written to the directory and returns it.
+func publishedPlan(t *testing.T, dir string) *Plan {
+	for {
+		paths, err := filepath.Glob(filepath.Join(dir, "*.plan.json"))
+		assert.NoError(t, err)
+
+		if len(paths) > 0 {
+			plan, err := ReadPlan(paths[0])
+			if err == nil {
+				return plan
+			}
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func newTestGate(cfg *config.Approval) *Gate {
+	return &Gate{
+		Config:    cfg,
+		Secret:    testSecret,
+		AccountID: "123456789012",
+		Requester: "arn:aws:iam::123456789012:user/alice",
+	}
+}
+
+func TestGate_HandleDirectory(t *testing.T) {
+	cases := []struct {
+		name     string
+		approver string
+		digest   string
+		issuedAt time.Duration
+		wantErr  string
+	}{
+		{
+			name:     "approved",
+			approver: "arn:aws:iam::123456789012:user/bob",
+		},
+		{
+			name:     "issued-before-plan",
+			approver: "arn:aws:iam::123456789012:user/bob",
+			issuedAt: -time.Hour,
+			wantErr:  "the token has expired",
+		},
+		{
+			name:     "self-approved",
+			approver: "arn:aws:iam::123456789012:user/alice",
+			wantErr:  "it must be approved by a principal other than arn:aws:iam::123456789012:user/alice",
+		},
+		{
+			name:     "other-plan",
+			approver: "arn:aws:iam::123456789012:user/bob",
+			digest:   "other",
+			wantErr:  "the token approves plan other",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			gate := newTestGate(&config.Approval{
+				Directory:    dir,
+				Timeout:      5 * time.Second,
+				PollInterval: 10 * time.Millisecond,
+			})
+
+			// Approve the plan once it has been published
+			go func() {
+				digest := publishedPlan(t, dir).Digest
+
+				tokenDigest := digest
+				if tc.digest != "" {
+					tokenDigest = tc.digest
+				}
+
+				token, err := Sign(testSecret, &Token{
+					Digest:   tokenDigest,
+					Approver: tc.approver,
+					IssuedAt: time.Now().UTC().Add(tc.issuedAt),
+				})
+				assert.NoError(t, err)
+				assert.NoError(t, os.WriteFile(TokenFile(dir, digest), []byte(token), 0600))
+			}()
+
+			err := gate.Handle(context.TODO(), testQueue("i-1"))
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestGate_HandleTimeout(t *testing.T) {
+	gate := newTestGate(&config.Approval{
+		Directory:    t.TempDir(),
+		Timeout:      50 * time.Millisecond,
+		PollInterval: 10 * time.Millisecond,
+	})
+
+	err := gate.Handle(context.TODO(), testQueue("i-1"))
+	assert.ErrorContains(t, err, "timed out waiting for the approval of plan")
+}
+
+func TestGate_HandleNoSecret(t *testing.T) {
+	gate := newTestGate(&config.Approval{Directory: t.TempDir()})
+	gate.Secret = nil
+
+	assert.ErrorIs(t, gate.Handle(context.TODO(), testQueue("i-1")), ErrNoSecret)
+}
+
+func TestGate_HandleURL(t *testing.T) {
+	var mu sync.Mutex
+	var published *Plan
+	polls := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		assert.Equal(t, "Bearer test", r.Header.Get("Authorization"))
+
+		switch r.Method {
+		case http.MethodPost:
+			body, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+
+			published = &Plan{}
+			assert.NoError(t, json.Unmarshal(body, published))
+			w.WriteHeader(http.StatusCreated)
+		case http.MethodGet:
+			assert.Equal(t, "/plans/"+published.Digest, r.URL.Path)
+
+			// The plan is approved on the second poll
+			polls++
+			if polls < 2 {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			token, err := Sign(testSecret, &Token{Digest: published.Digest, Approver: "bob", IssuedAt: time.Now().UTC()})
+			assert.NoError(t, err)
+			_, _ = w.Write([]byte(token))
+		}
+	}))
+	defer server.Close()
+
+	gate := newTestGate(&config.Approval{
+		URL:          server.URL + "/plans/",
+		Headers:      map[string]string{"Authorization": "Bearer test"},
+		Timeout:      5 * time.Second,
+		PollInterval: 10 * time.Millisecond,
+	})
+
+	assert.NoError(t, gate.Handle(context.TODO(), testQueue("i-1")))
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, "123456789012", published.AccountID)
+	assert.Equal(t, "arn:aws:iam::123456789012:user/alice", published.Requester)
+	assert.Len(t, published.Resources, 1)
+	assert.Equal(t, 2, polls)
+}
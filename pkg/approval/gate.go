@@ -0,0 +1,216 @@
+package approval
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+
+	"github.com/ekristen/aws-nuke/v3/pkg/config"
+)
+
+// tokenClockSkew is how far the clocks of the requester and the approver may differ.
+const tokenClockSkew = 5 * time.Minute
+
+// PlanFile returns the path of the plan with the digest in the directory.
+func PlanFile(directory, digest string) string {
+	return filepath.Join(directory, digest+".plan.json")
+}
+
+// TokenFile returns the path of the approval token for the plan with the digest in the directory.
+func TokenFile(directory, digest string) string {
+	return filepath.Join(directory, digest+".token")
+}
+
+// Gate publishes the plan of a run and waits for its approval.
+type Gate struct {
+	Config *config.Approval
+	Secret []byte
+
+	AccountID    string
+	AccountAlias string
+
+	// Requester is the principal that runs aws-nuke, the plan must be approved by a different principal.
+	Requester string
+
+	Client *http.Client
+	Logger *logrus.Entry
+}
+
+// Handle publishes the plan for the resources in the queue that would be removed and waits until the plan has been
+// approved by a different principal. It returns an error when the approval is invalid or does not appear in time.
+func (g *Gate) Handle(ctx context.Context, q *queue.Queue) error {
+	if len(g.Secret) == 0 {
+		return ErrNoSecret
+	}
+
+	plan := NewPlan(g.AccountID, g.AccountAlias, g.Requester, q)
+
+	if err := g.Publish(ctx, plan); err != nil {
+		return fmt.Errorf("unable to publish plan %s: %w", plan.Digest, err)
+	}
+
+	g.log().WithField("_handler", "println").
+		Infof("waiting up to %s for the approval of plan %s (%d resources)",
+			g.Config.GetTimeout(), plan.Digest, len(plan.Resources))
+
+	ctx, cancel := context.WithTimeout(ctx, g.Config.GetTimeout())
+	defer cancel()
+
+	for {
+		raw, err := g.fetch(ctx, plan)
+		if err != nil {
+			g.log().WithError(err).Warn("unable to check for approval")
+		}
+
+		if raw != "" {
+			token, err := g.verify(plan, raw)
+			if err != nil {
+				return err
+			}
+
+			g.log().WithField("_handler", "println").Infof("plan %s approved by %s", plan.Digest, token.Approver)
+
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for the approval of plan %s", plan.Digest)
+		case <-time.After(g.Config.GetPollInterval()):
+		}
+	}
+}
+
+// Publish writes the plan to the directory or posts it to the URL of the approval.
+func (g *Gate) Publish(ctx context.Context, plan *Plan) error {
+	body, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if g.Config.Directory != "" {
+		path := PlanFile(g.Config.Directory, plan.Digest)
+		if err := os.WriteFile(path, body, 0o600); err != nil {
+			return err
+		}
+
+		g.log().WithField("_handler", "println").Infof("plan written to %s", path)
+
+		return nil
+	}
+
+	resp, err := g.do(ctx, http.MethodPost, os.ExpandEnv(g.Config.URL), body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected response status: %s", resp.Status)
+	}
+
+	return nil
+}
+
+// fetch returns the approval token for the plan, or an empty string when the plan has not been approved yet.
+func (g *Gate) fetch(ctx context.Context, plan *Plan) (string, error) {
+	if g.Config.Directory != "" {
+		raw, err := os.ReadFile(TokenFile(g.Config.Directory, plan.Digest))
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+
+		return strings.TrimSpace(string(raw)), err
+	}
+
+	url := strings.TrimSuffix(os.ExpandEnv(g.Config.URL), "/") + "/" + plan.Digest
+
+	resp, err := g.do(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return strings.TrimSpace(string(body)), nil
+	case http.StatusAccepted, http.StatusNoContent, http.StatusNotFound:
+		return "", nil
+	default:
+		return "", fmt.Errorf("unexpected response status: %s", resp.Status)
+	}
+}
+
+// verify ensures the token is signed with the secret, approves the plan and was issued by a different principal. The
+// token expires with the timeout of the approval, a token issued before the plan was created or longer ago than the
+// timeout is refused.
+func (g *Gate) verify(plan *Plan, raw string) (*Token, error) {
+	token, err := Verify(g.Secret, raw)
+	if err != nil {
+		return nil, fmt.Errorf("approval of plan %s refused: %w", plan.Digest, err)
+	}
+
+	if token.Digest != plan.Digest {
+		return nil, fmt.Errorf("approval of plan %s refused: the token approves plan %s", plan.Digest, token.Digest)
+	}
+
+	if token.IssuedAt.Before(plan.CreatedAt.Add(-tokenClockSkew)) ||
+		time.Since(token.IssuedAt) > g.Config.GetTimeout()+tokenClockSkew {
+		return nil, fmt.Errorf("approval of plan %s refused: the token has expired", plan.Digest)
+	}
+
+	if token.Approver == "" || strings.EqualFold(strings.TrimSpace(token.Approver), strings.TrimSpace(g.Requester)) {
+		return nil, fmt.Errorf("approval of plan %s refused: it must be approved by a principal other than %s",
+			plan.Digest, g.Requester)
+	}
+
+	return token, nil
+}
+
+func (g *Gate) do(ctx context.Context, method, url string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range g.Config.Headers {
+		req.Header.Set(key, os.ExpandEnv(value))
+	}
+
+	client := g.Client
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	return client.Do(req)
+}
+
+func (g *Gate) log() *logrus.Entry {
+	if g.Logger == nil {
+		logger := logrus.New()
+		logger.SetOutput(io.Discard)
+		return logrus.NewEntry(logger)
+	}
+
+	return g.Logger
+}
@@ -0,0 +1,134 @@
+// Package approval implements the two-person approval of a run. The plan of a run is published for review and the run
+// waits until an approval token for the plan, signed by a different principal, appears.
+package approval
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+
+	"github.com/ekristen/aws-nuke/v3/pkg/nuke"
+)
+
+// Resource is a resource that the plan would remove.
+type Resource struct {
+	ResourceType string            `json:"resourceType"`
+	Region       string            `json:"region"`
+	Name         string            `json:"name,omitempty"`
+	Properties   map[string]string `json:"properties,omitempty"`
+}
+
+// Plan is the list of resources a run would remove. The digest identifies the plan, an approval is only valid for the
+// plan with the same digest. The nonce is random for every run, so the approval of a plan cannot be replayed for a
+// later run that would remove the same resources.
+type Plan struct {
+	Digest       string     `json:"digest"`
+	Nonce        string     `json:"nonce"`
+	AccountID    string     `json:"accountId"`
+	AccountAlias string     `json:"accountAlias,omitempty"`
+	Requester    string     `json:"requester"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	Resources    []Resource `json:"resources"`
+}
+
+// NewPlan returns the plan for the resources in the queue that would be removed.
+func NewPlan(accountID, accountAlias, requester string, q *queue.Queue) *Plan {
+	p := &Plan{
+		Nonce:        rand.Text(),
+		AccountID:    accountID,
+		AccountAlias: accountAlias,
+		Requester:    requester,
+		CreatedAt:    time.Now().UTC(),
+		Resources:    make([]Resource, 0),
+	}
+
+	for _, item := range q.GetItems() {
+		switch item.GetState() {
+		case queue.ItemStateNew, queue.ItemStateNewDependency:
+		default:
+			continue
+		}
+
+		p.Resources = append(p.Resources, Resource{
+			ResourceType: item.Type,
+			Region:       item.Owner,
+			Name:         nuke.ItemName(item),
+			Properties:   nuke.ItemProperties(item),
+		})
+	}
+
+	sort.SliceStable(p.Resources, func(i, j int) bool {
+		return p.Resources[i].line() < p.Resources[j].line()
+	})
+
+	p.Digest = p.ComputeDigest()
+
+	return p
+}
+
+// ReadPlan reads a plan from a file and ensures its digest matches its resources.
+func ReadPlan(path string) (*Plan, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Plan{}
+	if err := json.Unmarshal(raw, p); err != nil {
+		return nil, fmt.Errorf("unable to parse plan %s: %w", path, err)
+	}
+
+	if digest := p.ComputeDigest(); digest != p.Digest {
+		return nil, fmt.Errorf("plan %s has been modified, its digest does not match its resources", path)
+	}
+
+	return p, nil
+}
+
+// ComputeDigest returns the SHA-256 digest of the nonce, the account and the resources of the plan. The requester and
+// the time the plan was created are not part of the digest.
+func (p *Plan) ComputeDigest() string {
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "nonce:%s\n", p.Nonce)
+	_, _ = fmt.Fprintf(h, "account:%s\n", p.AccountID)
+
+	for _, r := range p.Resources {
+		_, _ = fmt.Fprintln(h, r.line())
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Counts returns the number of resources in the plan by resource type.
+func (p *Plan) Counts() map[string]int {
+	counts := make(map[string]int)
+	for _, r := range p.Resources {
+		counts[r.ResourceType]++
+	}
+
+	return counts
+}
+
+// line returns a stable representation of the resource that is used to sort the resources and compute the digest.
+func (r *Resource) line() string {
+	keys := make([]string, 0, len(r.Properties))
+	for key := range r.Properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	properties := make([]string, 0, len(keys))
+	for _, key := range keys {
+		properties = append(properties, fmt.Sprintf("%q=%q", key, r.Properties[key]))
+	}
+
+	return fmt.Sprintf("%q %q %q %s", r.ResourceType, r.Region, r.Name, strings.Join(properties, " "))
+}
@@ -0,0 +1,80 @@
+package approval
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SecretEnv is the environment variable that holds the secret that is shared by the requester and the approvers to
+// sign and verify approval tokens.
+const SecretEnv = "AWS_NUKE_APPROVAL_SECRET"
+
+// ErrNoSecret is returned when an approval token is signed or verified without a secret.
+var ErrNoSecret = fmt.Errorf("a secret is required to sign and verify approval tokens, set %s", SecretEnv)
+
+// ErrInvalidToken is returned when an approval token is malformed or its signature does not match.
+var ErrInvalidToken = errors.New("invalid approval token")
+
+// Token approves the plan with the digest. It is signed with the shared secret.
+type Token struct {
+	Digest   string    `json:"digest"`
+	Approver string    `json:"approver"`
+	IssuedAt time.Time `json:"issuedAt"`
+}
+
+// Sign returns the signed and encoded token.
+func Sign(secret []byte, token *Token) (string, error) {
+	if len(secret) == 0 {
+		return "", ErrNoSecret
+	}
+
+	payload, err := json.Marshal(token)
+	if err != nil {
+		return "", err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+
+	return encoded + "." + signature(secret, encoded), nil
+}
+
+// Verify verifies the signature of the encoded token and returns the token.
+func Verify(secret []byte, raw string) (*Token, error) {
+	if len(secret) == 0 {
+		return nil, ErrNoSecret
+	}
+
+	encoded, sig, ok := strings.Cut(strings.TrimSpace(raw), ".")
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
+	if !hmac.Equal([]byte(sig), []byte(signature(secret, encoded))) {
+		return nil, ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	token := &Token{}
+	if err := json.Unmarshal(payload, token); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	return token, nil
+}
+
+func signature(secret []byte, encoded string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(encoded))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"           //nolint:staticcheck
 	"github.com/aws/aws-sdk-go/aws/endpoints" //nolint:staticcheck
+	"github.com/aws/aws-sdk-go/aws/session"   //nolint:staticcheck
 	"github.com/gotidy/ptr"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -53,9 +54,9 @@ func NewAccount(creds *Credentials, customEndpoints config.CustomEndpoints) (*Ac
 		return nil, errors.Wrapf(err, "failed to create default session in %s", DefaultRegionID)
 	}
 
-	identityOutput, err := sts.New(defaultSession, &aws.Config{STSRegionalEndpoint: endpoints.RegionalSTSEndpoint}).GetCallerIdentity(nil)
+	identityOutput, err := callerIdentity(defaultSession)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed get caller identity")
+		return nil, err
 	}
 
 	regionsOutput, err := ec2.New(defaultSession).DescribeRegions(&ec2.DescribeRegionsInput{
@@ -105,6 +106,31 @@ func NewAccount(creds *Credentials, customEndpoints config.CustomEndpoints) (*Ac
 	return &account, nil
 }
 
+// CallerARN returns the ARN of the principal of the credentials, without looking up the regions and aliases of the
+// account.
+func CallerARN(creds *Credentials) (string, error) {
+	defaultSession, err := creds.NewSession(DefaultRegionID, "")
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to create default session in %s", DefaultRegionID)
+	}
+
+	identityOutput, err := callerIdentity(defaultSession)
+	if err != nil {
+		return "", err
+	}
+
+	return ptr.ToString(identityOutput.Arn), nil
+}
+
+func callerIdentity(sess *session.Session) (*sts.GetCallerIdentityOutput, error) {
+	identityOutput, err := sts.New(sess, &aws.Config{STSRegionalEndpoint: endpoints.RegionalSTSEndpoint}).GetCallerIdentity(nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed get caller identity")
+	}
+
+	return identityOutput, nil
+}
+
 // ID returns the account ID
 func (a *Account) ID() string {
 	return a.id
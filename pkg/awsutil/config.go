@@ -5,7 +5,9 @@ import (
 	"crypto/tls"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -46,6 +48,7 @@ func (c *Credentials) NewConfig(ctx context.Context, region, serviceType string)
 	}
 
 	var cfg *aws.Config
+	isCustom := false
 	if customRegion := c.CustomEndpoints.GetRegion(region); customRegion != nil {
 		var opts []func(*config.LoadOptions) error
 
@@ -76,6 +79,7 @@ func (c *Credentials) NewConfig(ctx context.Context, region, serviceType string)
 		}
 
 		cfg = &cfgv
+		isCustom = true
 	}
 
 	if cfg == nil {
@@ -86,35 +90,56 @@ func (c *Credentials) NewConfig(ctx context.Context, region, serviceType string)
 
 		cfgCopy := root.Copy()
 		cfgCopy.Region = region
-		if global {
-			cfgCopy.APIOptions = append(cfgCopy.APIOptions, func(stack *middleware.Stack) error {
-				return stack.Initialize.Add(SkipGlobal{}, middleware.After)
-			})
-		} else {
-			cfgCopy.APIOptions = append(cfgCopy.APIOptions, func(stack *middleware.Stack) error {
-				return stack.Initialize.Add(SkipRegionalForGlobalService{}, middleware.After)
-			})
-		}
+		// The API options are cloned, appending to the slice of the root config would race between configs
+		cfgCopy.APIOptions = slices.Clone(root.APIOptions)
 		cfg = &cfgCopy
 	}
 
+	cfg.APIOptions = append(cfg.APIOptions, c.apiOptions(global, isCustom)...)
+
 	return cfg, nil
 }
 
-func (c *Credentials) rootConfig(ctx context.Context) (*aws.Config, error) {
-	if c.cfg != nil {
-		return c.cfg, nil
-	}
-
-	var opts []func(*config.LoadOptions) error
-	opts = append(opts, config.WithAPIOptions([]func(*middleware.Stack) error{
+// apiOptions returns the middleware of the SDK v2 configs. It mirrors the handlers that NewSession adds to the SDK v1
+// sessions, so a resource behaves the same when it is migrated: requests and responses are traced, requests for
+// services that do not exist in the region are skipped unless a custom endpoint is used, and requests are rate limited.
+func (c *Credentials) apiOptions(global, isCustom bool) []func(*middleware.Stack) error {
+	apiOptions := []func(*middleware.Stack) error{
 		func(stack *middleware.Stack) error {
 			return errors.Join(
 				stack.Finalize.Add(traceRequest{}, middleware.After),
 				stack.Deserialize.Add(traceResponse{}, middleware.After),
 			)
 		},
-	}))
+	}
+
+	if !isCustom {
+		var skip middleware.InitializeMiddleware = SkipRegionalForGlobalService{}
+		if global {
+			skip = SkipGlobal{}
+		}
+
+		apiOptions = append(apiOptions, func(stack *middleware.Stack) error {
+			return errors.Join(
+				stack.Initialize.Add(skip, middleware.After),
+				stack.Initialize.Add(SkipUnknownEndpoint{}, middleware.After),
+			)
+		})
+	}
+
+	if c.RateLimiter != nil {
+		apiOptions = append(apiOptions, c.RateLimiter.APIOption())
+	}
+
+	return apiOptions
+}
+
+func (c *Credentials) rootConfig(ctx context.Context) (*aws.Config, error) {
+	if c.cfg != nil {
+		return c.cfg, nil
+	}
+
+	var opts []func(*config.LoadOptions) error
 
 	region := DefaultRegionID
 	log.Debugf("creating new root session in %s", region)
@@ -225,6 +250,32 @@ func (SkipRegionalForGlobalService) HandleInitialize(
 	return next.HandleInitialize(ctx, in)
 }
 
+// SkipUnknownEndpoint skips requests whose endpoint does not resolve, the
+// service is assumed to not exist in the region. It is the equivalent of the
+// DNS lookup the SDK v1 sessions do for services missing in the endpoints list.
+type SkipUnknownEndpoint struct{}
+
+func (SkipUnknownEndpoint) ID() string {
+	return "aws-nuke::skipUnknownEndpoint"
+}
+
+func (SkipUnknownEndpoint) HandleInitialize(
+	ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler,
+) (
+	middleware.InitializeOutput, middleware.Metadata, error,
+) {
+	out, md, err := next.HandleInitialize(ctx, in)
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+		log.Debug(err)
+		return out, md, liberrors.ErrUnknownEndpoint(
+			fmt.Sprintf("DNS lookup failed for %s; assuming it does not exist in this region", dnsErr.Name))
+	}
+
+	return out, md, err
+}
+
 type traceRequest struct{}
 
 func (traceRequest) ID() string {
@@ -0,0 +1,94 @@
+package awsutil
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/smithy-go/middleware"
+
+	liberrors "github.com/ekristen/libnuke/pkg/errors"
+
+	"github.com/ekristen/aws-nuke/v3/pkg/config"
+)
+
+func TestSkipUnknownEndpoint(t *testing.T) {
+	cases := []struct {
+		name    string
+		err     error
+		unknown bool
+	}{
+		{name: "not-found", err: &net.DNSError{Name: "svc.eu-west-1.amazonaws.com", IsNotFound: true}, unknown: true},
+		{name: "timeout", err: &net.DNSError{Name: "svc.eu-west-1.amazonaws.com", IsTimeout: true}},
+		{name: "other", err: errors.New("access denied")},
+		{name: "none"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			next := middleware.InitializeHandlerFunc(
+				func(context.Context, middleware.InitializeInput) (middleware.InitializeOutput, middleware.Metadata, error) {
+					return middleware.InitializeOutput{}, middleware.Metadata{}, tc.err
+				})
+
+			_, _, err := SkipUnknownEndpoint{}.HandleInitialize(context.TODO(), middleware.InitializeInput{}, next)
+
+			var errUnknownEndpoint liberrors.ErrUnknownEndpoint
+			assert.Equal(t, tc.unknown, errors.As(err, &errUnknownEndpoint))
+			if !tc.unknown {
+				assert.Equal(t, tc.err, err)
+			}
+		})
+	}
+}
+
+func TestNewConfig_CustomEndpointTrace(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/xml")
+		_, _ = w.Write([]byte(`<GetCallerIdentityResponse><GetCallerIdentityResult><Account>000000000000</Account>` +
+			`</GetCallerIdentityResult></GetCallerIdentityResponse>`))
+	}))
+	defer server.Close()
+
+	hook := test.NewGlobal()
+	defer hook.Reset()
+
+	level := logrus.GetLevel()
+	logrus.SetLevel(logrus.TraceLevel)
+	defer logrus.SetLevel(level)
+
+	creds := &Credentials{
+		AccessKeyID:     "id",
+		SecretAccessKey: "secret",
+		CustomEndpoints: config.CustomEndpoints{
+			{Region: "eu-west-1", Services: config.CustomServices{{Service: "sts", URL: server.URL}}},
+		},
+	}
+
+	cfg, err := creds.NewConfig(context.TODO(), "eu-west-1", "sts")
+	require.NoError(t, err)
+
+	out, err := sts.NewFromConfig(*cfg).GetCallerIdentity(context.TODO(), &sts.GetCallerIdentityInput{})
+	require.NoError(t, err)
+	assert.Equal(t, "000000000000", aws.ToString(out.Account))
+
+	var messages []string
+	for _, entry := range hook.AllEntries() {
+		if entry.Level == logrus.TraceLevel {
+			messages = append(messages, strings.SplitN(entry.Message, "\n", 2)[0])
+		}
+	}
+
+	assert.Equal(t, []string{"sending AWS request:", "received AWS response:"}, messages)
+}
@@ -25,6 +25,7 @@ import (
 	liberrors "github.com/ekristen/libnuke/pkg/errors"
 
 	"github.com/ekristen/aws-nuke/v3/pkg/config"
+	"github.com/ekristen/aws-nuke/v3/pkg/throttle"
 )
 
 const (
@@ -52,8 +53,12 @@ type Credentials struct {
 	Credentials *credentials.Credentials
 
 	CustomEndpoints config.CustomEndpoints
-	session         *session.Session
-	cfg             *awsv2.Config
+
+	// RateLimiter limits the requests of all sessions and configs, it is optional.
+	RateLimiter *throttle.Limiter
+
+	session *session.Session
+	cfg     *awsv2.Config
 }
 
 func (c *Credentials) HasProfile() bool {
@@ -228,6 +233,11 @@ func (c *Credentials) NewSession(region, serviceType string) (*session.Session,
 		sess.Handlers.Validate.PushFront(skipMissingServiceInRegionHandler)
 		sess.Handlers.Validate.PushFront(skipGlobalHandler(global))
 	}
+
+	if c.RateLimiter != nil {
+		c.RateLimiter.InstrumentSession(sess)
+	}
+
 	return sess, nil
 }
 
@@ -0,0 +1,237 @@
+// Package backup provides the helpers that resources use to back up their data before they are removed, such as the
+// naming of final snapshots and an encrypted local archive for values like parameters and secrets.
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PassphraseEnv is the environment variable that holds the passphrase used to encrypt the archive.
+const PassphraseEnv = "AWS_NUKE_BACKUP_PASSPHRASE"
+
+// DefaultArchivePath is the path of the archive when a resource does not configure one.
+const DefaultArchivePath = "aws-nuke-backup.archive"
+
+const (
+	archiveHeader     = "aws-nuke-backup v1"
+	archiveIterations = 600000
+	archiveKeyLength  = 32
+	archiveSaltLength = 16
+)
+
+// ErrNoPassphrase is returned when a record is appended to an archive without a passphrase.
+var ErrNoPassphrase = fmt.Errorf("a passphrase is required to encrypt the backup archive, set %s", PassphraseEnv)
+
+// Record is a single value that was backed up before the resource it belongs to was removed.
+type Record struct {
+	// ResourceType is the aws-nuke resource type, for example SSMParameter
+	ResourceType string `json:"resourceType"`
+
+	// Region is the region the resource was found in
+	Region string `json:"region,omitempty"`
+
+	// Name is the name or identifier of the resource
+	Name string `json:"name"`
+
+	// Attributes are additional details needed to recreate the resource, for example the type of parameter
+	Attributes map[string]string `json:"attributes,omitempty"`
+
+	// Value is the value of the resource, for example the value of a parameter or secret
+	Value []byte `json:"value"`
+
+	// CreatedAt is the time the record was created
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+var (
+	archiveLock sync.Mutex
+	archiveKeys = make(map[string][]byte)
+)
+
+// Append encrypts the record with the passphrase from the environment and appends it to the archive at the path.
+func Append(path string, record *Record) error {
+	return AppendWithPassphrase(path, os.Getenv(PassphraseEnv), record)
+}
+
+// AppendWithPassphrase encrypts the record with the passphrase and appends it to the archive at the path. The archive
+// is created if it does not exist. Each record is encrypted separately so the archive can be appended to safely by
+// multiple resources during a run.
+func AppendWithPassphrase(path, passphrase string, record *Record) error {
+	if passphrase == "" {
+		return ErrNoPassphrase
+	}
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = time.Now().UTC()
+	}
+
+	plaintext, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	archiveLock.Lock()
+	defer archiveLock.Unlock()
+
+	salt, err := archiveSalt(path)
+	if err != nil {
+		return err
+	}
+
+	gcm, err := archiveCipher(passphrase, salt)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	line := base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, plaintext, nil))
+	if _, err := f.WriteString(line + "\n"); err != nil {
+		return err
+	}
+
+	return f.Sync()
+}
+
+// Read decrypts all records of the archive at the path with the passphrase.
+func Read(path, passphrase string) ([]*Record, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(raw))
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+
+	if !scanner.Scan() {
+		return nil, fmt.Errorf("backup archive is empty")
+	}
+
+	salt, err := parseArchiveHeader(scanner.Text())
+	if err != nil {
+		return nil, err
+	}
+
+	archiveLock.Lock()
+	gcm, err := archiveCipher(passphrase, salt)
+	archiveLock.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]*Record, 0)
+	for line := 2; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+
+		ciphertext, err := base64.StdEncoding.DecodeString(scanner.Text())
+		if err != nil {
+			return nil, fmt.Errorf("backup archive line %d: %w", line, err)
+		}
+
+		if len(ciphertext) < gcm.NonceSize() {
+			return nil, fmt.Errorf("backup archive line %d: record is too short", line)
+		}
+
+		nonce, ciphertext := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+		plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+		if err != nil {
+			return nil, fmt.Errorf("backup archive line %d: unable to decrypt, wrong passphrase?", line)
+		}
+
+		record := &Record{}
+		if err := json.Unmarshal(plaintext, record); err != nil {
+			return nil, fmt.Errorf("backup archive line %d: %w", line, err)
+		}
+
+		records = append(records, record)
+	}
+
+	return records, scanner.Err()
+}
+
+// archiveSalt returns the salt of the archive at the path, creating the archive with a new salt if it does not exist.
+func archiveSalt(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		salt := make([]byte, archiveSaltLength)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, err
+		}
+
+		header := fmt.Sprintf("%s %s\n", archiveHeader, base64.StdEncoding.EncodeToString(salt))
+		if err := os.WriteFile(path, []byte(header), 0o600); err != nil {
+			return nil, err
+		}
+
+		return salt, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	header, err := bufio.NewReader(f).ReadString('\n')
+	if err != nil {
+		return nil, fmt.Errorf("unable to read backup archive header: %w", err)
+	}
+
+	return parseArchiveHeader(strings.TrimSpace(header))
+}
+
+func parseArchiveHeader(header string) ([]byte, error) {
+	encoded, ok := strings.CutPrefix(header, archiveHeader+" ")
+	if !ok {
+		return nil, fmt.Errorf("not a backup archive")
+	}
+
+	return base64.StdEncoding.DecodeString(encoded)
+}
+
+// archiveCipher derives the key from the passphrase and salt and returns the cipher. Deriving the key is slow on
+// purpose, so the keys are cached for the duration of the process. The caller must hold the archive lock.
+func archiveCipher(passphrase string, salt []byte) (cipher.AEAD, error) {
+	cacheKey := fmt.Sprintf("%x:%x", sha256.Sum256([]byte(passphrase)), salt)
+
+	key, ok := archiveKeys[cacheKey]
+	if !ok {
+		var err error
+		key, err = pbkdf2.Key(sha256.New, passphrase, salt, archiveIterations, archiveKeyLength)
+		if err != nil {
+			return nil, err
+		}
+
+		archiveKeys[cacheKey] = key
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,128 @@
+package backup
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	libsettings "github.com/ekristen/libnuke/pkg/settings"
+)
+
+func TestSnapshotIdentifier(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 30, 45, 0, time.UTC)
+
+	cases := []struct {
+		name string
+		id   string
+		want string
+	}{
+		{name: "simple", id: "database-1", want: "aws-nuke-final-database-1-20240301123045"},
+		{name: "invalid-characters", id: "my_db.cluster", want: "aws-nuke-final-my-db-cluster-20240301123045"},
+		{name: "repeated-hyphens", id: "db--1-", want: "aws-nuke-final-db-1-20240301123045"},
+		{name: "empty", id: "", want: "aws-nuke-final-20240301123045"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, SnapshotIdentifier(tc.id, now))
+		})
+	}
+
+	long := SnapshotIdentifier(strings.Repeat("a", 300), now)
+	assert.Len(t, long, maxSnapshotIdentifierLength)
+	assert.True(t, strings.HasSuffix(long, "-20240301123045"))
+}
+
+func TestIsFinalSnapshot(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 30, 45, 0, time.UTC)
+
+	assert.True(t, IsFinalSnapshot(SnapshotIdentifier("database-1", now)))
+	assert.True(t, IsFinalSnapshot("AWS-NUKE-FINAL-database-1-20240301123045"))
+	assert.False(t, IsFinalSnapshot("database-1-final"))
+	assert.False(t, IsFinalSnapshot("aws-nuke-finale"))
+}
+
+func TestFilterFinalSnapshot(t *testing.T) {
+	assert.NoError(t, FilterFinalSnapshot(false, nil))
+	assert.ErrorIs(t, FilterFinalSnapshot(true, nil), ErrFinalSnapshot)
+	assert.ErrorIs(t, FilterFinalSnapshot(true, &libsettings.Setting{}), ErrFinalSnapshot)
+
+	// The protection is lifted by the setting
+	setting := &libsettings.Setting{}
+	setting.Set(IncludeFinalSnapshotsSetting, true)
+	assert.NoError(t, FilterFinalSnapshot(true, setting))
+}
+
+func TestArchive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "backup.archive")
+
+	assert.ErrorIs(t, AppendWithPassphrase(path, "", &Record{}), ErrNoPassphrase)
+
+	first := &Record{
+		ResourceType: "SSMParameter",
+		Region:       "us-east-1",
+		Name:         "/app/password",
+		Attributes:   map[string]string{"Type": "SecureString"},
+		Value:        []byte("hunter2"),
+	}
+	second := &Record{
+		ResourceType: "SecretsManagerSecret",
+		Region:       "us-east-1",
+		Name:         "app-secret",
+		Value:        []byte(`{"user":"admin"}`),
+	}
+
+	assert.NoError(t, AppendWithPassphrase(path, "correct horse", first))
+	assert.NoError(t, AppendWithPassphrase(path, "correct horse", second))
+
+	raw, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(raw), "hunter2")
+	assert.True(t, strings.HasPrefix(string(raw), archiveHeader))
+
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	records, err := Read(path, "correct horse")
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.Equal(t, "/app/password", records[0].Name)
+	assert.Equal(t, []byte("hunter2"), records[0].Value)
+	assert.Equal(t, map[string]string{"Type": "SecureString"}, records[0].Attributes)
+	assert.False(t, records[0].CreatedAt.IsZero())
+	assert.Equal(t, "app-secret", records[1].Name)
+
+	_, err = Read(path, "wrong passphrase")
+	assert.ErrorContains(t, err, "unable to decrypt")
+}
+
+func TestArchive_NotAnArchive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "other.txt")
+	assert.NoError(t, os.WriteFile(path, []byte("hello\n"), 0o600))
+
+	assert.Error(t, AppendWithPassphrase(path, "passphrase", &Record{}))
+
+	_, err := Read(path, "passphrase")
+	assert.Error(t, err)
+}
+
+func TestFindRecord(t *testing.T) {
+	older := &Record{ResourceType: "SSMParameter", Region: "us-east-1", Name: "/app/secret", Value: []byte("old"),
+		CreatedAt: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)}
+	newer := &Record{ResourceType: "SSMParameter", Region: "us-east-1", Name: "/app/secret", Value: []byte("new"),
+		CreatedAt: time.Date(2024, 3, 2, 12, 0, 0, 0, time.UTC)}
+	other := &Record{ResourceType: "SSMParameter", Region: "eu-west-1", Name: "/app/secret", Value: []byte("other")}
+
+	assert.Nil(t, FindRecord(context.TODO(), "SSMParameter", "us-east-1", "/app/secret"))
+
+	ctx := WithRecords(context.TODO(), []*Record{newer, other, older})
+	assert.Equal(t, newer, FindRecord(ctx, "SSMParameter", "us-east-1", "/app/secret"))
+	assert.Equal(t, other, FindRecord(ctx, "SSMParameter", "eu-west-1", "/app/secret"))
+	assert.Nil(t, FindRecord(ctx, "SecretsManagerSecret", "us-east-1", "/app/secret"))
+}
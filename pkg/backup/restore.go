@@ -0,0 +1,32 @@
+package backup
+
+import (
+	"context"
+)
+
+type recordsKey struct{}
+
+// WithRecords returns a context that holds the records of a backup archive, restorers look up the values that are not
+// recorded in the undo log in them with FindRecord.
+func WithRecords(ctx context.Context, records []*Record) context.Context {
+	return context.WithValue(ctx, recordsKey{}, records)
+}
+
+// FindRecord returns the most recent record of the resource in the records of the context, or nil when the context
+// has no records or none of them belongs to the resource.
+func FindRecord(ctx context.Context, resourceType, region, name string) *Record {
+	records, _ := ctx.Value(recordsKey{}).([]*Record)
+
+	var found *Record
+	for _, record := range records {
+		if record.ResourceType != resourceType || record.Region != region || record.Name != name {
+			continue
+		}
+
+		if found == nil || !record.CreatedAt.Before(found.CreatedAt) {
+			found = record
+		}
+	}
+
+	return found
+}
@@ -0,0 +1,70 @@
+package backup
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	libsettings "github.com/ekristen/libnuke/pkg/settings"
+)
+
+// SnapshotPrefix is the prefix of every snapshot that is created before a resource is removed.
+const SnapshotPrefix = "aws-nuke-final"
+
+// SourceVolumeTag is the tag of an EBS snapshot that is created before a volume is removed, its value is the ID of the
+// volume.
+const SourceVolumeTag = "aws-nuke:source-volume"
+
+// ErrFinalSnapshot is the reason final snapshots are filtered, a later run must not remove the backups of an earlier
+// one.
+var ErrFinalSnapshot = errors.New("final snapshot created by aws-nuke before a resource was removed")
+
+// IncludeFinalSnapshotsSetting is the setting of the snapshot resource types that removes final snapshots like any
+// other snapshot, once the backups of earlier runs are no longer needed.
+const IncludeFinalSnapshotsSetting = "IncludeFinalSnapshots"
+
+// maxSnapshotIdentifierLength is the shortest maximum length of a snapshot identifier across RDS, DocumentDB and
+// Neptune.
+const maxSnapshotIdentifierLength = 255
+
+var invalidSnapshotCharacters = regexp.MustCompile(`[^a-zA-Z0-9-]+`)
+var repeatedHyphens = regexp.MustCompile(`-{2,}`)
+
+// SnapshotIdentifier returns the identifier for a final snapshot of a resource. The identifier only contains letters,
+// digits and single hyphens, starts with a letter and ends with the time of the snapshot so it is unique per run.
+func SnapshotIdentifier(id string, now time.Time) string {
+	suffix := now.UTC().Format("20060102150405")
+
+	id = invalidSnapshotCharacters.ReplaceAllString(id, "-")
+	id = repeatedHyphens.ReplaceAllString(id, "-")
+	id = strings.Trim(id, "-")
+
+	maxLength := maxSnapshotIdentifierLength - len(SnapshotPrefix) - len(suffix) - 2
+	if len(id) > maxLength {
+		id = strings.TrimRight(id[:maxLength], "-")
+	}
+
+	if id == "" {
+		return fmt.Sprintf("%s-%s", SnapshotPrefix, suffix)
+	}
+
+	return fmt.Sprintf("%s-%s-%s", SnapshotPrefix, id, suffix)
+}
+
+// IsFinalSnapshot returns true if the identifier is the identifier of a final snapshot. RDS stores identifiers in lower
+// case, they are compared regardless of case.
+func IsFinalSnapshot(identifier string) bool {
+	return strings.HasPrefix(strings.ToLower(identifier), SnapshotPrefix+"-")
+}
+
+// FilterFinalSnapshot returns ErrFinalSnapshot for a final snapshot, unless the settings of its resource type include
+// final snapshots.
+func FilterFinalSnapshot(final bool, setting *libsettings.Setting) error {
+	if !final || (setting != nil && setting.GetBool(IncludeFinalSnapshotsSetting)) {
+		return nil
+	}
+
+	return ErrFinalSnapshot
+}
@@ -0,0 +1,181 @@
+package approve
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v3"
+
+	"github.com/aws/aws-sdk-go/aws/endpoints" //nolint:staticcheck
+
+	"github.com/ekristen/aws-nuke/v3/pkg/approval"
+	"github.com/ekristen/aws-nuke/v3/pkg/awsutil"
+	"github.com/ekristen/aws-nuke/v3/pkg/commands/global"
+	nukecmd "github.com/ekristen/aws-nuke/v3/pkg/commands/nuke"
+	"github.com/ekristen/aws-nuke/v3/pkg/common"
+)
+
+func execute(_ context.Context, c *cli.Command) error {
+	plan, err := approval.ReadPlan(c.String("plan"))
+	if err != nil {
+		return err
+	}
+
+	// The approver is the principal of the credentials, like the requester is for the run
+	approver, err := callerARN(c)
+	if err != nil {
+		return err
+	}
+
+	if strings.EqualFold(approver, strings.TrimSpace(plan.Requester)) {
+		return fmt.Errorf("the plan was requested by %s, it must be approved by a different principal", plan.Requester)
+	}
+
+	account := plan.AccountID
+	if plan.AccountAlias != "" {
+		account = fmt.Sprintf("%s (%s)", plan.AccountAlias, plan.AccountID)
+	}
+
+	logrus.Infof("Plan %s requested by %s at %s", plan.Digest, plan.Requester, plan.CreatedAt.Format(time.RFC3339))
+	logrus.Infof("The following resources would be removed from the account %s (%d total):", account, len(plan.Resources))
+
+	counts := plan.Counts()
+	for _, resourceType := range slices.Sorted(maps.Keys(counts)) {
+		logrus.Infof("> %s: %d", resourceType, counts[resourceType])
+	}
+
+	token, err := approval.Sign([]byte(os.Getenv(approval.SecretEnv)), &approval.Token{
+		Digest:   plan.Digest,
+		Approver: approver,
+		IssuedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	output := c.String("output")
+	if output == "" {
+		output = approval.TokenFile(filepath.Dir(c.String("plan")), plan.Digest)
+	}
+
+	if output == "-" {
+		fmt.Println(token)
+		return nil
+	}
+
+	// The token is written to a temporary file first so a waiting run never reads a partially written token
+	tmp := output + ".tmp"
+	if err := os.WriteFile(tmp, []byte(token+"\n"), 0600); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp, output); err != nil {
+		return err
+	}
+
+	logrus.Infof("approval token written to %s", output)
+
+	return nil
+}
+
+// callerARN returns the ARN of the principal of the credentials the command is run with.
+func callerARN(c *cli.Command) (string, error) {
+	creds := nukecmd.ConfigureCreds(c)
+	if err := creds.Validate(); err != nil {
+		return "", common.NewExitError(common.ExitCodeConfig, err)
+	}
+
+	if defaultRegion := c.String("default-region"); defaultRegion != "" {
+		partition, ok := endpoints.PartitionForRegion(endpoints.DefaultPartitions(), defaultRegion)
+		if !ok {
+			return "", common.NewExitError(common.ExitCodeConfig,
+				fmt.Errorf("unable to determine the partition of the region '%s'", defaultRegion))
+		}
+
+		awsutil.DefaultRegionID = defaultRegion
+		awsutil.DefaultAWSPartitionID = partition.ID()
+	}
+
+	arn, err := awsutil.CallerARN(creds)
+	if err != nil {
+		return "", common.NewExitError(common.ExitCodeAuth, err)
+	}
+
+	return arn, nil
+}
+
+func init() { //nolint:funlen
+	flags := []cli.Flag{
+		&cli.StringFlag{
+			Name:     "plan",
+			Usage:    "path to the plan file to approve",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:    "output",
+			Aliases: []string{"o"},
+			Usage:   "path to write the approval token to, defaults to next to the plan, use - to print it",
+		},
+		&cli.StringFlag{
+			Name:    "default-region",
+			Sources: cli.EnvVars("AWS_DEFAULT_REGION"),
+			Usage:   "the default aws region to use when setting up the aws auth session",
+		},
+		&cli.StringFlag{
+			Name:    "access-key-id",
+			Sources: cli.EnvVars("AWS_ACCESS_KEY_ID"),
+			Usage:   "the aws access key id to use when setting up the aws auth session",
+		},
+		&cli.StringFlag{
+			Name:    "secret-access-key",
+			Sources: cli.EnvVars("AWS_SECRET_ACCESS_KEY"),
+			Usage:   "the aws secret access key to use when setting up the aws auth session",
+		},
+		&cli.StringFlag{
+			Name:    "session-token",
+			Sources: cli.EnvVars("AWS_SESSION_TOKEN"),
+			Usage:   "the aws session token to use when setting up the aws auth session, typically used for temporary credentials",
+		},
+		&cli.StringFlag{
+			Name:    "profile",
+			Sources: cli.EnvVars("AWS_PROFILE"),
+			Usage:   "the aws profile to use when setting up the aws auth session, typically used for shared credentials files",
+		},
+		&cli.StringFlag{
+			Name:    "assume-role-arn",
+			Sources: cli.EnvVars("AWS_ASSUME_ROLE_ARN"),
+			Usage:   "the role arn to assume using the credentials provided in the profile or statically set",
+		},
+		&cli.StringFlag{
+			Name:    "assume-role-session-name",
+			Sources: cli.EnvVars("AWS_ASSUME_ROLE_SESSION_NAME"),
+			Usage:   "the session name to provide for the assumed role",
+		},
+		&cli.StringFlag{
+			Name:    "assume-role-external-id",
+			Sources: cli.EnvVars("AWS_ASSUME_ROLE_EXTERNAL_ID"),
+			Usage:   "the external id to provide for the assumed role",
+		},
+	}
+
+	cmd := &cli.Command{
+		Name:  "approve",
+		Usage: "approve the plan of a run that is waiting for a second approver",
+		Description: `approve signs an approval token for a plan that was published by a run with an approval configured.
+The token is signed with the secret shared in the ` + approval.SecretEnv + ` environment variable and is written next to
+the plan, where the waiting run picks it up. The approver is the principal of the AWS credentials the command is run
+with, a plan cannot be approved by the principal that requested it.`,
+		Flags:  append(flags, global.Flags()...),
+		Before: global.Before,
+		Action: execute,
+	}
+
+	common.RegisterCommand(cmd)
+}
@@ -20,31 +20,14 @@ import (
 )
 
 func execute(_ context.Context, c *cli.Command) error { //nolint:funlen,gocyclo
-	accountID := c.String("account-id")
-
-	parsedConfig, err := config.New(libconfig.Options{
-		Path:         c.String("config"),
-		Deprecations: registry.GetDeprecatedResourceTypeMapping(),
-	})
+	parsedConfig, err := loadConfig(c)
 	if err != nil {
-		logrus.Errorf("Failed to parse config file %s", c.String("config"))
 		return err
 	}
 
-	if accountID == "" {
-		logrus.Info("no account id provided, attempting to authenticate and get account id")
-		creds := nuke.ConfigureCreds(c)
-		if err := creds.Validate(); err != nil {
-			return err
-		}
-
-		// Create the AWS Account object. This will be used to get the account ID and aliases for the account.
-		account, err := awsutil.NewAccount(creds, parsedConfig.CustomEndpoints)
-		if err != nil {
-			return err
-		}
-
-		accountID = account.ID()
+	accountID, err := resolveAccountID(c, parsedConfig)
+	if err != nil {
+		return err
 	}
 
 	// Get any specific account level configuration
@@ -54,27 +37,7 @@ func execute(_ context.Context, c *cli.Command) error { //nolint:funlen,gocyclo
 		return fmt.Errorf("account %s is not configured in the config file", accountID)
 	}
 
-	// Resolve the resource types to be used for the nuke process based on the parameters, global configuration, and
-	// account level configuration.
-	resourceTypes := types.ResolveResourceTypes(
-		registry.GetNames(),
-		[]types.Collection{
-			{}, // note: empty collection since we are not capturing parameters
-			parsedConfig.ResourceTypes.GetIncludes(),
-			accountConfig.ResourceTypes.GetIncludes(),
-		},
-		[]types.Collection{
-			{}, // note: empty collection since we are not capturing parameters
-			parsedConfig.ResourceTypes.Excludes,
-			accountConfig.ResourceTypes.Excludes,
-		},
-		[]types.Collection{
-			{}, // note: empty collection since we are not capturing parameters
-			parsedConfig.ResourceTypes.GetAlternatives(),
-			accountConfig.ResourceTypes.GetAlternatives(),
-		},
-		registry.GetAlternativeResourceTypeMapping(),
-	)
+	resourceTypes := resolveResourceTypes(parsedConfig, accountConfig)
 
 	filtersTotal := 0
 	var resourcesWithFilters []string
@@ -140,8 +103,70 @@ func execute(_ context.Context, c *cli.Command) error { //nolint:funlen,gocyclo
 	return nil
 }
 
-func init() {
-	flags := []cli.Flag{
+// loadConfig parses the configuration file that was provided on the command line.
+func loadConfig(c *cli.Command) (*config.Config, error) {
+	parsedConfig, err := config.New(libconfig.Options{
+		Path:         c.String("config"),
+		Deprecations: registry.GetDeprecatedResourceTypeMapping(),
+	})
+	if err != nil {
+		logrus.Errorf("Failed to parse config file %s", c.String("config"))
+		return nil, err
+	}
+
+	return parsedConfig, nil
+}
+
+// resolveAccountID returns the account ID provided on the command line, or authenticates against AWS to determine
+// the account ID if none was provided.
+func resolveAccountID(c *cli.Command, parsedConfig *config.Config) (string, error) {
+	accountID := c.String("account-id")
+	if accountID != "" {
+		return accountID, nil
+	}
+
+	logrus.Info("no account id provided, attempting to authenticate and get account id")
+	creds := nuke.ConfigureCreds(c)
+	if err := creds.Validate(); err != nil {
+		return "", err
+	}
+
+	// Create the AWS Account object. This will be used to get the account ID and aliases for the account.
+	account, err := awsutil.NewAccount(creds, parsedConfig.CustomEndpoints)
+	if err != nil {
+		return "", err
+	}
+
+	return account.ID(), nil
+}
+
+// resolveResourceTypes resolves the resource types to be used for the nuke process based on the global configuration,
+// and account level configuration.
+func resolveResourceTypes(parsedConfig *config.Config, accountConfig *libconfig.Account) types.Collection {
+	return types.ResolveResourceTypes(
+		registry.GetNames(),
+		[]types.Collection{
+			{}, // note: empty collection since we are not capturing parameters
+			parsedConfig.ResourceTypes.GetIncludes(),
+			accountConfig.ResourceTypes.GetIncludes(),
+		},
+		[]types.Collection{
+			{}, // note: empty collection since we are not capturing parameters
+			parsedConfig.ResourceTypes.Excludes,
+			accountConfig.ResourceTypes.Excludes,
+		},
+		[]types.Collection{
+			{}, // note: empty collection since we are not capturing parameters
+			parsedConfig.ResourceTypes.GetAlternatives(),
+			accountConfig.ResourceTypes.GetAlternatives(),
+		},
+		registry.GetAlternativeResourceTypeMapping(),
+	)
+}
+
+// commonFlags returns the flags shared by all the commands that evaluate a configuration file for an account.
+func commonFlags() []cli.Flag {
+	return []cli.Flag{
 		&cli.StringFlag{
 			Name:    "config",
 			Aliases: []string{"c"},
@@ -153,18 +178,6 @@ func init() {
 			Name:  "account-id",
 			Usage: `the account id to check against the configuration file, if empty, it will use whatever account can be authenticated against`,
 		},
-		&cli.BoolFlag{
-			Name:  "with-filtered",
-			Usage: "print out resource types that have filters defined against them",
-		},
-		&cli.BoolFlag{
-			Name:  "with-included",
-			Usage: "print out the included resource types",
-		},
-		&cli.BoolFlag{
-			Name:  "with-excluded",
-			Usage: "print out the excluded resource types",
-		},
 		&cli.StringFlag{
 			Name:    "default-region",
 			Sources: cli.EnvVars("AWS_DEFAULT_REGION"),
@@ -206,6 +219,23 @@ func init() {
 			Usage:   "the external id to provide for the assumed role",
 		},
 	}
+}
+
+func init() {
+	flags := []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "with-filtered",
+			Usage: "print out resource types that have filters defined against them",
+		},
+		&cli.BoolFlag{
+			Name:  "with-included",
+			Usage: "print out the included resource types",
+		},
+		&cli.BoolFlag{
+			Name:  "with-excluded",
+			Usage: "print out the excluded resource types",
+		},
+	}
 
 	cmd := &cli.Command{
 		Name:  "explain-config",
@@ -214,10 +244,21 @@ func init() {
 is defined within the configuration. You may either specific an account using the --account-id flag or
 leave it empty to use the default account that can be authenticated against. You can optionally list out included,
 excluded and resources with filters with their respective with flags.`,
-		Flags:  append(flags, global.Flags()...),
+		Flags:  append(append(commonFlags(), flags...), global.Flags()...),
 		Before: global.Before,
 		Action: execute,
 	}
 
 	common.RegisterCommand(cmd)
+
+	configCmd := &cli.Command{
+		Name:  "config",
+		Usage: "inspect the configuration file and how it applies to an account",
+		Commands: []*cli.Command{
+			explainCommand(),
+			migrateCommand(),
+		},
+	}
+
+	common.RegisterCommand(configCmd)
 }
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/ekristen/libnuke/pkg/registry"
+
+	"github.com/ekristen/aws-nuke/v3/pkg/commands/global"
+	"github.com/ekristen/aws-nuke/v3/pkg/common"
+	"github.com/ekristen/aws-nuke/v3/pkg/inventory"
+)
+
+func executeExplain(_ context.Context, c *cli.Command) error {
+	parsedConfig, err := loadConfig(c)
+	if err != nil {
+		return err
+	}
+
+	accountID, err := resolveAccountID(c, parsedConfig)
+	if err != nil {
+		return err
+	}
+
+	accountConfig := parsedConfig.Accounts[accountID]
+	if accountConfig == nil {
+		return fmt.Errorf("account %s is not configured in the config file", accountID)
+	}
+
+	resources, err := inventory.Load(c.String("inventory"))
+	if err != nil {
+		return err
+	}
+
+	resourceTypes := resolveResourceTypes(parsedConfig, accountConfig)
+	useFilterGroups := slices.Contains(c.StringSlice("feature-flag"), "filter-groups")
+
+	fmt.Printf("Filter Explanation\n\n")
+	fmt.Printf("Account ID:       %s\n", accountID)
+	fmt.Printf("Filter Presets:   %d\n", len(accountConfig.Presets))
+	fmt.Printf("Filter Groups:    %t\n", useFilterGroups)
+	fmt.Printf("Resources:        %d\n", len(resources))
+	fmt.Println("")
+
+	for _, r := range resources {
+		fmt.Printf("%s - %s - %s\n", r.Region, r.Type, r.Name)
+
+		if registry.GetRegistration(r.Type) == nil {
+			fmt.Printf("  > unknown resource type\n\n")
+			continue
+		}
+
+		if !slices.Contains(resourceTypes, r.Type) {
+			fmt.Printf("  > excluded by resource-types\n\n")
+			continue
+		}
+
+		explanation, err := parsedConfig.ExplainFilters(accountID, r.Type, r, useFilterGroups)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case len(explanation.Filters) == 0:
+			fmt.Printf("  > would remove (no filters defined)\n")
+		case explanation.Filtered:
+			fmt.Printf("  > filtered by config\n")
+		default:
+			fmt.Printf("  > would remove (no filters matched)\n")
+		}
+
+		for _, e := range explanation.Filters {
+			result := "no match"
+			if e.Matched {
+				result = "matched"
+			} else if e.Err != nil {
+				result = "error"
+			}
+
+			fmt.Printf("    [%-8s] %s: %s\n", result, e, e.Reason())
+		}
+
+		for _, group := range explanation.GroupNames() {
+			fmt.Printf("    group %s matched: %t\n", group, explanation.Groups[group])
+		}
+
+		fmt.Println("")
+	}
+
+	fmt.Printf("Note: resource specific built-in filters (e.g. default or AWS managed resources) " +
+		"are not evaluated against an inventory\n")
+
+	return nil
+}
+
+func explainCommand() *cli.Command {
+	flags := []cli.Flag{
+		&cli.StringFlag{
+			Name:     "inventory",
+			Usage:    "path to a json inventory snapshot of resources to explain the filters against",
+			Required: true,
+			Action:   common.CheckFilePath,
+		},
+		&cli.StringSliceFlag{
+			Name:  "feature-flag",
+			Usage: "enable experimental behaviors that may not be fully tested or supported",
+		},
+	}
+
+	return &cli.Command{
+		Name:  "explain",
+		Usage: "explain which filters, presets and filter groups match each resource in an inventory snapshot",
+		Description: `explain evaluates every filter configured for an account against the resources in an inventory
+snapshot without querying AWS. For each resource it prints which filters matched or did not match and why. The
+inventory is a json list of objects with the keys type, region, name and properties.`,
+		Flags:  append(append(commonFlags(), flags...), global.Flags()...),
+		Before: global.Before,
+		Action: executeExplain,
+	}
+}
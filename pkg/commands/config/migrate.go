@@ -0,0 +1,94 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v3"
+
+	"github.com/ekristen/libnuke/pkg/registry"
+
+	"github.com/ekristen/aws-nuke/v3/pkg/commands/global"
+	"github.com/ekristen/aws-nuke/v3/pkg/config"
+)
+
+func executeMigrate(_ context.Context, c *cli.Command) error {
+	if c.Args().Len() != 1 {
+		return fmt.Errorf("exactly one configuration file must be provided")
+	}
+
+	path := c.Args().First()
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	migration, err := config.Migrate(raw, registry.GetDeprecatedResourceTypeMapping())
+	if err != nil {
+		return fmt.Errorf("unable to migrate %s: %w", path, err)
+	}
+
+	if len(migration.Changes) == 0 {
+		logrus.Infof("%s does not need to be migrated", path)
+		return nil
+	}
+
+	for _, change := range migration.Changes {
+		logrus.Info(change)
+	}
+
+	diff, err := migration.Diff(path)
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(diff)
+
+	output := c.String("output")
+	if c.Bool("in-place") {
+		output = path
+	}
+
+	if output == "" {
+		logrus.Info("no changes written, use --output or --in-place to write the migrated configuration")
+		return nil
+	}
+
+	if err := os.WriteFile(output, migration.Migrated, 0600); err != nil {
+		return err
+	}
+
+	logrus.Infof("migrated configuration written to %s", output)
+
+	return nil
+}
+
+func migrateCommand() *cli.Command {
+	flags := []cli.Flag{
+		&cli.StringFlag{
+			Name:    "output",
+			Aliases: []string{"o"},
+			Usage:   "path to write the migrated configuration to",
+		},
+		&cli.BoolFlag{
+			Name:  "in-place",
+			Usage: "overwrite the configuration file with the migrated configuration",
+		},
+	}
+
+	return &cli.Command{
+		Name:      "migrate",
+		Usage:     "migrate a legacy configuration file to the current format",
+		ArgsUsage: "<config-file>",
+		Description: `migrate rewrites a legacy configuration file, such as one written for rebuy-de/aws-nuke v2, to the
+current format. Deprecated keys such as account-blocklist and targets are renamed, feature-flags are converted to
+settings and renamed resource types are mapped to their replacements. A diff of the changes is printed, the migrated
+configuration is only written when --output or --in-place is provided.`,
+		Flags:  append(flags, global.Flags()...),
+		Before: global.Before,
+		Action: executeMigrate,
+	}
+}
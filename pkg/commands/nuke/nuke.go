@@ -2,7 +2,9 @@ package nuke
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"slices"
 	"strings"
@@ -16,15 +18,24 @@ import (
 
 	libconfig "github.com/ekristen/libnuke/pkg/config"
 	libnuke "github.com/ekristen/libnuke/pkg/nuke"
+	"github.com/ekristen/libnuke/pkg/queue"
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/scanner"
+	libsettings "github.com/ekristen/libnuke/pkg/settings"
 	"github.com/ekristen/libnuke/pkg/types"
 
+	"github.com/ekristen/aws-nuke/v3/pkg/approval"
 	"github.com/ekristen/aws-nuke/v3/pkg/awsutil"
 	"github.com/ekristen/aws-nuke/v3/pkg/commands/global"
 	"github.com/ekristen/aws-nuke/v3/pkg/common"
 	"github.com/ekristen/aws-nuke/v3/pkg/config"
+	"github.com/ekristen/aws-nuke/v3/pkg/hooks"
+	"github.com/ekristen/aws-nuke/v3/pkg/lock"
+	"github.com/ekristen/aws-nuke/v3/pkg/notify"
 	"github.com/ekristen/aws-nuke/v3/pkg/nuke"
+	"github.com/ekristen/aws-nuke/v3/pkg/plugin"
+	"github.com/ekristen/aws-nuke/v3/pkg/throttle"
+	"github.com/ekristen/aws-nuke/v3/pkg/undo"
 
 	"github.com/ekristen/aws-nuke/v3/resources"
 )
@@ -52,7 +63,7 @@ func execute(baseCtx context.Context, c *cli.Command) error { //nolint:funlen,go
 	creds := ConfigureCreds(c)
 
 	if err := creds.Validate(); err != nil {
-		return err
+		return common.NewExitError(common.ExitCodeConfig, err)
 	}
 
 	// Create the parameters object that will be used to configure the nuke process.
@@ -88,7 +99,7 @@ func execute(baseCtx context.Context, c *cli.Command) error { //nolint:funlen,go
 	})
 	if err != nil {
 		logger.Errorf("Failed to parse config file %s", c.String("config"))
-		return err
+		return common.NewExitError(common.ExitCodeConfig, err)
 	}
 
 	// Set the default region for the AWS SDK to use.
@@ -102,27 +113,65 @@ func execute(baseCtx context.Context, c *cli.Command) error { //nolint:funlen,go
 					"the custom region '%s' must be specified in the configuration 'endpoints'"+
 						" to determine its partition", defaultRegion)
 				logger.WithError(err).Errorf("unable to resolve partition for region: %s", defaultRegion)
-				return err
+				return common.NewExitError(common.ExitCodeConfig, err)
 			}
 		}
 
 		awsutil.DefaultAWSPartitionID = partition.ID()
 	}
 
+	// Limit the rate of requests per service and region, the limits are lowered adaptively when AWS throttles requests
+	creds.RateLimiter = throttle.New(parsedConfig.RateLimits)
+
 	// Create the AWS Account object. This will be used to get the account ID and aliases for the account.
 	account, err := awsutil.NewAccount(creds, parsedConfig.CustomEndpoints)
 	if err != nil {
-		return err
+		return common.NewExitError(common.ExitCodeAuth, err)
+	}
+
+	// Acquire the lock of the account, this refuses to start while another run holds it
+	if parsedConfig.Lock != nil {
+		release, err := acquireLock(ctx, c, parsedConfig.Lock, account, logger)
+		if err != nil {
+			var heldErr *lock.HeldError
+			if errors.As(err, &heldErr) {
+				return common.NewExitError(common.ExitCodeGuard, err)
+			}
+			return err
+		}
+		defer release()
+	}
+
+	// Start the plugins and register their resource types, they run until the run has ended
+	var plugins []*plugin.Client
+	defer func() {
+		for _, client := range plugins {
+			if err := client.Close(); err != nil {
+				logger.WithError(err).Warn("unable to stop plugin")
+			}
+		}
+	}()
+
+	for _, p := range parsedConfig.Plugins {
+		client, err := plugin.Start(ctx, p)
+		if err != nil {
+			return common.NewExitError(common.ExitCodeConfig, err)
+		}
+		plugins = append(plugins, client)
+
+		if err := client.Register(); err != nil {
+			return common.NewExitError(common.ExitCodeConfig, err)
+		}
 	}
 
 	// Get the filters for the account that is being connected to via the AWS SDK.
 	filters, err := parsedConfig.Filters(account.ID())
 	if err != nil {
-		return err
+		return common.NewExitError(common.ExitCodeConfig, err)
 	}
 
-	// Instantiate libnuke
-	n := libnuke.New(params, filters, parsedConfig.Settings)
+	// Instantiate the nuke process, this wraps libnuke
+	n := nuke.New(params, filters, parsedConfig.Settings)
 
 	n.SetRunSleep(c.Duration("run-sleep-delay"))
 	n.SetLogger(logger.WithField("component", "libnuke"))
@@ -133,6 +182,81 @@ func execute(baseCtx context.Context, c *cli.Command) error { //nolint:funlen,go
 		return parsedConfig.ValidateAccount(account.ID(), account.Aliases(), c.Bool("no-alias-check"))
 	})
 
+	// Register our settings resolver that applies the settings overrides scoped by account, region and tags on top of
+	// the global settings of each resource. The owner of an item is the region it was found in.
+	n.RegisterSettingsResolver(func(item *queue.Item) *libsettings.Setting {
+		return parsedConfig.ResolveSettings(account.ID(), item.Owner, item.Type, item)
+	})
+
+	// Register our removal limits handler, this refuses to remove anything when the scan queued more resources than the
+	// configured limits allow
+	if parsedConfig.RemovalLimits != nil {
+		n.RegisterQueueValidateHandler(
+			nuke.RemovalLimitsHandler(parsedConfig.RemovalLimits, logger.WithField("component", "limits")))
+	}
+
+	// Register our protection tags filter, any resource with one of the tags is filtered regardless of its resource type
+	// and checked again right before it would be removed, with the current tags of the resources that can refresh them
+	if len(parsedConfig.ProtectionTags) > 0 {
+		n.RegisterItemFilter(nuke.ProtectionTagsFilter(parsedConfig))
+		n.SetRefreshTags(true)
+	}
+
+	// Register our managed filter, AWS-managed and default resources are filtered unless they are included
+	n.RegisterItemFilter(nuke.ManagedFilter(parsedConfig.IncludeAWSManaged, parsedConfig.IncludeDefaults))
+
+	// Register our minimum age filter, any resource created less than min-age ago is filtered regardless of its
+	// resource type and checked again right before it would be removed
+	if parsedConfig.MinAge > 0 {
+		n.RegisterItemFilter(nuke.MinAgeFilter(parsedConfig.MinAge, time.Now))
+	}
+
+	// Register our notifier, this sends the configured notifications for the lifecycle events of the run
+	if len(parsedConfig.Notifications) > 0 {
+		notifier := &notify.Notifier{
+			Notifications: parsedConfig.Notifications,
+			AccountID:     account.ID(),
+			DryRun:        !params.NoDryRun,
+			Logger:        logger.WithField("component", "notify"),
+		}
+		if aliases := account.Aliases(); len(aliases) > 0 {
+			notifier.AccountAlias = aliases[0]
+		}
+		n.RegisterRunEventHandler(notifier.Handle)
+	}
+
+	// Register our approval gate, this publishes the plan and waits for a second person to approve it before anything
+	// is removed. The principal running aws-nuke cannot approve its own plan.
+	if parsedConfig.Approval != nil {
+		gate := &approval.Gate{
+			Config:    parsedConfig.Approval,
+			Secret:    []byte(os.Getenv(approval.SecretEnv)),
+			AccountID: account.ID(),
+			Requester: account.ARN(),
+			Logger:    logger.WithField("component", "approval"),
+		}
+		if aliases := account.Aliases(); len(aliases) > 0 {
+			gate.AccountAlias = aliases[0]
+		}
+		n.RegisterApprovalHandler(gate.Handle)
+	}
+
+	// Register our undo log, this records the definition of resources that support it right before they are removed
+	if parsedConfig.UndoLog != "" {
+		n.RegisterBeforeRemoveHandler(undo.NewLog(parsedConfig.UndoLog).Handle)
+	}
+
+	// Register our hooks, the hooks of the before stage can prevent a resource from being removed, the hooks of the
+	// after stage are run once it has been removed
+	if len(parsedConfig.Hooks) > 0 {
+		runner := &hooks.Runner{Hooks: parsedConfig.Hooks}
+		n.RegisterBeforeRemoveHandler(runner.Before)
+		n.RegisterAfterRemoveHandler(runner.After)
+	}
+
+	// Register the throttle metrics, they are added to the summary when requests were throttled or delayed
+	n.RegisterSummaryWriter(creds.RateLimiter.WriteSummary)
+
 	// Register our custom prompt handler that shows the account information
 	p := &nuke.Prompt{Parameters: params, Account: account, Logger: logger}
 	n.RegisterPrompt(p.Prompt)
@@ -178,34 +302,48 @@ func execute(baseCtx context.Context, c *cli.Command) error { //nolint:funlen,go
 		registry.GetAlternativeResourceTypeMapping(),
 	)
 
-	// If the user has specified the "all" region, then we need to get the enabled regions for the account
-	// and use those. Otherwise, we will use the regions that are specified in the configuration.
-	if slices.Contains(parsedConfig.Regions, "all") {
-		parsedConfig.Regions = account.Regions()
-
-		logger.Info(
-			`"all" detected in region list, only enabled regions and "global" will be used, all others ignored`)
+	// Remove any resource types that are part of a schedule when this run is outside all of their schedule windows.
+	deferredTypes := parsedConfig.DeferredResourceTypes(resourceTypes, time.Now())
+	if len(deferredTypes) > 0 {
+		logger.Infof("The following resource types are deferred, outside of their schedule windows (%d total):",
+			len(deferredTypes))
 
-		if len(parsedConfig.Regions) > 1 {
-			logger.Warnf(`additional regions defined along with "all", these will be ignored!`)
+		for _, rt := range slices.Sorted(maps.Keys(deferredTypes)) {
+			logger.Infof("> %s (schedules: %s)", rt, strings.Join(deferredTypes[rt], ", "))
+			resourceTypes = resourceTypes.Remove(types.Collection{rt})
 		}
 
-		logger.Infof("The following regions are enabled for the account (%d total):", len(parsedConfig.Regions))
+		// The deferred resource types are listed in the summary as well, they are easy to miss in the log of the scan
+		n.SetDeferredResourceTypes(deferredTypes)
+	}
 
-		printableRegions := make([]string, 0)
-		for i, region := range parsedConfig.Regions {
-			printableRegions = append(printableRegions, region)
-			if i%6 == 0 { // print 5 regions per line
-				logger.Infof("> %s", strings.Join(printableRegions, ", "))
-				printableRegions = make([]string, 0)
-			} else if i == len(parsedConfig.Regions)-1 {
-				logger.Infof("> %s", strings.Join(printableRegions, ", "))
-			}
+	// Resolve the regions for the account. This expands the special "all" region, region groups and patterns such as
+	// "us-*" against the enabled regions for the account, and removes any exclusions such as "!ap-east-1". The account
+	// level regions take precedence over the global regions.
+	regions, err := parsedConfig.ResolveRegions(account.ID(), account.Regions())
+	if err != nil {
+		return common.NewExitError(common.ExitCodeConfig, err)
+	}
+
+	logger.Infof("The following regions will be used for the account (%d total):", len(regions))
+
+	printableRegions := make([]string, 0)
+	for i, region := range regions {
+		printableRegions = append(printableRegions, region)
+		if len(printableRegions) == 6 || i == len(regions)-1 { // print 6 regions per line
+			logger.Infof("> %s", strings.Join(printableRegions, ", "))
+			printableRegions = make([]string, 0)
 		}
 	}
 
+	// Register a mutate function that will be called to modify the lister options for each resource type, see
+	// pkg/nuke/resource.go for the MutateOpts function. Its purpose is to create the proper session for the proper
+	// region. The listers of all regions share the scan concurrency budget, parallel-queries limits a single region.
+	n.RegisterMutateOptsFunc(nuke.MutateOpts)
+	n.SetScanConcurrency(scanConcurrency(c))
+
 	// Register the scanners for each region that is defined in the configuration.
-	for _, regionName := range parsedConfig.Regions {
+	for _, regionName := range regions {
 		// Step 1 - Create the region object
 		region := nuke.NewRegion(regionName, account.ResourceTypeToServiceType, account.NewSession, account.NewConfig)
 
@@ -220,31 +358,99 @@ func execute(baseCtx context.Context, c *cli.Command) error { //nolint:funlen,go
 					"component": "scanner",
 					"region":    regionName,
 				}),
+				Cache:           nuke.NewListCache(),
+				ResolveSettings: n.ResolveSettings,
 			},
-			Logger:          logger,
-			ParallelQueries: c.Int64("parallel-queries"),
-			QueueSize:       c.Int("max-queue-size"),
+			Logger:    logger,
+			QueueSize: c.Int("max-queue-size"),
 		})
 		if scannerActualErr != nil {
 			return scannerActualErr
 		}
 
-		// Step 3 - Register a mutate function that will be called to modify the lister options for each resource type
-		// see pkg/nuke/resource.go for the MutateOpts function. Its purpose is to create the proper session for the
-		// proper region.
-		regMutateErr := scannerActual.RegisterMutateOptsFunc(nuke.MutateOpts)
-		if regMutateErr != nil {
-			return regMutateErr
-		}
-
-		// Step 4 - Register the scannerActual with the nuke object
+		// Step 3 - Register the scannerActual with the nuke object
 		regScanErr := n.RegisterScanner(nuke.Account, scannerActual)
 		if regScanErr != nil {
 			return regScanErr
 		}
 	}
 
-	return n.Run(ctx)
+	runErr := n.Run(ctx)
+
+	// Write the resource types of the failed resources, so a retry run can be limited to them with --include
+	if path := c.String("failed-file"); path != "" {
+		failed := n.Summary().FailedResourceTypes()
+		if err := writeFailedFile(path, failed); err != nil {
+			logger.WithError(err).Errorf("unable to write the failed resource types to %s", path)
+		} else if len(failed) > 0 {
+			logger.Infof("wrote %d failed resource types to %s, retry with --include \"$(paste -sd, %s)\"",
+				len(failed), path, path)
+		}
+	}
+
+	var configErr *nuke.ConfigError
+	var guardErr *nuke.GuardError
+	var removalErr *nuke.RemovalError
+	switch {
+	case errors.As(runErr, &configErr):
+		return common.NewExitError(common.ExitCodeConfig, runErr)
+	case errors.As(runErr, &guardErr):
+		return common.NewExitError(common.ExitCodeGuard, runErr)
+	case errors.As(runErr, &removalErr):
+		return common.NewExitError(common.ExitCodeFailed, runErr)
+	default:
+		return runErr
+	}
+}
+
+// writeFailedFile writes the resource types, one per line. The file is truncated when there are none, so a retry run
+// never picks up the failures of an older run.
+func writeFailedFile(path string, resourceTypes []string) error {
+	var content string
+	if len(resourceTypes) > 0 {
+		content = strings.Join(resourceTypes, "\n") + "\n"
+	}
+
+	return os.WriteFile(path, []byte(content), 0o600)
+}
+
+// acquireLock acquires the lock of the account, breaking it first when requested. The returned function releases it.
+func acquireLock(
+	ctx context.Context, c *cli.Command, cfg *config.Lock, account *awsutil.Account, logger *logrus.Logger,
+) (func(), error) {
+	backend, err := lock.NewBackend(ctx, cfg, account.NewConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.Bool("break-lock") {
+		logger.Warnf("breaking the lock of account %s", account.ID())
+		if err := backend.Break(ctx, account.ID()); err != nil {
+			return nil, err
+		}
+	}
+
+	hostname, _ := os.Hostname()
+	info := lock.NewInfo(account.ID(),
+		fmt.Sprintf("%s on %s (pid %d)", account.ARN(), hostname, os.Getpid()), cfg.GetTTL())
+
+	if err := backend.Acquire(ctx, info); err != nil {
+		return nil, err
+	}
+
+	logger.Debugf("acquired the lock of account %s until %s", account.ID(), info.ExpiresAt.Format(time.RFC3339))
+
+	return func() {
+		// The run context may already be canceled, the lock must be released regardless
+		if err := backend.Release(context.WithoutCancel(ctx), info); err != nil {
+			logger.WithError(err).Warnf("unable to release the lock of account %s", account.ID())
+		}
+	}, nil
+}
+
+// scanConcurrency returns the number of listers that run at the same time across all regions and for a single region.
+func scanConcurrency(c *cli.Command) (budget, perScanner int64) {
+	return int64(c.Int("scan-concurrency")), int64(c.Int("parallel-queries"))
 }
 
 func init() { //nolint:funlen
@@ -306,6 +512,14 @@ func init() { //nolint:funlen
 			Name:  "no-alias-check",
 			Usage: "disable aws account alias check - requires entry in config as well",
 		},
+		&cli.StringFlag{
+			Name:  "failed-file",
+			Usage: "write the resource types of resources that failed to this file, one per line, to retry them with --include",
+		},
+		&cli.BoolFlag{
+			Name:  "break-lock",
+			Usage: "clear the lock of the account held by another run before acquiring it - requires lock in config",
+		},
 		&cli.StringSliceFlag{
 			Name:  "feature-flag",
 			Usage: "enable experimental behaviors that may not be fully tested or supported",
@@ -350,9 +564,15 @@ func init() { //nolint:funlen
 			Sources: cli.EnvVars("AWS_ASSUME_ROLE_EXTERNAL_ID"),
 			Usage:   "the external id to provide for the assumed role",
 		},
+		&cli.IntFlag{
+			Name:    "scan-concurrency",
+			Usage:   "number of resource queries to run at a time across all regions",
+			Sources: cli.EnvVars("AWS_NUKE_SCAN_CONCURRENCY"),
+			Value:   nuke.DefaultScanConcurrency,
+		},
 		&cli.IntFlag{
 			Name:    "parallel-queries",
-			Usage:   "CAUTION! ADVANCED USAGE! number of parallel resource queries to run at a time",
+			Usage:   "CAUTION! ADVANCED USAGE! number of parallel resource queries to run at a time for a single region",
 			Sources: cli.EnvVars("AWS_NUKE_PARALLEL_QUERIES"),
 			Value:   scanner.DefaultParallelQueries,
 			Hidden:  true,
@@ -0,0 +1,51 @@
+package nuke
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/urfave/cli/v3"
+
+	"github.com/ekristen/libnuke/pkg/scanner"
+
+	"github.com/ekristen/aws-nuke/v3/pkg/common"
+	"github.com/ekristen/aws-nuke/v3/pkg/nuke"
+)
+
+// runScanConcurrency parses the arguments with the flags of the run command and returns the scan concurrency.
+func runScanConcurrency(t *testing.T, args ...string) (budget, perScanner int64) {
+	var run *cli.Command
+	for _, cmd := range common.GetCommands() {
+		if cmd.Name == "run" {
+			run = cmd
+		}
+	}
+	assert.NotNil(t, run)
+
+	cmd := &cli.Command{
+		Name:  "run",
+		Flags: run.Flags,
+		Action: func(_ context.Context, c *cli.Command) error {
+			budget, perScanner = scanConcurrency(c)
+			return nil
+		},
+	}
+	assert.NoError(t, cmd.Run(context.TODO(), append([]string{"run"}, args...)))
+
+	return budget, perScanner
+}
+
+func TestScanConcurrency(t *testing.T) {
+	budget, perScanner := runScanConcurrency(t)
+	assert.Equal(t, int64(nuke.DefaultScanConcurrency), budget)
+	assert.Equal(t, int64(scanner.DefaultParallelQueries), perScanner)
+
+	t.Setenv("AWS_NUKE_SCAN_CONCURRENCY", "8")
+	t.Setenv("AWS_NUKE_PARALLEL_QUERIES", "3")
+	budget, perScanner = runScanConcurrency(t)
+	assert.Equal(t, []int64{8, 3}, []int64{budget, perScanner})
+
+	budget, perScanner = runScanConcurrency(t, "--scan-concurrency", "4", "--parallel-queries", "2")
+	assert.Equal(t, []int64{4, 2}, []int64{budget, perScanner})
+}
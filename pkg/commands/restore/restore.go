@@ -0,0 +1,289 @@
+package restore
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/gotidy/ptr"
+	"github.com/mb0/glob"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v3"
+
+	"github.com/aws/aws-sdk-go/aws/endpoints" //nolint:staticcheck
+
+	libconfig "github.com/ekristen/libnuke/pkg/config"
+	"github.com/ekristen/libnuke/pkg/registry"
+
+	"github.com/ekristen/aws-nuke/v3/pkg/awsutil"
+	"github.com/ekristen/aws-nuke/v3/pkg/backup"
+	"github.com/ekristen/aws-nuke/v3/pkg/commands/global"
+	nukecmd "github.com/ekristen/aws-nuke/v3/pkg/commands/nuke"
+	"github.com/ekristen/aws-nuke/v3/pkg/common"
+	"github.com/ekristen/aws-nuke/v3/pkg/config"
+	"github.com/ekristen/aws-nuke/v3/pkg/nuke"
+	"github.com/ekristen/aws-nuke/v3/pkg/undo"
+)
+
+// selectEntries returns the entries of the undo log that match the resource types, regions and name pattern. Empty
+// selectors match every entry.
+func selectEntries(entries []*undo.Entry, resourceTypes, regions []string, name string) ([]*undo.Entry, error) {
+	selected := make([]*undo.Entry, 0)
+	for _, entry := range entries {
+		if len(resourceTypes) > 0 && !slices.Contains(resourceTypes, entry.ResourceType) {
+			continue
+		}
+
+		if len(regions) > 0 && !slices.Contains(regions, entry.Region) {
+			continue
+		}
+
+		if name != "" {
+			match, err := glob.Match(name, entry.Name)
+			if err != nil {
+				return nil, fmt.Errorf("invalid name pattern '%s': %w", name, err)
+			}
+
+			if !match {
+				continue
+			}
+		}
+
+		selected = append(selected, entry)
+	}
+
+	return selected, nil
+}
+
+func execute(ctx context.Context, c *cli.Command) error { //nolint:funlen
+	entries, err := undo.Read(c.String("undo-log"))
+	if err != nil {
+		return err
+	}
+
+	entries, err = selectEntries(entries,
+		registry.ExpandNames(c.StringSlice("include")), c.StringSlice("region"), c.String("name"))
+	if err != nil {
+		return common.NewExitError(common.ExitCodeConfig, err)
+	}
+
+	if len(entries) == 0 {
+		logrus.Info("no entries of the undo log match the selection")
+		return nil
+	}
+
+	// The backup archive holds the values that are never recorded in the undo log, like SecureString parameters
+	if c.String("backup-archive") != "" {
+		passphrase := os.Getenv(backup.PassphraseEnv)
+		if passphrase == "" {
+			return common.NewExitError(common.ExitCodeConfig,
+				fmt.Errorf("a passphrase is required to decrypt the backup archive, set %s", backup.PassphraseEnv))
+		}
+
+		records, err := backup.Read(c.String("backup-archive"), passphrase)
+		if err != nil {
+			return common.NewExitError(common.ExitCodeConfig, err)
+		}
+
+		ctx = backup.WithRecords(ctx, records)
+	}
+
+	if !c.Bool("no-dry-run") {
+		for _, entry := range entries {
+			entryLog := logrus.WithFields(logrus.Fields{
+				"type":     entry.ResourceType,
+				"region":   entry.Region,
+				"recorded": entry.RecordedAt,
+			})
+
+			if undo.GetRestorer(entry.ResourceType) == nil {
+				entryLog.Warnf("%s - cannot be restored", entry.Name)
+				continue
+			}
+
+			entryLog.Infof("%s - would restore", entry.Name)
+		}
+
+		logrus.Info("The above resources would be restored. Provide --no-dry-run to actually restore them.")
+
+		return nil
+	}
+
+	defaultRegion := c.String("default-region")
+	creds := nukecmd.ConfigureCreds(c)
+
+	if err := creds.Validate(); err != nil {
+		return common.NewExitError(common.ExitCodeConfig, err)
+	}
+
+	// The configuration is only needed for the custom endpoints, it is optional
+	customEndpoints := config.CustomEndpoints{}
+	if c.String("config") != "" {
+		parsedConfig, err := config.New(libconfig.Options{
+			Path:         c.String("config"),
+			Deprecations: registry.GetDeprecatedResourceTypeMapping(),
+		})
+		if err != nil {
+			logrus.Errorf("Failed to parse config file %s", c.String("config"))
+			return common.NewExitError(common.ExitCodeConfig, err)
+		}
+
+		customEndpoints = parsedConfig.CustomEndpoints
+	}
+
+	if defaultRegion != "" {
+		awsutil.DefaultRegionID = defaultRegion
+
+		partition, ok := endpoints.PartitionForRegion(endpoints.DefaultPartitions(), defaultRegion)
+		if !ok && customEndpoints.GetRegion(defaultRegion) == nil {
+			return common.NewExitError(common.ExitCodeConfig, fmt.Errorf(
+				"the custom region '%s' must be specified in the configuration 'endpoints'"+
+					" to determine its partition", defaultRegion))
+		}
+
+		awsutil.DefaultAWSPartitionID = partition.ID()
+	}
+
+	account, err := awsutil.NewAccount(creds, customEndpoints)
+	if err != nil {
+		return common.NewExitError(common.ExitCodeAuth, err)
+	}
+
+	regions := make(map[string]*nuke.Region)
+
+	failed := 0
+	for _, entry := range entries {
+		entryLog := logrus.WithFields(logrus.Fields{
+			"type":   entry.ResourceType,
+			"region": entry.Region,
+		})
+
+		restorer := undo.GetRestorer(entry.ResourceType)
+		if restorer == nil {
+			entryLog.Warnf("%s - cannot be restored", entry.Name)
+			continue
+		}
+
+		region, ok := regions[entry.Region]
+		if !ok {
+			region = nuke.NewRegion(entry.Region, account.ResourceTypeToServiceType, account.NewSession, account.NewConfig)
+			regions[entry.Region] = region
+		}
+
+		opts := &nuke.ListerOpts{
+			Region:    region,
+			AccountID: ptr.String(account.ID()),
+			Logger:    entryLog,
+		}
+
+		if err = opts.SetClients(entry.ResourceType); err == nil {
+			err = restorer(ctx, opts, entry.Payload)
+		}
+
+		if err != nil {
+			failed++
+			entryLog.WithError(err).Errorf("%s - restore failed", entry.Name)
+			continue
+		}
+
+		entryLog.Infof("%s - restored", entry.Name)
+	}
+
+	if failed > 0 {
+		return common.NewExitError(common.ExitCodeFailed,
+			fmt.Errorf("%d of %d resources could not be restored", failed, len(entries)))
+	}
+
+	return nil
+}
+
+func init() { //nolint:funlen
+	flags := []cli.Flag{
+		&cli.StringFlag{
+			Name:     "undo-log",
+			Usage:    "path to the undo log written by a run",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name: "backup-archive",
+			Usage: "path to the backup archive written by the run, for the values that are not in the undo log. " +
+				"The passphrase is read from " + backup.PassphraseEnv,
+		},
+		&cli.StringSliceFlag{
+			Name:  "include",
+			Usage: "only restore these resource types",
+		},
+		&cli.StringSliceFlag{
+			Name:  "region",
+			Usage: "only restore resources from these regions",
+		},
+		&cli.StringFlag{
+			Name:  "name",
+			Usage: "only restore resources with a name that matches this glob pattern",
+		},
+		&cli.BoolFlag{
+			Name:  "no-dry-run",
+			Usage: "actually restore the resources",
+		},
+		&cli.StringFlag{
+			Name:    "config",
+			Aliases: []string{"c"},
+			Usage:   "path to config file, only used for custom endpoints",
+		},
+		&cli.StringFlag{
+			Name:    "default-region",
+			Sources: cli.EnvVars("AWS_DEFAULT_REGION"),
+			Usage:   "the default aws region to use when setting up the aws auth session",
+		},
+		&cli.StringFlag{
+			Name:    "access-key-id",
+			Sources: cli.EnvVars("AWS_ACCESS_KEY_ID"),
+			Usage:   "the aws access key id to use when setting up the aws auth session",
+		},
+		&cli.StringFlag{
+			Name:    "secret-access-key",
+			Sources: cli.EnvVars("AWS_SECRET_ACCESS_KEY"),
+			Usage:   "the aws secret access key to use when setting up the aws auth session",
+		},
+		&cli.StringFlag{
+			Name:    "session-token",
+			Sources: cli.EnvVars("AWS_SESSION_TOKEN"),
+			Usage:   "the aws session token to use when setting up the aws auth session, typically used for temporary credentials",
+		},
+		&cli.StringFlag{
+			Name:    "profile",
+			Sources: cli.EnvVars("AWS_PROFILE"),
+			Usage:   "the aws profile to use when setting up the aws auth session, typically used for shared credentials files",
+		},
+		&cli.StringFlag{
+			Name:    "assume-role-arn",
+			Sources: cli.EnvVars("AWS_ASSUME_ROLE_ARN"),
+			Usage:   "the role arn to assume using the credentials provided in the profile or statically set",
+		},
+		&cli.StringFlag{
+			Name:    "assume-role-session-name",
+			Sources: cli.EnvVars("AWS_ASSUME_ROLE_SESSION_NAME"),
+			Usage:   "the session name to provide for the assumed role",
+		},
+		&cli.StringFlag{
+			Name:    "assume-role-external-id",
+			Sources: cli.EnvVars("AWS_ASSUME_ROLE_EXTERNAL_ID"),
+			Usage:   "the external id to provide for the assumed role",
+		},
+	}
+
+	cmd := &cli.Command{
+		Name:  "restore",
+		Usage: "recreate removed resources from an undo log",
+		Description: `restore recreates resources from the undo log that a run with an undo-log configured writes before
+removing them. Only the definitions of configuration-only resources are recorded, the restore is best-effort and does
+not recover data, state or the relationships to resources that were removed as well. Without --no-dry-run the
+resources that would be restored are listed.`,
+		Flags:  append(flags, global.Flags()...),
+		Before: global.Before,
+		Action: execute,
+	}
+
+	common.RegisterCommand(cmd)
+}
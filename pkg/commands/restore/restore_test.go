@@ -0,0 +1,40 @@
+package restore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ekristen/aws-nuke/v3/pkg/undo"
+)
+
+func TestSelectEntries(t *testing.T) {
+	entries := []*undo.Entry{
+		{ResourceType: "CloudWatchAlarm", Region: "us-east-1", Name: "cpu-high"},
+		{ResourceType: "CloudWatchAlarm", Region: "eu-west-1", Name: "cpu-low"},
+		{ResourceType: "SNSTopic", Region: "us-east-1", Name: "alerts"},
+	}
+
+	cases := []struct {
+		name          string
+		resourceTypes []string
+		regions       []string
+		pattern       string
+		want          int
+	}{
+		{name: "all", want: 3},
+		{name: "resource-type", resourceTypes: []string{"CloudWatchAlarm"}, want: 2},
+		{name: "region", regions: []string{"us-east-1"}, want: 2},
+		{name: "name", pattern: "cpu-*", want: 2},
+		{name: "combined", resourceTypes: []string{"CloudWatchAlarm"}, regions: []string{"eu-west-1"}, want: 1},
+		{name: "none", resourceTypes: []string{"IAMPolicy"}, want: 0},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			selected, err := selectEntries(entries, tc.resourceTypes, tc.regions, tc.pattern)
+			assert.NoError(t, err)
+			assert.Len(t, selected, tc.want)
+		})
+	}
+}
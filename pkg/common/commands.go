@@ -36,15 +36,15 @@ func CheckFilePath(ctx context.Context, _ *cli.Command, s string) error {
 
 	select {
 	case <-timeout.Done():
-		return timeout.Err()
+		return NewExitError(ExitCodeConfig, timeout.Err())
 	case err := <-check:
-		return err
+		return NewExitError(ExitCodeConfig, err)
 	}
 }
 
 func CheckRealInt(_ context.Context, _ *cli.Command, i int) error {
 	if i > math.MaxInt || i < 0 {
-		return fmt.Errorf("value must be between 0 and %d", math.MaxInt)
+		return NewExitError(ExitCodeConfig, fmt.Errorf("value must be between 0 and %d", math.MaxInt))
 	}
 	return nil
 }
@@ -0,0 +1,67 @@
+package common
+
+import "errors"
+
+// The exit codes of the commands, they are part of the public interface and must not change.
+const (
+	// ExitCodeOK is returned when the command completed, including when there was nothing to remove.
+	ExitCodeOK = 0
+
+	// ExitCodeError is returned for errors that have no specific exit code.
+	ExitCodeError = 1
+
+	// ExitCodeConfig is returned when the configuration or the flags are invalid.
+	ExitCodeConfig = 2
+
+	// ExitCodeAuth is returned when the credentials are invalid or the account cannot be identified.
+	ExitCodeAuth = 3
+
+	// ExitCodeGuard is returned when a safety guard aborted the run, such as the account blocklist, the removal limits,
+	// the approval or the lock.
+	ExitCodeGuard = 4
+
+	// ExitCodeFailed is returned when resources remain failed after all retries.
+	ExitCodeFailed = 5
+)
+
+// ExitError is an error with the exit code the process should exit with.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+// NewExitError wraps the error with an exit code, it returns nil when the error is nil.
+func NewExitError(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &ExitError{Code: code, Err: err}
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns the exit code, it implements the cli.ExitCoder interface.
+func (e *ExitError) ExitCode() int {
+	return e.Code
+}
+
+// ExitCode returns the exit code for an error, errors without an exit code exit with ExitCodeError.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitCodeOK
+	}
+
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+
+	return ExitCodeError
+}
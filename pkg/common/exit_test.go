@@ -0,0 +1,25 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/urfave/cli/v3"
+)
+
+func TestExitCode(t *testing.T) {
+	assert.Equal(t, ExitCodeOK, ExitCode(nil))
+	assert.Equal(t, ExitCodeError, ExitCode(errors.New("unexpected")))
+
+	err := NewExitError(ExitCodeGuard, errors.New("removal limits exceeded"))
+	assert.EqualError(t, err, "removal limits exceeded")
+	assert.Equal(t, ExitCodeGuard, ExitCode(err))
+	assert.Equal(t, ExitCodeGuard, ExitCode(fmt.Errorf("wrapped: %w", err)))
+
+	var exitCoder cli.ExitCoder
+	assert.ErrorAs(t, err, &exitCoder)
+
+	assert.NoError(t, NewExitError(ExitCodeFailed, nil))
+}
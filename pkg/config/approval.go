@@ -0,0 +1,76 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultApprovalTimeout is how long a run waits for an approval when the approval does not configure a timeout.
+	DefaultApprovalTimeout = time.Hour
+
+	// DefaultApprovalPollInterval is how often a run checks for an approval when the approval does not configure an
+	// interval.
+	DefaultApprovalPollInterval = 10 * time.Second
+)
+
+// Approval requires a second person to approve a run before any resource is removed. The plan of the run is written to
+// a directory or posted to an HTTP endpoint, and the run waits until an approval token for the plan appears.
+type Approval struct {
+	// Directory is the directory the plan is written to and the approval token is read from.
+	Directory string `yaml:"directory"`
+
+	// URL is the HTTP endpoint the plan is posted to. The approval token is read from the URL with the digest of the
+	// plan appended as the last path segment. Environment variables are expanded.
+	URL string `yaml:"url"`
+
+	// Headers are additional HTTP headers that are sent with the requests to the URL. Environment variables are
+	// expanded.
+	Headers map[string]string `yaml:"headers"`
+
+	// Timeout is how long the run waits for the approval, for example 30m.
+	Timeout time.Duration `yaml:"timeout"`
+
+	// PollInterval is how often the run checks for the approval, for example 10s.
+	PollInterval time.Duration `yaml:"poll-interval"`
+}
+
+// Validate ensures the approval has exactly one of a directory or a URL and no negative durations. A nil approval is
+// valid, it means approvals are not required.
+func (a *Approval) Validate() error {
+	if a == nil {
+		return nil
+	}
+
+	if (a.Directory == "") == (a.URL == "") {
+		return fmt.Errorf("approval must have exactly one of directory or url")
+	}
+
+	if a.Timeout < 0 {
+		return fmt.Errorf("approval timeout must not be negative")
+	}
+
+	if a.PollInterval < 0 {
+		return fmt.Errorf("approval poll-interval must not be negative")
+	}
+
+	return nil
+}
+
+// GetTimeout returns the timeout, or the default when it is not set.
+func (a *Approval) GetTimeout() time.Duration {
+	if a.Timeout == 0 {
+		return DefaultApprovalTimeout
+	}
+
+	return a.Timeout
+}
+
+// GetPollInterval returns the poll interval, or the default when it is not set.
+func (a *Approval) GetPollInterval() time.Duration {
+	if a.PollInterval == 0 {
+		return DefaultApprovalPollInterval
+	}
+
+	return a.PollInterval
+}
@@ -0,0 +1,32 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestApproval_Validate(t *testing.T) {
+	assert.NoError(t, (*Approval)(nil).Validate())
+	assert.NoError(t, (&Approval{Directory: "/tmp/approvals"}).Validate())
+	assert.NoError(t, (&Approval{URL: "https://example.com/approvals"}).Validate())
+
+	assert.EqualError(t, (&Approval{}).Validate(), "approval must have exactly one of directory or url")
+	assert.EqualError(t, (&Approval{Directory: "/tmp", URL: "https://example.com"}).Validate(),
+		"approval must have exactly one of directory or url")
+	assert.EqualError(t, (&Approval{Directory: "/tmp", Timeout: -time.Second}).Validate(),
+		"approval timeout must not be negative")
+}
+
+func TestApproval_Durations(t *testing.T) {
+	a := &Approval{}
+	assert.NoError(t, yaml.Unmarshal([]byte("directory: /tmp\ntimeout: 30m\npoll-interval: 5s\n"), a))
+
+	assert.Equal(t, 30*time.Minute, a.GetTimeout())
+	assert.Equal(t, 5*time.Second, a.GetPollInterval())
+
+	assert.Equal(t, DefaultApprovalTimeout, (&Approval{}).GetTimeout())
+	assert.Equal(t, DefaultApprovalPollInterval, (&Approval{}).GetPollInterval())
+}
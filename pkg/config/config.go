@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 
@@ -35,6 +36,61 @@ func New(opts config.Options) (*Config, error) {
 	// Step 5 - Resolve any deprecated feature flags
 	c.ResolveDeprecatedFeatureFlags()
 
+	// Step 6 - Validate the schedules
+	if err := c.Schedules.Validate(c.Presets); err != nil {
+		return nil, err
+	}
+
+	// Step 7 - Validate the settings overrides
+	if err := c.ValidateSettingsOverrides(); err != nil {
+		return nil, err
+	}
+
+	// Step 8 - Validate the removal limits
+	if err := c.RemovalLimits.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Step 9 - Validate the protection tags
+	if err := c.ValidateProtectionTags(); err != nil {
+		return nil, err
+	}
+
+	// Step 10 - Validate the notifications
+	if err := c.ValidateNotifications(); err != nil {
+		return nil, err
+	}
+
+	// Step 11 - Validate the approval
+	if err := c.Approval.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Step 12 - Validate the lock
+	if err := c.Lock.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Step 13 - Validate the minimum age
+	if c.MinAge < 0 {
+		return nil, fmt.Errorf("min-age must not be negative")
+	}
+
+	// Step 14 - Validate the rate limits
+	if err := c.RateLimits.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Step 15 - Validate the hooks
+	if err := c.ValidateHooks(); err != nil {
+		return nil, err
+	}
+
+	// Step 16 - Validate the plugins
+	if err := c.ValidatePlugins(); err != nil {
+		return nil, err
+	}
+
 	return c, nil
 }
 
@@ -51,6 +107,39 @@ type Config struct {
 	// blocklist.
 	NoBlocklistTermsDefault bool `yaml:"no-blocklist-terms-default"`
 
+	// RemovalLimits is a set of safety limits on the number of resources a run may remove. If any of the limits is
+	// exceeded after the scan, the run will abort, even when the prompt is disabled.
+	RemovalLimits *RemovalLimits `yaml:"removal-limits"`
+
+	// ProtectionTags is a list of tags, in the form "key" or "key=value", that protect a resource from being removed
+	// regardless of its resource type or filters.
+	ProtectionTags []string `yaml:"protection-tags"`
+
+	// MinAge protects resources that were created less than this long ago, for example 30m, regardless of their
+	// resource type or filters. Resources that do not expose when they were created are not protected.
+	MinAge time.Duration `yaml:"min-age"`
+
+	// IncludeAWSManaged includes resources that are managed by AWS, such as service-linked roles, which are filtered
+	// otherwise.
+	IncludeAWSManaged bool `yaml:"include-aws-managed"`
+
+	// IncludeDefaults includes default resources that AWS creates, such as the rules of default security groups, which
+	// are filtered otherwise.
+	IncludeDefaults bool `yaml:"include-defaults"`
+
+	// RateLimits limits the rate of requests per service and region.
+	RateLimits *RateLimits `yaml:"rate-limits"`
+
+	// Lock prevents concurrent runs against the same account.
+	Lock *Lock `yaml:"lock"`
+
+	// UndoLog is the path of a file that the definitions of resources are recorded to before they are removed, so they
+	// can be recreated with the restore command. Only resources that support it are recorded.
+	UndoLog string `yaml:"undo-log"`
+
+	// Approval requires a second person to approve a run with --no-dry-run before any resource is removed.
+	Approval *Approval `yaml:"approval"`
+
 	// BypassAliasCheckAccounts is a list of account IDs that will be allowed to bypass the alias check.
 	// This is useful for accounts that don't have an alias for a number of reasons, it must be used with a cli
 	// flag --no-alias-check to be effective.
@@ -65,6 +154,33 @@ type Config struct {
 
 	// CustomEndpoints is a collection of custom endpoints that can be used to override the default AWS endpoints.
 	CustomEndpoints CustomEndpoints `yaml:"endpoints"`
+
+	// RegionGroups is a collection of named groups of regions that can be referenced by name in the regions list.
+	RegionGroups RegionGroups `yaml:"region-groups"`
+
+	// ExtendedAccounts is the aws-nuke specific configuration for each account. It is loaded from the same accounts
+	// block as the libnuke account configuration.
+	ExtendedAccounts map[string]*Account `yaml:"-"`
+
+	// Schedules is a collection of windows in which resource types are allowed to be removed. Resource types that are
+	// part of a schedule are deferred when the run happens outside all of their windows.
+	Schedules Schedules `yaml:"schedules"`
+
+	// SettingsOverrides is a list of settings that are scoped to accounts, regions and tags. They are applied on top
+	// of the global settings for the resources they match.
+	SettingsOverrides []*SettingsOverride `yaml:"settings-overrides"`
+
+	// Notifications is a list of endpoints, such as Slack or Teams channels, that are notified about the lifecycle
+	// events of a run.
+	Notifications []*Notification `yaml:"notifications"`
+
+	// Hooks is a list of commands or HTTP endpoints that are run before or after resources are removed. A hook that
+	// runs before the removal and fails prevents the resource from being removed.
+	Hooks []*Hook `yaml:"hooks"`
+
+	// Plugins is a list of external binaries that provide additional resource types, such as internal resources that
+	// do not belong upstream.
+	Plugins []*Plugin `yaml:"plugins"`
 }
 
 // Load loads a configuration from a file and parses it into a Config struct.
@@ -80,6 +196,13 @@ func (c *Config) Load(path string) error {
 		return err
 	}
 
+	accounts := extendedAccounts{}
+	if err := yaml.Unmarshal(raw, &accounts); err != nil {
+		return err
+	}
+
+	c.ExtendedAccounts = accounts.Accounts
+
 	if !c.NoBlocklistTermsDefault {
 		c.BlocklistTerms = append(c.BlocklistTerms, "prod")
 	}
@@ -140,40 +263,8 @@ func (c *Config) ResolveDeprecatedFeatureFlags() {
 	if c.FeatureFlags != nil {
 		c.Log.Warn("deprecated configuration key 'feature-flags' - please use 'settings' instead")
 
-		if c.FeatureFlags.ForceDeleteLightsailAddOns {
-			c.Settings.Set("LightsailInstance", &settings.Setting{
-				"ForceDeleteAddOns": true,
-			})
-		}
-		if c.FeatureFlags.DisableEC2InstanceStopProtection {
-			c.Settings.Set("EC2Instance", &settings.Setting{
-				"DisableStopProtection": true,
-			})
-		}
-		if c.FeatureFlags.DisableDeletionProtection.EC2Instance {
-			c.Settings.Set("EC2Instance", &settings.Setting{
-				"DisableDeletionProtection": true,
-			})
-		}
-		if c.FeatureFlags.DisableDeletionProtection.RDSInstance {
-			c.Settings.Set("RDSInstance", &settings.Setting{
-				"DisableDeletionProtection": true,
-			})
-		}
-		if c.FeatureFlags.DisableDeletionProtection.ELBv2 {
-			c.Settings.Set("ELBv2", &settings.Setting{
-				"DisableDeletionProtection": true,
-			})
-		}
-		if c.FeatureFlags.DisableDeletionProtection.CloudformationStack {
-			c.Settings.Set("CloudFormationStack", &settings.Setting{
-				"DisableDeletionProtection": true,
-			})
-		}
-		if c.FeatureFlags.DisableDeletionProtection.QLDBLedger {
-			c.Settings.Set("QLDBLedger", &settings.Setting{
-				"DisableDeletionProtection": true,
-			})
+		for resourceType, setting := range c.FeatureFlags.Settings() {
+			c.Settings.Set(resourceType, setting)
 		}
 	}
 }
@@ -189,6 +280,49 @@ type FeatureFlags struct {
 	ForceDeleteLightsailAddOns       bool                      `yaml:"force-delete-lightsail-addons"`
 }
 
+// Settings converts the feature flags into the equivalent settings keyed by resource type.
+func (f *FeatureFlags) Settings() settings.Settings {
+	converted := settings.Settings{}
+
+	if f.ForceDeleteLightsailAddOns {
+		converted.Set("LightsailInstance", &settings.Setting{
+			"ForceDeleteAddOns": true,
+		})
+	}
+	if f.DisableEC2InstanceStopProtection {
+		converted.Set("EC2Instance", &settings.Setting{
+			"DisableStopProtection": true,
+		})
+	}
+	if f.DisableDeletionProtection.EC2Instance {
+		converted.Set("EC2Instance", &settings.Setting{
+			"DisableDeletionProtection": true,
+		})
+	}
+	if f.DisableDeletionProtection.RDSInstance {
+		converted.Set("RDSInstance", &settings.Setting{
+			"DisableDeletionProtection": true,
+		})
+	}
+	if f.DisableDeletionProtection.ELBv2 {
+		converted.Set("ELBv2", &settings.Setting{
+			"DisableDeletionProtection": true,
+		})
+	}
+	if f.DisableDeletionProtection.CloudformationStack {
+		converted.Set("CloudFormationStack", &settings.Setting{
+			"DisableDeletionProtection": true,
+		})
+	}
+	if f.DisableDeletionProtection.QLDBLedger {
+		converted.Set("QLDBLedger", &settings.Setting{
+			"DisableDeletionProtection": true,
+		})
+	}
+
+	return converted
+}
+
 // DisableDeletionProtection is a collection of feature flags that can be used to disable deletion protection for
 // certain resource types. This is left over from the AWS Nuke tool and is deprecated. It was left to make transition
 // to the library and ekristen/aws-nuke@v3 easier for existing users.
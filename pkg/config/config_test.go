@@ -113,6 +113,9 @@ func TestConfig_LoadExample(t *testing.T) {
 			},
 		},
 		BlocklistTerms: []string{"prod"},
+		ExtendedAccounts: map[string]*Account{
+			"555133742": {},
+		},
 	}
 
 	assert.Equal(t, expect, *config)
@@ -176,6 +179,9 @@ func TestConfig_NoBlocklistTermsProd(t *testing.T) {
 		CustomEndpoints:         CustomEndpoints{},
 		BlocklistTerms:          []string{"alpha"},
 		NoBlocklistTermsDefault: true,
+		ExtendedAccounts: map[string]*Account{
+			"555133742": {},
+		},
 	}
 
 	assert.Equal(t, expect, *config)
@@ -0,0 +1,215 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	liberrors "github.com/ekristen/libnuke/pkg/errors"
+	"github.com/ekristen/libnuke/pkg/filter"
+)
+
+// FilterSourceAccount is the source name used for filters that are defined directly on the account.
+const FilterSourceAccount = "account"
+
+// FilterExplanation is the outcome of evaluating a single filter against a single resource.
+type FilterExplanation struct {
+	// Source is where the filter was defined, either "account" or "preset:<name>"
+	Source string
+
+	// Global is true when the filter was defined under the special __global__ resource type
+	Global bool
+
+	// Filter is the filter that was evaluated
+	Filter filter.Filter
+
+	// Value is the value of the property the filter was evaluated against
+	Value string
+
+	// Matched is the final result of the filter, after any inversion has been applied
+	Matched bool
+
+	// Err is set when the property could not be retrieved or the filter could not be evaluated
+	Err error
+}
+
+// Reason returns a human-readable explanation of why the filter matched or did not match, for example
+// `tag:owner glob "team-*" matched value "team-a"`.
+func (e *FilterExplanation) Reason() string {
+	property := e.Filter.Property
+	if property == "" {
+		property = "name"
+	}
+
+	filterType := string(e.Filter.Type)
+	if filterType == "" {
+		filterType = string(filter.Exact)
+	}
+
+	expected := fmt.Sprintf("%q", e.Filter.Value)
+	if e.Filter.Type == filter.In || e.Filter.Type == filter.NotIn {
+		expected = fmt.Sprintf("%q", e.Filter.Values)
+	}
+
+	if e.Err != nil {
+		return fmt.Sprintf("%s %s %s could not be evaluated: %s", property, filterType, expected, e.Err)
+	}
+
+	outcome := "matched"
+	if !e.Matched {
+		outcome = "did not match"
+	}
+
+	reason := fmt.Sprintf("%s %s %s %s value %q", property, filterType, expected, outcome, e.Value)
+	if e.Filter.Invert {
+		reason += " (inverted)"
+	}
+
+	return reason
+}
+
+// ResourceExplanation is the result of explaining how the configured filters of an account apply to a resource.
+type ResourceExplanation struct {
+	// ResourceType is the type of the resource that was explained
+	ResourceType string
+
+	// Filtered is true if the resource would be filtered, and therefore not removed
+	Filtered bool
+
+	// Filters is every filter that applies to the resource type in the order they were evaluated
+	Filters []*FilterExplanation
+
+	// Groups is the outcome of each filter group, it is only populated when filter groups are in use
+	Groups map[string]bool
+}
+
+// GroupNames returns the names of the filter groups that were evaluated in a stable order.
+func (e *ResourceExplanation) GroupNames() []string {
+	names := make([]string, 0, len(e.Groups))
+	for name := range e.Groups {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// FilterSources returns the filters for an account keyed by where they were defined. The account level filters
+// are keyed by FilterSourceAccount and each preset is keyed by "preset:<name>". Unlike Filters, this does not merge
+// the presets into the account filters, so it is safe to call multiple times.
+func (c *Config) FilterSources(accountID string) (sources []string, filters map[string]filter.Filters, err error) {
+	account, ok := c.Accounts[accountID]
+	if !ok || account == nil {
+		return nil, nil, liberrors.ErrAccountNotConfigured
+	}
+
+	filters = make(map[string]filter.Filters)
+
+	sources = append(sources, FilterSourceAccount)
+	filters[FilterSourceAccount] = account.Filters
+
+	for _, presetName := range account.Presets {
+		preset, ok := c.Presets[presetName]
+		if !ok {
+			return nil, nil, liberrors.ErrUnknownPreset(presetName)
+		}
+
+		source := fmt.Sprintf("preset:%s", presetName)
+		sources = append(sources, source)
+		filters[source] = preset.Filters
+	}
+
+	return sources, filters, nil
+}
+
+// ExplainFilters evaluates every filter configured for the account against the resource and records why each one
+// matched or did not. The decision mirrors the one made during a run, with or without filter groups.
+func (c *Config) ExplainFilters(
+	accountID, resourceType string, p filter.Property, useFilterGroups bool) (*ResourceExplanation, error) {
+	sources, filters, err := c.FilterSources(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	explanation := &ResourceExplanation{
+		ResourceType: resourceType,
+		Groups:       make(map[string]bool),
+	}
+
+	for _, source := range sources {
+		for _, scope := range []string{filter.Global, resourceType} {
+			for _, f := range filters[source][scope] {
+				explanation.Filters = append(explanation.Filters, explainFilter(source, scope == filter.Global, f, p))
+			}
+		}
+	}
+
+	if !useFilterGroups {
+		for _, e := range explanation.Filters {
+			if e.Matched {
+				explanation.Filtered = true
+				break
+			}
+		}
+
+		return explanation, nil
+	}
+
+	for _, e := range explanation.Filters {
+		explanation.Groups[e.Filter.Group] = explanation.Groups[e.Filter.Group] || e.Matched
+	}
+
+	explanation.Filtered = len(explanation.Groups) > 0
+	for _, matched := range explanation.Groups {
+		if !matched {
+			explanation.Filtered = false
+		}
+	}
+
+	return explanation, nil
+}
+
+func explainFilter(source string, global bool, f filter.Filter, p filter.Property) *FilterExplanation {
+	e := &FilterExplanation{
+		Source: source,
+		Global: global,
+		Filter: f,
+	}
+
+	value, err := p.GetProperty(f.Property)
+	if err != nil {
+		e.Err = err
+		return e
+	}
+
+	e.Value = value
+
+	matched, err := f.Match(value)
+	if err != nil {
+		e.Err = err
+		return e
+	}
+
+	if f.Invert {
+		matched = !matched
+	}
+
+	e.Matched = matched
+
+	return e
+}
+
+// String renders the source of a filter for display, including whether it was a global filter.
+func (e *FilterExplanation) String() string {
+	var parts []string
+	parts = append(parts, e.Source)
+	if e.Global {
+		parts = append(parts, filter.Global)
+	}
+	if e.Filter.Group != "" {
+		parts = append(parts, fmt.Sprintf("group:%s", e.Filter.Group))
+	}
+
+	return strings.Join(parts, " ")
+}
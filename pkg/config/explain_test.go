@@ -0,0 +1,151 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	libconfig "github.com/ekristen/libnuke/pkg/config"
+	liberrors "github.com/ekristen/libnuke/pkg/errors"
+)
+
+type testExplainProperties struct {
+	name       string
+	properties map[string]string
+}
+
+func (p *testExplainProperties) GetProperty(key string) (string, error) {
+	if key == "" {
+		return p.name, nil
+	}
+
+	return p.properties[key], nil
+}
+
+func TestConfig_ExplainFilters(t *testing.T) {
+	cases := []struct {
+		name          string
+		resource      *testExplainProperties
+		groups        bool
+		filtered      bool
+		matched       []string
+		groupsMatched map[string]bool
+	}{
+		{
+			name: "no-match",
+			resource: &testExplainProperties{
+				name:       "scratch",
+				properties: map[string]string{"Name": "scratch", "tag:owner": "someone"},
+			},
+			filtered: false,
+		},
+		{
+			name: "global-match",
+			resource: &testExplainProperties{
+				name:       "scratch",
+				properties: map[string]string{"Name": "scratch", "tag:nuke:protect": "true"},
+			},
+			filtered: true,
+			matched:  []string{`tag:nuke:protect exact "true" matched value "true"`},
+		},
+		{
+			name: "tag-match",
+			resource: &testExplainProperties{
+				name:       "scratch",
+				properties: map[string]string{"Name": "scratch", "tag:owner": "team-a"},
+			},
+			filtered: true,
+			matched:  []string{`tag:owner glob "team-*" matched value "team-a"`},
+		},
+		{
+			name: "preset-match",
+			resource: &testExplainProperties{
+				name:       "my-statebucket-1",
+				properties: map[string]string{"Name": "my-statebucket-1"},
+			},
+			filtered: true,
+			matched:  []string{`name glob "my-statebucket-*" matched value "my-statebucket-1"`},
+		},
+		{
+			name: "groups-partial-match",
+			resource: &testExplainProperties{
+				name:       "logs-1",
+				properties: map[string]string{"Name": "logs-1"},
+			},
+			groups:        true,
+			filtered:      false,
+			matched:       []string{`Name regex "^(logs|audit)-" matched value "logs-1"`},
+			groupsMatched: map[string]bool{"default": true, "owner": false},
+		},
+		{
+			name: "groups-full-match",
+			resource: &testExplainProperties{
+				name:       "logs-1",
+				properties: map[string]string{"Name": "logs-1", "tag:owner": "team-b"},
+			},
+			groups:   true,
+			filtered: true,
+			matched: []string{
+				`tag:owner glob "team-*" matched value "team-b"`,
+				`Name regex "^(logs|audit)-" matched value "logs-1"`,
+			},
+			groupsMatched: map[string]bool{"default": true, "owner": true},
+		},
+	}
+
+	cfg, err := New(libconfig.Options{
+		Path: "testdata/explain.yaml",
+	})
+	assert.NoError(t, err)
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			explanation, err := cfg.ExplainFilters("555133742", "S3Bucket", tc.resource, tc.groups)
+			assert.NoError(t, err)
+			assert.Len(t, explanation.Filters, 4)
+			assert.Equal(t, tc.filtered, explanation.Filtered)
+
+			var matched []string
+			for _, e := range explanation.Filters {
+				if e.Matched {
+					matched = append(matched, e.Reason())
+				}
+			}
+			assert.Equal(t, tc.matched, matched)
+
+			if tc.groups {
+				assert.Equal(t, tc.groupsMatched, explanation.Groups)
+			}
+		})
+	}
+}
+
+func TestConfig_ExplainFiltersSources(t *testing.T) {
+	cfg, err := New(libconfig.Options{
+		Path: "testdata/explain.yaml",
+	})
+	assert.NoError(t, err)
+
+	explanation, err := cfg.ExplainFilters("555133742", "S3Bucket", &testExplainProperties{}, false)
+	assert.NoError(t, err)
+
+	var sources []string
+	for _, e := range explanation.Filters {
+		sources = append(sources, e.String())
+	}
+
+	assert.Equal(t, []string{
+		"account __global__ group:default",
+		"account group:owner",
+		"account group:default",
+		"preset:terraform group:owner",
+	}, sources)
+
+	// Ensure explaining does not merge presets into the account filters
+	_, err = cfg.ExplainFilters("555133742", "S3Bucket", &testExplainProperties{}, false)
+	assert.NoError(t, err)
+	assert.Len(t, cfg.Accounts["555133742"].Filters["S3Bucket"], 2)
+
+	_, err = cfg.ExplainFilters("000000000", "S3Bucket", &testExplainProperties{}, false)
+	assert.ErrorIs(t, err, liberrors.ErrAccountNotConfigured)
+}
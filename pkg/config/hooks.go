@@ -0,0 +1,87 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+const (
+	// HookStageBefore runs the hook before a resource is removed, a hook that fails vetoes the removal.
+	HookStageBefore = "before"
+
+	// HookStageAfter runs the hook after a resource has been removed, a hook that fails is only logged.
+	HookStageAfter = "after"
+)
+
+// DefaultHookTimeout is how long a hook may run when the hook does not configure a timeout.
+const DefaultHookTimeout = 30 * time.Second
+
+// Hook is a custom action that is run around the removal of resources, such as deregistering a resource from a CMDB.
+// The resource is passed to the hook as a JSON document with its type, region, name and properties, on the standard
+// input of a command or as the body of an HTTP request.
+type Hook struct {
+	// Name is a name for the hook that is used in the logs and errors.
+	Name string `yaml:"name"`
+
+	// Stage is when the hook is run, before or after the removal.
+	Stage string `yaml:"stage"`
+
+	// ResourceTypes is a list of the resource types the hook is run for. It is run for all types when it is not set.
+	ResourceTypes []string `yaml:"resource-types"`
+
+	// Command is the command that is run with its arguments, it is not run in a shell. A non-zero exit code fails the
+	// hook. Only one of command or url may be set.
+	Command []string `yaml:"command"`
+
+	// URL is the URL the resource is posted to. A response status other than 2xx fails the hook. Environment variables
+	// are expanded.
+	URL string `yaml:"url"`
+
+	// Headers are additional HTTP headers that are sent with the request, for example to authenticate with the
+	// endpoint. Environment variables are expanded.
+	Headers map[string]string `yaml:"headers"`
+
+	// Timeout is how long the hook may run, for example 1m. A hook that runs longer fails.
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// HasResourceType returns true if the hook is run for the resource type.
+func (h *Hook) HasResourceType(resourceType string) bool {
+	return len(h.ResourceTypes) == 0 || slices.Contains(h.ResourceTypes, resourceType)
+}
+
+// GetTimeout returns the timeout, or the default when it is not set.
+func (h *Hook) GetTimeout() time.Duration {
+	if h.Timeout == 0 {
+		return DefaultHookTimeout
+	}
+
+	return h.Timeout
+}
+
+// ValidateHooks ensures every hook has a known stage, exactly one of a command or a URL and no negative timeout.
+func (c *Config) ValidateHooks() error {
+	for i, hook := range c.Hooks {
+		if hook == nil {
+			return fmt.Errorf("hook %d is empty", i)
+		}
+
+		switch hook.Stage {
+		case HookStageBefore, HookStageAfter:
+		default:
+			return fmt.Errorf("hook %d has an unknown stage '%s', must be one of %s or %s",
+				i, hook.Stage, HookStageBefore, HookStageAfter)
+		}
+
+		if (len(hook.Command) == 0) == (hook.URL == "") {
+			return fmt.Errorf("hook %d must have exactly one of command or url", i)
+		}
+
+		if hook.Timeout < 0 {
+			return fmt.Errorf("hook %d timeout must not be negative", i)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_ValidateHooks(t *testing.T) {
+	cases := []struct {
+		name    string
+		hook    *Hook
+		wantErr string
+	}{
+		{
+			name: "valid-command",
+			hook: &Hook{Stage: "before", Command: []string{"cmdb", "deregister"}, ResourceTypes: []string{"EC2Instance"}},
+		},
+		{
+			name: "valid-url",
+			hook: &Hook{Stage: "after", URL: "https://example.com/hook", Timeout: time.Minute},
+		},
+		{
+			name:    "unknown-stage",
+			hook:    &Hook{Stage: "during", URL: "https://example.com/hook"},
+			wantErr: "hook 0 has an unknown stage 'during', must be one of before or after",
+		},
+		{
+			name:    "missing-action",
+			hook:    &Hook{Stage: "before"},
+			wantErr: "hook 0 must have exactly one of command or url",
+		},
+		{
+			name:    "command-and-url",
+			hook:    &Hook{Stage: "before", Command: []string{"true"}, URL: "https://example.com/hook"},
+			wantErr: "hook 0 must have exactly one of command or url",
+		},
+		{
+			name:    "negative-timeout",
+			hook:    &Hook{Stage: "after", Command: []string{"true"}, Timeout: -time.Second},
+			wantErr: "hook 0 timeout must not be negative",
+		},
+		{
+			name:    "empty",
+			wantErr: "hook 0 is empty",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := (&Config{Hooks: []*Hook{tc.hook}}).ValidateHooks()
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.EqualError(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestHook_HasResourceType(t *testing.T) {
+	all := &Hook{}
+	assert.True(t, all.HasResourceType("EC2Instance"))
+
+	instances := &Hook{ResourceTypes: []string{"EC2Instance"}}
+	assert.True(t, instances.HasResourceType("EC2Instance"))
+	assert.False(t, instances.HasResourceType("S3Bucket"))
+}
+
+func TestHook_GetTimeout(t *testing.T) {
+	assert.Equal(t, DefaultHookTimeout, (&Hook{}).GetTimeout())
+	assert.Equal(t, time.Minute, (&Hook{Timeout: time.Minute}).GetTimeout())
+}
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+)
+
+// RemovalLimits are safety limits on the number of resources a single run may remove. When any of the limits is
+// exceeded after the scan, the run refuses to remove anything. A limit of zero is not enforced, except for the limits
+// of ResourceTypes.
+type RemovalLimits struct {
+	// MaxTotal is the maximum number of resources that may be removed in total.
+	MaxTotal int `yaml:"max-total"`
+
+	// MaxPerResourceType is the maximum number of resources that may be removed for each resource type.
+	MaxPerResourceType int `yaml:"max-per-resource-type"`
+
+	// MaxPercent is the maximum percentage of the discovered resources that may be removed.
+	MaxPercent float64 `yaml:"max-percent"`
+
+	// ResourceTypes is a map of resource types to their maximum number of removals, it takes precedence over the
+	// MaxPerResourceType limit whether it is lower or higher. Unlike the other limits, zero is enforced, no resource of
+	// the type may be removed.
+	ResourceTypes map[string]int `yaml:"resource-types"`
+}
+
+// Validate ensures the limits are not negative and the percentage is within range.
+func (l *RemovalLimits) Validate() error {
+	if l == nil {
+		return nil
+	}
+
+	if l.MaxTotal < 0 || l.MaxPerResourceType < 0 {
+		return fmt.Errorf("removal limits cannot be negative")
+	}
+
+	if l.MaxPercent < 0 || l.MaxPercent > 100 {
+		return fmt.Errorf("removal limit max-percent must be between 0 and 100")
+	}
+
+	for resourceType, limit := range l.ResourceTypes {
+		if limit < 0 {
+			return fmt.Errorf("removal limit for resource type '%s' cannot be negative", resourceType)
+		}
+	}
+
+	return nil
+}
+
+// ResourceTypeLimit returns the maximum number of removals for a resource type, false if there is no limit. The limit
+// of the resource type takes precedence over MaxPerResourceType.
+func (l *RemovalLimits) ResourceTypeLimit(resourceType string) (int, bool) {
+	if l == nil {
+		return 0, false
+	}
+
+	if limit, ok := l.ResourceTypes[resourceType]; ok {
+		return limit, true
+	}
+
+	return l.MaxPerResourceType, l.MaxPerResourceType > 0
+}
+
+// Exceeded returns a description of every limit that is exceeded by the removals, which is the number of resources
+// that would be removed by resource type, out of the total number of discovered resources.
+func (l *RemovalLimits) Exceeded(removals map[string]int, discovered int) []string {
+	if l == nil {
+		return nil
+	}
+
+	var exceeded []string
+
+	total := 0
+	resourceTypes := make([]string, 0, len(removals))
+	for resourceType, count := range removals {
+		total += count
+		resourceTypes = append(resourceTypes, resourceType)
+	}
+	sort.Strings(resourceTypes)
+
+	if l.MaxTotal > 0 && total > l.MaxTotal {
+		exceeded = append(exceeded, fmt.Sprintf("%d resources would be removed, the maximum is %d", total, l.MaxTotal))
+	}
+
+	if l.MaxPercent > 0 && discovered > 0 {
+		percent := float64(total) / float64(discovered) * 100
+		if percent > l.MaxPercent {
+			exceeded = append(exceeded, fmt.Sprintf("%.1f%% of the %d discovered resources would be removed, "+
+				"the maximum is %g%%", percent, discovered, l.MaxPercent))
+		}
+	}
+
+	for _, resourceType := range resourceTypes {
+		limit, ok := l.ResourceTypeLimit(resourceType)
+		if ok && removals[resourceType] > limit {
+			exceeded = append(exceeded, fmt.Sprintf("%d %s resources would be removed, the maximum is %d",
+				removals[resourceType], resourceType, limit))
+		}
+	}
+
+	return exceeded
+}
@@ -0,0 +1,128 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemovalLimits_Exceeded(t *testing.T) {
+	removals := map[string]int{
+		"EC2Instance": 40,
+		"S3Bucket":    5,
+		"IAMRole":     15,
+	}
+
+	cases := []struct {
+		name   string
+		limits *RemovalLimits
+		want   []string
+	}{
+		{
+			name:   "nil",
+			limits: nil,
+		},
+		{
+			name:   "within-limits",
+			limits: &RemovalLimits{MaxTotal: 60, MaxPerResourceType: 40, MaxPercent: 60},
+		},
+		{
+			name:   "max-total",
+			limits: &RemovalLimits{MaxTotal: 50},
+			want:   []string{"60 resources would be removed, the maximum is 50"},
+		},
+		{
+			name:   "max-percent",
+			limits: &RemovalLimits{MaxPercent: 50},
+			want:   []string{"60.0% of the 100 discovered resources would be removed, the maximum is 50%"},
+		},
+		{
+			name:   "max-per-resource-type",
+			limits: &RemovalLimits{MaxPerResourceType: 10},
+			want: []string{
+				"40 EC2Instance resources would be removed, the maximum is 10",
+				"15 IAMRole resources would be removed, the maximum is 10",
+			},
+		},
+		{
+			name: "resource-type-override",
+			limits: &RemovalLimits{
+				MaxPerResourceType: 10,
+				ResourceTypes:      map[string]int{"EC2Instance": 50, "S3Bucket": 1},
+			},
+			want: []string{
+				"15 IAMRole resources would be removed, the maximum is 10",
+				"5 S3Bucket resources would be removed, the maximum is 1",
+			},
+		},
+		{
+			name: "resource-type-zero",
+			limits: &RemovalLimits{
+				MaxPerResourceType: 50,
+				ResourceTypes:      map[string]int{"S3Bucket": 0},
+			},
+			want: []string{"5 S3Bucket resources would be removed, the maximum is 0"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.limits.Exceeded(removals, 100))
+		})
+	}
+}
+
+func TestRemovalLimits_ResourceTypeLimit(t *testing.T) {
+	limits := &RemovalLimits{
+		MaxPerResourceType: 10,
+		ResourceTypes:      map[string]int{"EC2Instance": 50, "S3Bucket": 0},
+	}
+
+	limit, ok := limits.ResourceTypeLimit("EC2Instance")
+	assert.True(t, ok)
+	assert.Equal(t, 50, limit)
+
+	limit, ok = limits.ResourceTypeLimit("S3Bucket")
+	assert.True(t, ok, "a limit of zero for a resource type is enforced")
+	assert.Equal(t, 0, limit)
+
+	limit, ok = limits.ResourceTypeLimit("IAMRole")
+	assert.True(t, ok)
+	assert.Equal(t, 10, limit)
+
+	_, ok = (&RemovalLimits{}).ResourceTypeLimit("IAMRole")
+	assert.False(t, ok)
+	_, ok = (*RemovalLimits)(nil).ResourceTypeLimit("IAMRole")
+	assert.False(t, ok)
+}
+
+func TestRemovalLimits_Validate(t *testing.T) {
+	cases := []struct {
+		name    string
+		limits  *RemovalLimits
+		wantErr bool
+	}{
+		{name: "nil", limits: nil},
+		{name: "valid", limits: &RemovalLimits{MaxTotal: 10, MaxPerResourceType: 5, MaxPercent: 12.5}},
+		{name: "negative-total", limits: &RemovalLimits{MaxTotal: -1}, wantErr: true},
+		{name: "negative-per-resource-type", limits: &RemovalLimits{MaxPerResourceType: -1}, wantErr: true},
+		{name: "percent-out-of-range", limits: &RemovalLimits{MaxPercent: 101}, wantErr: true},
+		{
+			name:    "negative-resource-type",
+			limits:  &RemovalLimits{ResourceTypes: map[string]int{"S3Bucket": -5}},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.limits.Validate()
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultLockTTL is how long a lock is held when the lock does not configure a TTL.
+const DefaultLockTTL = 6 * time.Hour
+
+// Lock prevents concurrent runs against the same account. The lock is stored in a directory or in an S3 bucket, only
+// one of them may be set.
+type Lock struct {
+	// Directory is the directory the lock files are stored in, it must be shared by all runs, for example a network
+	// file system mounted on the runners.
+	Directory string `yaml:"directory"`
+
+	// Bucket is the S3 bucket the locks are stored in. Conditional writes are used, so no other coordination is needed.
+	Bucket string `yaml:"bucket"`
+
+	// Prefix is the prefix of the lock objects in the bucket.
+	Prefix string `yaml:"prefix"`
+
+	// Region is the region of the bucket, the default region is used when it is not set.
+	Region string `yaml:"region"`
+
+	// TTL is how long the lock is held at most, for example 2h. A lock that has expired is taken over by the next run.
+	// It must be longer than the longest run.
+	TTL time.Duration `yaml:"ttl"`
+}
+
+// Validate ensures the lock has exactly one of a directory or a bucket and no negative TTL. A nil lock is valid, it
+// means runs are not locked.
+func (l *Lock) Validate() error {
+	if l == nil {
+		return nil
+	}
+
+	if (l.Directory == "") == (l.Bucket == "") {
+		return fmt.Errorf("lock must have exactly one of directory or bucket")
+	}
+
+	if l.TTL < 0 {
+		return fmt.Errorf("lock ttl must not be negative")
+	}
+
+	return nil
+}
+
+// GetTTL returns the TTL, or the default when it is not set.
+func (l *Lock) GetTTL() time.Duration {
+	if l.TTL == 0 {
+		return DefaultLockTTL
+	}
+
+	return l.TTL
+}
@@ -0,0 +1,29 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestLock_Validate(t *testing.T) {
+	assert.NoError(t, (*Lock)(nil).Validate())
+	assert.NoError(t, (&Lock{Directory: "/tmp/locks"}).Validate())
+	assert.NoError(t, (&Lock{Bucket: "locks", Prefix: "aws-nuke"}).Validate())
+
+	assert.EqualError(t, (&Lock{}).Validate(), "lock must have exactly one of directory or bucket")
+	assert.EqualError(t, (&Lock{Directory: "/tmp", Bucket: "locks"}).Validate(),
+		"lock must have exactly one of directory or bucket")
+	assert.EqualError(t, (&Lock{Directory: "/tmp", TTL: -time.Second}).Validate(),
+		"lock ttl must not be negative")
+}
+
+func TestLock_TTL(t *testing.T) {
+	l := &Lock{}
+	assert.NoError(t, yaml.Unmarshal([]byte("bucket: locks\nttl: 2h\n"), l))
+
+	assert.Equal(t, 2*time.Hour, l.GetTTL())
+	assert.Equal(t, DefaultLockTTL, (&Lock{}).GetTTL())
+}
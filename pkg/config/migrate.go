@@ -0,0 +1,310 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"gopkg.in/yaml.v3"
+)
+
+// Migration is the result of migrating a legacy configuration to the current format.
+type Migration struct {
+	// Original is the configuration before the migration
+	Original []byte
+
+	// Migrated is the configuration after the migration
+	Migrated []byte
+
+	// Changes is a human-readable list of the changes that were made
+	Changes []string
+
+	deprecations map[string]string
+}
+
+// Migrate rewrites a legacy configuration, such as one written for rebuy-de/aws-nuke v2, to the current format. It
+// renames deprecated keys, converts the feature flags to settings and maps renamed resource types using the provided
+// deprecations, which map the deprecated resource type to its replacement. Comments are preserved where possible.
+func Migrate(raw []byte, deprecations map[string]string) (*Migration, error) {
+	m := &Migration{
+		Original:     raw,
+		deprecations: deprecations,
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("configuration must be a yaml mapping")
+	}
+
+	root := doc.Content[0]
+
+	m.migrateBlocklist(root)
+
+	if err := m.migrateFeatureFlags(root); err != nil {
+		return nil, err
+	}
+
+	m.migrateResourceTypes(root, "resource-types")
+	m.renameMappingKeys(mappingValue(root, "settings"), "settings")
+
+	if accounts := mappingValue(root, "accounts"); accounts != nil && accounts.Kind == yaml.MappingNode {
+		for i := 0; i < len(accounts.Content); i += 2 {
+			path := fmt.Sprintf("accounts.%s", accounts.Content[i].Value)
+			account := accounts.Content[i+1]
+
+			m.migrateResourceTypes(account, path+".resource-types")
+			m.renameMappingKeys(mappingValue(account, "filters"), path+".filters")
+		}
+	}
+
+	if presets := mappingValue(root, "presets"); presets != nil && presets.Kind == yaml.MappingNode {
+		for i := 0; i < len(presets.Content); i += 2 {
+			path := fmt.Sprintf("presets.%s.filters", presets.Content[i].Value)
+			m.renameMappingKeys(mappingValue(presets.Content[i+1], "filters"), path)
+		}
+	}
+
+	var buf bytes.Buffer
+
+	// The document start marker is not retained by the yaml encoder, keep it when the original had one
+	if bytes.HasPrefix(raw, []byte("---")) {
+		buf.WriteString("---\n")
+	}
+
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+
+	m.Migrated = buf.Bytes()
+
+	return m, nil
+}
+
+// Diff returns a unified diff between the original and the migrated configuration.
+func (m *Migration) Diff(name string) (string, error) {
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(m.Original)),
+		B:        difflib.SplitLines(string(m.Migrated)),
+		FromFile: name,
+		ToFile:   name + " (migrated)",
+		Context:  3,
+	})
+}
+
+func (m *Migration) addChange(format string, args ...interface{}) {
+	m.Changes = append(m.Changes, fmt.Sprintf(format, args...))
+}
+
+// migrateBlocklist merges the deprecated account-blocklist and account-blacklist keys into blocklist.
+func (m *Migration) migrateBlocklist(root *yaml.Node) {
+	for _, key := range []string{"account-blocklist", "account-blacklist"} {
+		keyNode, valueNode := mappingPair(root, key)
+		if keyNode == nil {
+			continue
+		}
+
+		if _, blocklist := mappingPair(root, "blocklist"); blocklist != nil {
+			blocklist.Content = appendUnique(blocklist.Content, valueNode.Content)
+			deleteMappingKey(root, key)
+		} else {
+			keyNode.Value = "blocklist"
+		}
+
+		m.addChange("renamed '%s' to 'blocklist'", key)
+	}
+}
+
+// migrateFeatureFlags converts the deprecated feature-flags key into settings.
+func (m *Migration) migrateFeatureFlags(root *yaml.Node) error {
+	_, flagsNode := mappingPair(root, "feature-flags")
+	if flagsNode == nil {
+		return nil
+	}
+
+	var flags FeatureFlags
+	if err := flagsNode.Decode(&flags); err != nil {
+		return fmt.Errorf("unable to decode feature-flags: %w", err)
+	}
+
+	deleteMappingKey(root, "feature-flags")
+	m.addChange("removed 'feature-flags'")
+
+	converted := flags.Settings()
+	if len(converted) == 0 {
+		return nil
+	}
+
+	settingsNode := mappingValue(root, "settings")
+	if settingsNode == nil {
+		settingsNode = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		root.Content = append(root.Content, scalarNode("settings"), settingsNode)
+	}
+
+	resourceTypes := make([]string, 0, len(converted))
+	for resourceType := range converted {
+		resourceTypes = append(resourceTypes, resourceType)
+	}
+	sort.Strings(resourceTypes)
+
+	for _, resourceType := range resourceTypes {
+		setting := converted[resourceType]
+
+		typeNode := mappingValue(settingsNode, resourceType)
+		if typeNode == nil {
+			typeNode = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			settingsNode.Content = append(settingsNode.Content, scalarNode(resourceType), typeNode)
+		}
+
+		keys := make([]string, 0, len(*setting))
+		for key := range *setting {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			if mappingValue(typeNode, key) != nil {
+				continue
+			}
+
+			valueNode := &yaml.Node{}
+			if err := valueNode.Encode((*setting)[key]); err != nil {
+				return err
+			}
+
+			typeNode.Content = append(typeNode.Content, scalarNode(key), valueNode)
+			m.addChange("converted feature flag to 'settings.%s.%s'", resourceType, key)
+		}
+	}
+
+	return nil
+}
+
+// migrateResourceTypes renames the deprecated keys of a resource-types block and maps renamed resource types.
+func (m *Migration) migrateResourceTypes(parent *yaml.Node, path string) {
+	resourceTypes := mappingValue(parent, "resource-types")
+	if resourceTypes == nil || resourceTypes.Kind != yaml.MappingNode {
+		return
+	}
+
+	renames := [][2]string{{"targets", "includes"}, {"cloud-control", "alternatives"}}
+	for _, rename := range renames {
+		keyNode, valueNode := mappingPair(resourceTypes, rename[0])
+		if keyNode == nil {
+			continue
+		}
+
+		if existing := mappingValue(resourceTypes, rename[1]); existing != nil {
+			existing.Content = appendUnique(existing.Content, valueNode.Content)
+			deleteMappingKey(resourceTypes, rename[0])
+		} else {
+			keyNode.Value = rename[1]
+		}
+
+		m.addChange("renamed '%s.%s' to '%s.%s'", path, rename[0], path, rename[1])
+	}
+
+	for _, key := range []string{"includes", "excludes", "alternatives"} {
+		list := mappingValue(resourceTypes, key)
+		if list == nil || list.Kind != yaml.SequenceNode {
+			continue
+		}
+
+		for _, item := range list.Content {
+			if replacement, ok := m.deprecations[item.Value]; ok {
+				m.addChange("renamed resource type '%s' to '%s' in '%s.%s'", item.Value, replacement, path, key)
+				item.Value = replacement
+			}
+		}
+
+		list.Content = appendUnique(nil, list.Content)
+	}
+}
+
+// renameMappingKeys maps renamed resource types used as the keys of a mapping, such as filters or settings. When
+// both the deprecated and the replacement resource type are present, their values are merged.
+func (m *Migration) renameMappingKeys(node *yaml.Node, path string) {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return
+	}
+
+	for i := 0; i < len(node.Content); i += 2 {
+		keyNode := node.Content[i]
+
+		replacement, ok := m.deprecations[keyNode.Value]
+		if !ok {
+			continue
+		}
+
+		m.addChange("renamed resource type '%s' to '%s' in '%s'", keyNode.Value, replacement, path)
+
+		existing := mappingValue(node, replacement)
+		if existing == nil {
+			keyNode.Value = replacement
+			continue
+		}
+
+		existing.Content = append(existing.Content, node.Content[i+1].Content...)
+		node.Content = slices.Delete(node.Content, i, i+2)
+		i -= 2
+	}
+}
+
+// mappingPair returns the key and value nodes for the key of a mapping node, or nil if the key does not exist.
+func mappingPair(node *yaml.Node, key string) (keyNode, valueNode *yaml.Node) {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+
+	for i := 0; i < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i], node.Content[i+1]
+		}
+	}
+
+	return nil, nil
+}
+
+// mappingValue returns the value node for the key of a mapping node, or nil if the key does not exist.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	_, valueNode := mappingPair(node, key)
+	return valueNode
+}
+
+// deleteMappingKey removes the key and its value from a mapping node.
+func deleteMappingKey(node *yaml.Node, key string) {
+	for i := 0; i < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content = slices.Delete(node.Content, i, i+2)
+			return
+		}
+	}
+}
+
+// appendUnique appends the scalar nodes to the list, skipping any values that are already present.
+func appendUnique(list, nodes []*yaml.Node) []*yaml.Node {
+	for _, n := range nodes {
+		duplicate := slices.ContainsFunc(list, func(existing *yaml.Node) bool {
+			return existing.Kind == yaml.ScalarNode && n.Kind == yaml.ScalarNode && existing.Value == n.Value
+		})
+		if !duplicate {
+			list = append(list, n)
+		}
+	}
+
+	return list
+}
+
+func scalarNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
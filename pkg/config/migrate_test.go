@@ -0,0 +1,70 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	libconfig "github.com/ekristen/libnuke/pkg/config"
+)
+
+var testMigrateDeprecations = map[string]string{
+	"IamRole":           "IAMRole",
+	"ECRrepository":     "ECRRepository",
+	"IamUserAccessKeys": "IAMUserAccessKey",
+}
+
+func TestMigrate(t *testing.T) {
+	raw, err := os.ReadFile("testdata/legacy-v2.yaml")
+	assert.NoError(t, err)
+
+	expected, err := os.ReadFile("testdata/legacy-v2-migrated.yaml")
+	assert.NoError(t, err)
+
+	m, err := Migrate(raw, testMigrateDeprecations)
+	assert.NoError(t, err)
+	assert.Equal(t, string(expected), string(m.Migrated))
+	assert.Len(t, m.Changes, 13)
+
+	diff, err := m.Diff("legacy-v2.yaml")
+	assert.NoError(t, err)
+	assert.Contains(t, diff, "--- legacy-v2.yaml\n+++ legacy-v2.yaml (migrated)\n")
+	assert.Contains(t, diff, "-account-blacklist:\n")
+	assert.Contains(t, diff, "+      IAMUserAccessKey:\n")
+}
+
+func TestMigrate_LoadsCleanly(t *testing.T) {
+	raw, err := os.ReadFile("testdata/legacy-v2.yaml")
+	assert.NoError(t, err)
+
+	m, err := Migrate(raw, testMigrateDeprecations)
+	assert.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "migrated.yaml")
+	assert.NoError(t, os.WriteFile(path, m.Migrated, 0600))
+
+	c, err := New(libconfig.Options{
+		Path:         path,
+		Deprecations: testMigrateDeprecations,
+	})
+	assert.NoError(t, err)
+	assert.Nil(t, c.FeatureFlags)
+	assert.Equal(t, []string{"1234567890", "2345678901"}, c.Blocklist)
+	assert.Equal(t, true, c.Settings.Get("EC2Instance").Get("DisableStopProtection"))
+
+	// A migrated configuration does not need to be migrated again
+	again, err := Migrate(m.Migrated, testMigrateDeprecations)
+	assert.NoError(t, err)
+	assert.Empty(t, again.Changes)
+	assert.Equal(t, string(m.Migrated), string(again.Migrated))
+}
+
+func TestMigrate_Invalid(t *testing.T) {
+	_, err := Migrate([]byte("- not\n- a\n- mapping\n"), testMigrateDeprecations)
+	assert.Error(t, err)
+
+	_, err = Migrate([]byte("feature-flags: [invalid]\n"), testMigrateDeprecations)
+	assert.Error(t, err)
+}
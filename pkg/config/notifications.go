@@ -0,0 +1,100 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"text/template"
+)
+
+const (
+	// NotificationTypeSlack sends the message as a Slack incoming webhook payload.
+	NotificationTypeSlack = "slack"
+
+	// NotificationTypeTeams sends the message as a Microsoft Teams incoming webhook payload.
+	NotificationTypeTeams = "teams"
+
+	// NotificationTypeWebhook sends a JSON document describing the event to a generic HTTP endpoint.
+	NotificationTypeWebhook = "webhook"
+)
+
+const (
+	// NotificationEventRunStart is sent when a run starts, before the scan.
+	NotificationEventRunStart = "run-start"
+
+	// NotificationEventScanComplete is sent when the scan has completed, with the counts of the discovered resources.
+	NotificationEventScanComplete = "scan-complete"
+
+	// NotificationEventRemovalComplete is sent when all resources have been removed.
+	NotificationEventRemovalComplete = "removal-complete"
+
+	// NotificationEventRemovalFailed is sent when the removal has ended with resources that could not be removed.
+	NotificationEventRemovalFailed = "removal-failed"
+)
+
+// NotificationEvents is the list of all the events a notification can be sent for.
+var NotificationEvents = []string{
+	NotificationEventRunStart,
+	NotificationEventScanComplete,
+	NotificationEventRemovalComplete,
+	NotificationEventRemovalFailed,
+}
+
+// Notification is an endpoint that is notified about the lifecycle events of a run.
+type Notification struct {
+	// Type is the type of the endpoint, one of slack, teams or webhook.
+	Type string `yaml:"type"`
+
+	// URL is the URL the notification is posted to. Environment variables are expanded, so secrets such as the
+	// webhook URL of a Slack channel don't have to be stored in the configuration.
+	URL string `yaml:"url"`
+
+	// Events is a list of the events to notify about. All events are sent when it is not set.
+	Events []string `yaml:"events"`
+
+	// Template is a Go template that renders the message. For the slack and teams types it renders the text of the
+	// message, for the webhook type it renders the whole body of the request. A default is used when it is not set.
+	Template string `yaml:"template"`
+
+	// Headers are additional HTTP headers that are sent with the request, for example to authenticate with a generic
+	// endpoint. Environment variables are expanded.
+	Headers map[string]string `yaml:"headers"`
+}
+
+// HasEvent returns true if the notification is sent for the event.
+func (n *Notification) HasEvent(event string) bool {
+	return len(n.Events) == 0 || slices.Contains(n.Events, event)
+}
+
+// ValidateNotifications ensures every notification has a known type, a URL, known events and a valid template.
+func (c *Config) ValidateNotifications() error {
+	for i, notification := range c.Notifications {
+		if notification == nil {
+			return fmt.Errorf("notification %d is empty", i)
+		}
+
+		switch notification.Type {
+		case NotificationTypeSlack, NotificationTypeTeams, NotificationTypeWebhook:
+		default:
+			return fmt.Errorf("notification %d has an unknown type '%s', must be one of %s, %s or %s",
+				i, notification.Type, NotificationTypeSlack, NotificationTypeTeams, NotificationTypeWebhook)
+		}
+
+		if notification.URL == "" {
+			return fmt.Errorf("notification %d does not have a url", i)
+		}
+
+		for _, event := range notification.Events {
+			if !slices.Contains(NotificationEvents, event) {
+				return fmt.Errorf("notification %d has an unknown event '%s'", i, event)
+			}
+		}
+
+		if notification.Template != "" {
+			if _, err := template.New("notification").Parse(notification.Template); err != nil {
+				return fmt.Errorf("notification %d has an invalid template: %w", i, err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_ValidateNotifications(t *testing.T) {
+	cases := []struct {
+		name         string
+		notification *Notification
+		wantErr      string
+	}{
+		{
+			name:         "valid",
+			notification: &Notification{Type: "slack", URL: "https://hooks.slack.com/services/test"},
+		},
+		{
+			name: "valid-events-and-template",
+			notification: &Notification{
+				Type:     "webhook",
+				URL:      "https://example.com",
+				Events:   []string{"scan-complete", "removal-failed"},
+				Template: `{"account": "{{ .AccountID }}"}`,
+			},
+		},
+		{
+			name:         "unknown-type",
+			notification: &Notification{Type: "email", URL: "https://example.com"},
+			wantErr:      "notification 0 has an unknown type 'email', must be one of slack, teams or webhook",
+		},
+		{
+			name:         "missing-url",
+			notification: &Notification{Type: "teams"},
+			wantErr:      "notification 0 does not have a url",
+		},
+		{
+			name:         "unknown-event",
+			notification: &Notification{Type: "slack", URL: "https://example.com", Events: []string{"run-end"}},
+			wantErr:      "notification 0 has an unknown event 'run-end'",
+		},
+		{
+			name:         "invalid-template",
+			notification: &Notification{Type: "slack", URL: "https://example.com", Template: "{{ .AccountID "},
+			wantErr:      "notification 0 has an invalid template",
+		},
+		{
+			name:    "empty",
+			wantErr: "notification 0 is empty",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := (&Config{Notifications: []*Notification{tc.notification}}).ValidateNotifications()
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestNotification_HasEvent(t *testing.T) {
+	all := &Notification{}
+	assert.True(t, all.HasEvent(NotificationEventRunStart))
+	assert.True(t, all.HasEvent(NotificationEventRemovalFailed))
+
+	failures := &Notification{Events: []string{NotificationEventRemovalFailed}}
+	assert.False(t, failures.HasEvent(NotificationEventRunStart))
+	assert.True(t, failures.HasEvent(NotificationEventRemovalFailed))
+}
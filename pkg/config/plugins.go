@@ -0,0 +1,34 @@
+package config
+
+import (
+	"fmt"
+)
+
+// Plugin is an external binary that provides additional resource types. The plugin is started when a run starts and
+// speaks the plugin protocol on its standard input and output, see the plugin package.
+type Plugin struct {
+	// Name is a name for the plugin that is used in the logs and errors. The base name of the path is used when it is
+	// not set.
+	Name string `yaml:"name"`
+
+	// Path is the path of the plugin binary. Environment variables are expanded.
+	Path string `yaml:"path"`
+
+	// Args are additional arguments the plugin binary is started with.
+	Args []string `yaml:"args"`
+}
+
+// ValidatePlugins ensures every plugin has a path.
+func (c *Config) ValidatePlugins() error {
+	for i, plugin := range c.Plugins {
+		if plugin == nil {
+			return fmt.Errorf("plugin %d is empty", i)
+		}
+
+		if plugin.Path == "" {
+			return fmt.Errorf("plugin %d does not have a path", i)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,14 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_ValidatePlugins(t *testing.T) {
+	assert.NoError(t, (&Config{Plugins: []*Plugin{{Path: "/usr/local/bin/aws-nuke-plugin-partner"}}}).ValidatePlugins())
+	assert.EqualError(t, (&Config{Plugins: []*Plugin{{Name: "partner"}}}).ValidatePlugins(),
+		"plugin 0 does not have a path")
+	assert.EqualError(t, (&Config{Plugins: []*Plugin{nil}}).ValidatePlugins(), "plugin 0 is empty")
+}
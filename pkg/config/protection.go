@@ -0,0 +1,53 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ekristen/libnuke/pkg/types"
+)
+
+// defaultTagPrefix is the prefix that the properties of a resource use for its tags unless the resource overrides it.
+const defaultTagPrefix = "tag"
+
+// ValidateProtectionTags ensures every protection tag has a key.
+func (c *Config) ValidateProtectionTags() error {
+	for _, tag := range c.ProtectionTags {
+		if key, _ := parseProtectionTag(tag); key == "" {
+			return fmt.Errorf("protection tag '%s' does not have a key", tag)
+		}
+	}
+
+	return nil
+}
+
+// ProtectedBy returns the protection tag that matches the tags in the properties of a resource, or an empty string if
+// the resource is not protected. Only the tags of the resource itself are considered, not the tags of related resources
+// that some resources expose with an additional prefix, such as the tags of the VPC of a subnet.
+func (c *Config) ProtectedBy(properties types.Properties) string {
+	tagPrefix := properties.Get("_tagPrefix")
+	if tagPrefix == "" {
+		tagPrefix = defaultTagPrefix
+	}
+
+	for _, tag := range c.ProtectionTags {
+		key, value := parseProtectionTag(tag)
+
+		actual, ok := properties[fmt.Sprintf("%s:%s", tagPrefix, key)]
+		if !ok {
+			continue
+		}
+
+		if value == "" || strings.EqualFold(actual, value) {
+			return tag
+		}
+	}
+
+	return ""
+}
+
+// parseProtectionTag splits a protection tag in the form "key" or "key=value" into its key and value.
+func parseProtectionTag(tag string) (key, value string) {
+	key, value, _ = strings.Cut(tag, "=")
+	return strings.TrimSpace(key), strings.TrimSpace(value)
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/gotidy/ptr"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ekristen/libnuke/pkg/types"
+)
+
+func TestConfig_ProtectedBy(t *testing.T) {
+	cfg := &Config{
+		ProtectionTags: []string{"nuke:protect=true", "do-not-delete", "owner = platform"},
+	}
+
+	cases := []struct {
+		name       string
+		properties types.Properties
+		want       string
+	}{
+		{
+			name:       "no-tags",
+			properties: types.NewProperties().Set("Name", "test"),
+		},
+		{
+			name:       "key-and-value",
+			properties: types.NewProperties().SetTag(ptr.String("nuke:protect"), "true"),
+			want:       "nuke:protect=true",
+		},
+		{
+			name:       "value-case-insensitive",
+			properties: types.NewProperties().SetTag(ptr.String("nuke:protect"), "True"),
+			want:       "nuke:protect=true",
+		},
+		{
+			name:       "value-mismatch",
+			properties: types.NewProperties().SetTag(ptr.String("nuke:protect"), "false"),
+		},
+		{
+			name:       "key-only",
+			properties: types.NewProperties().SetTag(ptr.String("do-not-delete"), ""),
+			want:       "do-not-delete",
+		},
+		{
+			name:       "whitespace",
+			properties: types.NewProperties().SetTag(ptr.String("owner"), "platform"),
+			want:       "owner = platform",
+		},
+		{
+			name:       "related-resource-tag",
+			properties: types.NewProperties().SetTagWithPrefix("vpc", ptr.String("do-not-delete"), "yes"),
+		},
+		{
+			name: "custom-tag-prefix",
+			properties: types.NewProperties().SetTagPrefix("key:tag").
+				SetTag(ptr.String("do-not-delete"), "yes"),
+			want: "do-not-delete",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, cfg.ProtectedBy(tc.properties))
+		})
+	}
+}
+
+func TestConfig_ValidateProtectionTags(t *testing.T) {
+	assert.NoError(t, (&Config{ProtectionTags: []string{"nuke:protect=true", "keep"}}).ValidateProtectionTags())
+	assert.Error(t, (&Config{ProtectionTags: []string{"=true"}}).ValidateProtectionTags())
+}
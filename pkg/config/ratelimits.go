@@ -0,0 +1,137 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RateLimits configures the rate of requests per AWS service and region. Requests that are throttled by AWS lower the
+// rate adaptively, it recovers again once the requests succeed.
+type RateLimits struct {
+	// Default is the limit for services that do not have their own, there is no limit when it is not set.
+	Default *RateLimit `yaml:"default"`
+
+	// Services are the limits per service, keyed by the service ID, for example cloudwatch or ec2. The keys are
+	// case-insensitive and spaces are ignored, so "Route 53" and route53 are the same. A single operation is limited
+	// with the service ID and the name of the operation, for example cloudwatch:DeleteAlarms.
+	Services map[string]*RateLimit `yaml:"services"`
+}
+
+// DefaultOperationRateLimits are the limits of the operations that AWS allows at a far lower rate than the rest of
+// their service. They apply unless the operation is configured. They are shared by all regions of the account, like
+// the limits of AWS. The keys are normalized with OperationKey.
+var DefaultOperationRateLimits = normalizeOperationKeys(map[string]*RateLimit{
+	// The API Gateway Delete Rest API has a limit of 1 request per 30 seconds for each account
+	// https://docs.aws.amazon.com/apigateway/latest/developerguide/limits.html
+	// Note: due to time drift, set to 32 seconds to be safe.
+	"apigateway:DeleteRestApi": {Rate: 1.0 / 32, Burst: 1, Account: true},
+	"apigateway:DeleteApiKey":  {Rate: 1.0 / 32, Burst: 1, Account: true},
+
+	// AWS does not publish the rate limits for the cloud control api, the rate seems to be 60 reqs/minute, setting to
+	// 55 and setting no slack to avoid throttling.
+	"cloudcontrol:ListResources": {Rate: 55.0 / 60, Burst: 1, Account: true},
+
+	// ref - https://docs.aws.amazon.com/AmazonCloudWatch/latest/monitoring/cloudwatch_limits.html
+	"cloudwatch:DescribeAlarms":      {Rate: 8, Account: true},
+	"cloudwatch:DeleteAlarms":        {Rate: 3, Account: true},
+	"cloudwatch:ListTagsForResource": {Rate: 5, Account: true},
+})
+
+func normalizeOperationKeys(limits map[string]*RateLimit) map[string]*RateLimit {
+	normalized := make(map[string]*RateLimit, len(limits))
+	for key, limit := range limits {
+		service, operation, _ := strings.Cut(key, ":")
+		normalized[OperationKey(service, operation)] = limit
+	}
+
+	return normalized
+}
+
+// RateLimit is the maximum rate of requests for a service in a region.
+type RateLimit struct {
+	// Rate is the number of requests per second.
+	Rate float64 `yaml:"rate"`
+
+	// Burst is the number of requests that can be sent at once, it defaults to the rate.
+	Burst int `yaml:"burst"`
+
+	// Account shares the limit between all regions of the account, otherwise each region has its own limit.
+	Account bool `yaml:"account"`
+}
+
+// NormalizeServiceID returns the service ID in the form used for the keys of the rate limits.
+func NormalizeServiceID(serviceID string) string {
+	return strings.ToLower(strings.ReplaceAll(serviceID, " ", ""))
+}
+
+// OperationKey returns the key of the rate limit of an operation of a service.
+func OperationKey(serviceID, operation string) string {
+	return NormalizeServiceID(serviceID) + ":" + strings.ToLower(operation)
+}
+
+// Validate ensures the rates and bursts are not negative. Nil rate limits are valid, only adaptive limits are used.
+func (r *RateLimits) Validate() error {
+	if r == nil {
+		return nil
+	}
+
+	if err := r.Default.validate("default"); err != nil {
+		return err
+	}
+
+	for service, limit := range r.Services {
+		if err := limit.validate(service); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Get returns the limit for a service, or nil when the service is not limited.
+func (r *RateLimits) Get(serviceID string) *RateLimit {
+	if r == nil {
+		return nil
+	}
+
+	serviceID = NormalizeServiceID(serviceID)
+	for service, limit := range r.Services {
+		if NormalizeServiceID(service) == serviceID {
+			return limit
+		}
+	}
+
+	return r.Default
+}
+
+// GetOperation returns the limit for an operation of a service, or nil when the operation is only limited by the
+// limit of its service. A configured limit takes precedence over the default limits of the operations.
+func (r *RateLimits) GetOperation(serviceID, operation string) *RateLimit {
+	if operation == "" {
+		return nil
+	}
+
+	key := OperationKey(serviceID, operation)
+
+	if r != nil {
+		for service, limit := range r.Services {
+			if NormalizeServiceID(service) == key {
+				return limit
+			}
+		}
+	}
+
+	return DefaultOperationRateLimits[key]
+}
+
+func (l *RateLimit) validate(name string) error {
+	if l == nil {
+		return nil
+	}
+
+	if l.Rate < 0 || l.Burst < 0 {
+		return fmt.Errorf("rate limit for %s must not be negative", name)
+	}
+
+	return nil
+}
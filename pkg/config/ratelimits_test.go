@@ -0,0 +1,59 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestRateLimits(t *testing.T) {
+	r := &RateLimits{}
+	assert.NoError(t, yaml.Unmarshal([]byte(`
+default:
+  rate: 50
+services:
+  CloudWatch:
+    rate: 5
+    burst: 10
+  route53:
+    rate: 2
+`), r))
+	assert.NoError(t, r.Validate())
+
+	assert.Equal(t, &RateLimit{Rate: 5, Burst: 10}, r.Get("cloudwatch"))
+	assert.Equal(t, &RateLimit{Rate: 2}, r.Get("Route 53"))
+	assert.Equal(t, &RateLimit{Rate: 50}, r.Get("EC2"))
+
+	assert.Nil(t, (*RateLimits)(nil).Get("EC2"))
+	assert.Nil(t, (&RateLimits{}).Get("EC2"))
+}
+
+func TestRateLimits_GetOperation(t *testing.T) {
+	r := &RateLimits{
+		Default: &RateLimit{Rate: 50},
+		Services: map[string]*RateLimit{
+			"cloudwatch":              {Rate: 5},
+			"CloudWatch:DeleteAlarms": {Rate: 1},
+		},
+	}
+
+	assert.Equal(t, &RateLimit{Rate: 1}, r.GetOperation("CloudWatch", "DeleteAlarms"))
+	assert.Equal(t, DefaultOperationRateLimits["cloudwatch:describealarms"], r.GetOperation("cloudwatch", "DescribeAlarms"))
+	assert.Equal(t, DefaultOperationRateLimits["apigateway:deleterestapi"],
+		(*RateLimits)(nil).GetOperation("API Gateway", "DeleteRestApi"))
+	assert.Nil(t, r.GetOperation("cloudwatch", "PutMetricAlarm"))
+	assert.Nil(t, r.GetOperation("cloudwatch", ""))
+	assert.True(t, r.GetOperation("cloudwatch", "DescribeAlarms").Account)
+
+	// An operation key is never the limit of the whole service
+	assert.Equal(t, &RateLimit{Rate: 5}, r.Get("cloudwatch"))
+}
+
+func TestRateLimits_Validate(t *testing.T) {
+	assert.NoError(t, (*RateLimits)(nil).Validate())
+	assert.EqualError(t, (&RateLimits{Default: &RateLimit{Rate: -1}}).Validate(),
+		"rate limit for default must not be negative")
+	assert.EqualError(t, (&RateLimits{Services: map[string]*RateLimit{"ec2": {Burst: -1}}}).Validate(),
+		"rate limit for ec2 must not be negative")
+}
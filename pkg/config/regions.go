@@ -0,0 +1,141 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mb0/glob"
+)
+
+// RegionAll is the special region that expands to all regions that are enabled for the account, including global.
+const RegionAll = "all"
+
+// RegionGroups is a collection of named groups of regions that can be referenced in the regions list by name.
+type RegionGroups map[string][]string
+
+// Account is the aws-nuke specific configuration for an account. It extends the libnuke account configuration which
+// holds the filters, presets and resource types for the account.
+type Account struct {
+	// Regions overrides the global regions for the account. It supports the same patterns, groups and exclusions.
+	Regions []string `yaml:"regions"`
+}
+
+// extendedAccounts is used to load the aws-nuke specific account configuration from the same accounts block as the
+// libnuke account configuration.
+type extendedAccounts struct {
+	Accounts map[string]*Account `yaml:"accounts"`
+}
+
+// GetAccount returns the aws-nuke specific configuration for an account or nil if there is none.
+func (c *Config) GetAccount(accountID string) *Account {
+	return c.ExtendedAccounts[accountID]
+}
+
+// ResolveRegions resolves the regions for an account against the regions that are enabled for the account. The regions
+// from the account configuration take precedence over the global regions. Each entry may be:
+//
+//   - the special region "all" which expands to all enabled regions including global
+//   - the name of a region group which expands to the regions in the group
+//   - a glob pattern such as "us-*" which is matched against the enabled regions
+//   - an exclusion prefixed with "!" such as "!ap-east-1" or "!eu-*" which is removed from the result
+//   - an explicit region name which is always kept, even if it's not enabled, to support custom regions
+func (c *Config) ResolveRegions(accountID string, enabledRegions []string) ([]string, error) {
+	regions := c.Regions
+	if account := c.GetAccount(accountID); account != nil && len(account.Regions) > 0 {
+		regions = account.Regions
+	}
+
+	var includes, excludes []string
+	for _, region := range regions {
+		if strings.HasPrefix(region, "!") {
+			excludes = append(excludes, strings.TrimPrefix(region, "!"))
+			continue
+		}
+
+		includes = append(includes, region)
+	}
+
+	resolved, err := c.expandRegions(includes, enabledRegions, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	excluded, err := c.expandRegions(excludes, resolved, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var final []string
+	for _, region := range resolved {
+		if slices.Contains(final, region) || slices.Contains(excluded, region) {
+			continue
+		}
+
+		final = append(final, region)
+	}
+
+	return final, nil
+}
+
+// expandRegions expands the special region, region groups and patterns into region names. The seen slice is used to
+// detect region groups that reference each other.
+func (c *Config) expandRegions(regions, enabledRegions, seen []string) ([]string, error) {
+	var expanded []string
+
+	for _, region := range regions {
+		if region == RegionAll {
+			expanded = append(expanded, enabledRegions...)
+			continue
+		}
+
+		if group, ok := c.RegionGroups[region]; ok {
+			if slices.Contains(seen, region) {
+				return nil, fmt.Errorf("region group '%s' references itself", region)
+			}
+
+			groupRegions, err := c.expandRegions(group, enabledRegions, append(seen, region))
+			if err != nil {
+				return nil, err
+			}
+
+			expanded = append(expanded, groupRegions...)
+			continue
+		}
+
+		if strings.ContainsAny(region, "*?[") {
+			matches, err := globFilter(enabledRegions, region)
+			if err != nil {
+				return nil, fmt.Errorf("invalid region pattern '%s': %w", region, err)
+			}
+
+			if len(matches) == 0 && c.Log != nil {
+				c.Log.Warnf("region pattern '%s' did not match any enabled regions", region)
+			}
+
+			expanded = append(expanded, matches...)
+			continue
+		}
+
+		expanded = append(expanded, region)
+	}
+
+	return expanded, nil
+}
+
+// globFilter returns the values that match the glob pattern, preserving their order.
+func globFilter(values []string, pattern string) ([]string, error) {
+	var matches []string
+	for _, value := range values {
+		ok, err := glob.Match(pattern, value)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			matches = append(matches, value)
+		}
+	}
+
+	return matches, nil
+}
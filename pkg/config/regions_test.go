@@ -0,0 +1,70 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	libconfig "github.com/ekristen/libnuke/pkg/config"
+)
+
+func TestConfig_ResolveRegions(t *testing.T) {
+	enabled := []string{
+		"global", "us-east-1", "us-east-2", "us-west-1", "us-west-2", "eu-west-1", "eu-central-1", "ap-east-1",
+		"ap-southeast-1",
+	}
+
+	cases := []struct {
+		name      string
+		accountID string
+		want      []string
+		wantErr   bool
+	}{
+		{
+			name:      "global-regions",
+			accountID: "555133742",
+			want:      []string{"eu-west-1", "eu-central-1", "us-east-1", "us-east-2", "us-west-2"},
+		},
+		{
+			name:      "unknown-account-uses-global-regions",
+			accountID: "000000000",
+			want:      []string{"eu-west-1", "eu-central-1", "us-east-1", "us-east-2", "us-west-2"},
+		},
+		{
+			name:      "all-with-exclusion",
+			accountID: "555133743",
+			want: []string{
+				"global", "us-east-1", "us-east-2", "us-west-1", "us-west-2", "eu-west-1", "eu-central-1",
+				"ap-southeast-1",
+			},
+		},
+		{
+			name:      "nested-group-and-custom-region",
+			accountID: "555133744",
+			want:      []string{"global", "eu-west-1", "eu-central-1", "ap-east-1", "ap-southeast-1", "stratoscale"},
+		},
+		{
+			name:      "group-loop",
+			accountID: "555133745",
+			wantErr:   true,
+		},
+	}
+
+	cfg, err := New(libconfig.Options{
+		Path: "testdata/regions.yaml",
+	})
+	assert.NoError(t, err)
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			regions, err := cfg.ResolveRegions(tc.accountID, enabled)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, regions)
+		})
+	}
+}
@@ -0,0 +1,269 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ekristen/libnuke/pkg/config"
+)
+
+// Schedule is a window in which resource types are allowed to be removed. Resource types that are part of a schedule
+// are deferred when a run happens outside the window.
+type Schedule struct {
+	// Cron is a cron-like expression with the fields minute, hour, day of month, month and day of week. Every minute
+	// that matches the expression is part of the window, for example "* 0-5 * * *" is every night from midnight to 6am
+	// and "* * * * sat,sun" is all weekend.
+	Cron string `yaml:"cron"`
+
+	// Timezone is the IANA timezone the cron expression is evaluated in, defaults to UTC.
+	Timezone string `yaml:"timezone"`
+
+	// ResourceTypes is a list of resource types, or glob patterns of resource types, that are part of the schedule.
+	ResourceTypes []string `yaml:"resource-types"`
+
+	// Presets is a list of presets, every resource type with a filter in the preset is part of the schedule.
+	Presets []string `yaml:"presets"`
+
+	expression *cronExpression
+	location   *time.Location
+}
+
+// Schedules is a collection of named schedules.
+type Schedules map[string]*Schedule
+
+// Validate parses the cron expression and timezone of every schedule and ensures the referenced presets exist.
+func (s Schedules) Validate(presets map[string]config.Preset) error {
+	for name, schedule := range s {
+		if schedule == nil {
+			return fmt.Errorf("schedule '%s' is empty", name)
+		}
+
+		expression, err := parseCron(schedule.Cron)
+		if err != nil {
+			return fmt.Errorf("schedule '%s' has an invalid cron expression: %w", name, err)
+		}
+
+		location := time.UTC
+		if schedule.Timezone != "" {
+			location, err = time.LoadLocation(schedule.Timezone)
+			if err != nil {
+				return fmt.Errorf("schedule '%s' has an invalid timezone: %w", name, err)
+			}
+		}
+
+		for _, preset := range schedule.Presets {
+			if _, ok := presets[preset]; !ok {
+				return fmt.Errorf("schedule '%s' references unknown preset '%s'", name, preset)
+			}
+		}
+
+		schedule.expression = expression
+		schedule.location = location
+	}
+
+	return nil
+}
+
+// InWindow returns true if the time is within the window of the schedule.
+func (s *Schedule) InWindow(now time.Time) bool {
+	if s.expression == nil {
+		return false
+	}
+
+	return s.expression.matches(now.In(s.location))
+}
+
+// DeferredResourceTypes returns the resource types that are part of at least one schedule, but are not within the
+// window of any of their schedules at the given time. The value is the list of schedules the resource type is part of.
+func (c *Config) DeferredResourceTypes(resourceTypes []string, now time.Time) map[string][]string {
+	scheduled := make(map[string][]string)
+	inWindow := make(map[string]bool)
+
+	names := make([]string, 0, len(c.Schedules))
+	for name := range c.Schedules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		schedule := c.Schedules[name]
+		open := schedule.InWindow(now)
+
+		for _, resourceType := range c.scheduleResourceTypes(schedule, resourceTypes) {
+			scheduled[resourceType] = append(scheduled[resourceType], name)
+			inWindow[resourceType] = inWindow[resourceType] || open
+		}
+	}
+
+	deferred := make(map[string][]string)
+	for resourceType, schedules := range scheduled {
+		if !inWindow[resourceType] {
+			deferred[resourceType] = schedules
+		}
+	}
+
+	return deferred
+}
+
+// scheduleResourceTypes returns the resource types that are part of a schedule, limited to the given resource types.
+func (c *Config) scheduleResourceTypes(schedule *Schedule, resourceTypes []string) []string {
+	var matched []string
+
+	seen := make(map[string]bool)
+	add := func(resourceType string) {
+		if !seen[resourceType] {
+			seen[resourceType] = true
+			matched = append(matched, resourceType)
+		}
+	}
+
+	for _, pattern := range schedule.ResourceTypes {
+		matches, _ := globFilter(resourceTypes, pattern)
+		for _, resourceType := range matches {
+			add(resourceType)
+		}
+	}
+
+	for _, preset := range schedule.Presets {
+		for resourceType := range c.Presets[preset].Filters {
+			for _, candidate := range resourceTypes {
+				if candidate == resourceType {
+					add(resourceType)
+				}
+			}
+		}
+	}
+
+	return matched
+}
+
+// cronExpression is a parsed cron-like expression, each field is the set of allowed values.
+type cronExpression struct {
+	minutes     map[int]bool
+	hours       map[int]bool
+	daysOfMonth map[int]bool
+	months      map[int]bool
+	daysOfWeek  map[int]bool
+
+	// restrictedDays is true when both the day of month and the day of week are restricted, in which case either
+	// of them has to match, the same as cron.
+	restrictedDays bool
+}
+
+var cronDayNames = map[string]int{"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6}
+
+var cronMonthNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+func parseCron(expression string) (*cronExpression, error) {
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("expected 5 fields, got %d", len(fields))
+	}
+
+	var err error
+	e := &cronExpression{}
+
+	if e.minutes, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("minute: %w", err)
+	}
+	if e.hours, err = parseCronField(fields[1], 0, 23, nil); err != nil {
+		return nil, fmt.Errorf("hour: %w", err)
+	}
+	if e.daysOfMonth, err = parseCronField(fields[2], 1, 31, nil); err != nil {
+		return nil, fmt.Errorf("day of month: %w", err)
+	}
+	if e.months, err = parseCronField(fields[3], 1, 12, cronMonthNames); err != nil {
+		return nil, fmt.Errorf("month: %w", err)
+	}
+	if e.daysOfWeek, err = parseCronField(fields[4], 0, 7, cronDayNames); err != nil {
+		return nil, fmt.Errorf("day of week: %w", err)
+	}
+
+	// 7 is an alias for sunday
+	if e.daysOfWeek[7] {
+		e.daysOfWeek[0] = true
+	}
+
+	e.restrictedDays = fields[2] != "*" && fields[4] != "*"
+
+	return e, nil
+}
+
+func parseCronField(field string, minimum, maximum int, names map[string]int) (map[int]bool, error) {
+	values := make(map[int]bool)
+
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if idx := strings.Index(part, "/"); idx != -1 {
+			var err error
+			step, err = strconv.Atoi(part[idx+1:])
+			if err != nil || step < 1 {
+				return nil, fmt.Errorf("invalid step in '%s'", part)
+			}
+			part = part[:idx]
+		}
+
+		start, end := minimum, maximum
+		if part != "*" {
+			bounds := strings.SplitN(part, "-", 2)
+
+			var err error
+			if start, err = parseCronValue(bounds[0], names); err != nil {
+				return nil, err
+			}
+
+			end = start
+			if len(bounds) == 2 {
+				if end, err = parseCronValue(bounds[1], names); err != nil {
+					return nil, err
+				}
+			} else if step > 1 {
+				end = maximum
+			}
+		}
+
+		if start < minimum || end > maximum || start > end {
+			return nil, fmt.Errorf("'%s' is out of range %d-%d", part, minimum, maximum)
+		}
+
+		for i := start; i <= end; i += step {
+			values[i] = true
+		}
+	}
+
+	return values, nil
+}
+
+func parseCronValue(value string, names map[string]int) (int, error) {
+	if v, ok := names[strings.ToLower(value)]; ok {
+		return v, nil
+	}
+
+	v, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value '%s'", value)
+	}
+
+	return v, nil
+}
+
+func (e *cronExpression) matches(t time.Time) bool {
+	if !e.minutes[t.Minute()] || !e.hours[t.Hour()] || !e.months[int(t.Month())] {
+		return false
+	}
+
+	dayOfMonth := e.daysOfMonth[t.Day()]
+	dayOfWeek := e.daysOfWeek[int(t.Weekday())]
+
+	if e.restrictedDays {
+		return dayOfMonth || dayOfWeek
+	}
+
+	return dayOfMonth && dayOfWeek
+}
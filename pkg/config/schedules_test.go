@@ -0,0 +1,103 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	libconfig "github.com/ekristen/libnuke/pkg/config"
+)
+
+func TestConfig_DeferredResourceTypes(t *testing.T) {
+	resourceTypes := []string{
+		"RDSSnapshot", "RDSClusterSnapshot", "S3Bucket", "EC2Instance", "EC2NATGateway", "EC2Volume",
+	}
+
+	cases := []struct {
+		name string
+		now  time.Time
+		want map[string][]string
+	}{
+		{
+			name: "weekday-afternoon",
+			now:  time.Date(2024, 1, 3, 14, 0, 0, 0, time.UTC), // Wednesday
+			want: map[string][]string{
+				"RDSSnapshot":        {"weekends"},
+				"RDSClusterSnapshot": {"weekends"},
+				"S3Bucket":           {"weekends"},
+				"EC2Instance":        {"maintenance", "nightly"},
+				"EC2NATGateway":      {"nightly"},
+			},
+		},
+		{
+			name: "weekday-night-in-timezone",
+			now:  time.Date(2024, 1, 3, 23, 30, 0, 0, time.UTC), // 00:30 in Europe/Berlin
+			want: map[string][]string{
+				"RDSSnapshot":        {"weekends"},
+				"RDSClusterSnapshot": {"weekends"},
+				"S3Bucket":           {"weekends"},
+			},
+		},
+		{
+			name: "saturday-afternoon",
+			now:  time.Date(2024, 1, 6, 14, 0, 0, 0, time.UTC),
+			want: map[string][]string{
+				"EC2Instance":   {"maintenance", "nightly"},
+				"EC2NATGateway": {"nightly"},
+			},
+		},
+		{
+			name: "first-of-month-maintenance",
+			now:  time.Date(2024, 2, 1, 12, 30, 0, 0, time.UTC), // Thursday
+			want: map[string][]string{
+				"RDSSnapshot":        {"weekends"},
+				"RDSClusterSnapshot": {"weekends"},
+				"S3Bucket":           {"weekends"},
+				"EC2NATGateway":      {"nightly"},
+			},
+		},
+	}
+
+	cfg, err := New(libconfig.Options{
+		Path: "testdata/schedules.yaml",
+	})
+	assert.NoError(t, err)
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, cfg.DeferredResourceTypes(resourceTypes, tc.now))
+		})
+	}
+}
+
+func TestSchedules_Validate(t *testing.T) {
+	cases := []struct {
+		name     string
+		schedule *Schedule
+		wantErr  bool
+	}{
+		{name: "valid", schedule: &Schedule{Cron: "0-59/5 1,2,3 1-15 jan-jun mon-fri"}},
+		{name: "sunday-alias", schedule: &Schedule{Cron: "* * * * 7"}},
+		{name: "empty", schedule: nil, wantErr: true},
+		{name: "too-few-fields", schedule: &Schedule{Cron: "* * *"}, wantErr: true},
+		{name: "out-of-range", schedule: &Schedule{Cron: "60 * * * *"}, wantErr: true},
+		{name: "reversed-range", schedule: &Schedule{Cron: "* 5-1 * * *"}, wantErr: true},
+		{name: "invalid-step", schedule: &Schedule{Cron: "*/0 * * * *"}, wantErr: true},
+		{name: "invalid-name", schedule: &Schedule{Cron: "* * * * funday"}, wantErr: true},
+		{name: "invalid-timezone", schedule: &Schedule{Cron: "* * * * *", Timezone: "Mars/Base"}, wantErr: true},
+		{name: "unknown-preset", schedule: &Schedule{Cron: "* * * * *", Presets: []string{"missing"}}, wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Schedules{tc.name: tc.schedule}.Validate(map[string]libconfig.Preset{})
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
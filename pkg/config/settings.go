@@ -0,0 +1,147 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mb0/glob"
+
+	"github.com/ekristen/libnuke/pkg/filter"
+	"github.com/ekristen/libnuke/pkg/settings"
+)
+
+// SettingsOverride scopes settings to a subset of accounts, regions and resources. Every selector that is set has to
+// match for the settings to be applied, a selector that is not set matches everything.
+type SettingsOverride struct {
+	// Accounts is a list of account IDs the override applies to.
+	Accounts []string `yaml:"accounts"`
+
+	// Regions is a list of regions the override applies to. It supports the same patterns and region groups as the
+	// global regions.
+	Regions []string `yaml:"regions"`
+
+	// Tags is a map of tag keys to values a resource must have for the override to apply. Values may be glob patterns.
+	Tags map[string]string `yaml:"tags"`
+
+	// Settings is a map of resource types to the settings that are applied on top of the global settings.
+	Settings settings.Settings `yaml:"settings"`
+}
+
+// ValidateSettingsOverrides ensures every settings override has settings and that its region groups can be resolved.
+func (c *Config) ValidateSettingsOverrides() error {
+	for i, override := range c.SettingsOverrides {
+		if override == nil || len(override.Settings) == 0 {
+			return fmt.Errorf("settings override %d does not have any settings", i)
+		}
+
+		if _, err := c.matchRegion(override.Regions, "", nil); err != nil {
+			return fmt.Errorf("settings override %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// ResolveSettings returns the settings for a resource in a region of an account. The global settings for the resource
+// type are used as the base and every settings override that matches is applied on top, in the order they are
+// defined. The global settings are returned as-is when no override applies.
+func (c *Config) ResolveSettings(accountID, region, resourceType string, p filter.Property) *settings.Setting {
+	base := c.Settings.Get(resourceType)
+
+	var resolved *settings.Setting
+	for _, override := range c.SettingsOverrides {
+		setting, ok := override.Settings[resourceType]
+		if !ok || setting == nil || !c.overrideMatches(override, accountID, region, p) {
+			continue
+		}
+
+		if resolved == nil {
+			resolved = &settings.Setting{}
+			if base != nil {
+				for k, v := range *base {
+					resolved.Set(k, v)
+				}
+			}
+		}
+
+		for k, v := range *setting {
+			resolved.Set(k, v)
+		}
+	}
+
+	if resolved == nil {
+		return base
+	}
+
+	return resolved
+}
+
+// overrideMatches returns true if all the selectors of the settings override match.
+func (c *Config) overrideMatches(override *SettingsOverride, accountID, region string, p filter.Property) bool {
+	if len(override.Accounts) > 0 && !slices.Contains(override.Accounts, accountID) {
+		return false
+	}
+
+	if len(override.Regions) > 0 {
+		if match, err := c.matchRegion(override.Regions, region, nil); err != nil || !match {
+			return false
+		}
+	}
+
+	for key, value := range override.Tags {
+		if p == nil {
+			return false
+		}
+
+		tag, err := p.GetProperty(fmt.Sprintf("tag:%s", key))
+		if err != nil {
+			return false
+		}
+
+		if match, _ := glob.Match(value, tag); !match {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchRegion returns true if the region is selected by the list of regions. The list supports the special region
+// "all", region groups, glob patterns and exclusions prefixed with "!". The seen slice is used to detect region groups
+// that reference each other.
+func (c *Config) matchRegion(regions []string, region string, seen []string) (bool, error) {
+	matched := false
+
+	for _, entry := range regions {
+		exclude := strings.HasPrefix(entry, "!")
+		entry = strings.TrimPrefix(entry, "!")
+
+		var match bool
+		if group, ok := c.RegionGroups[entry]; ok {
+			if slices.Contains(seen, entry) {
+				return false, fmt.Errorf("region group '%s' references itself", entry)
+			}
+
+			var err error
+			if match, err = c.matchRegion(group, region, append(seen, entry)); err != nil {
+				return false, err
+			}
+		} else {
+			var err error
+			if match, err = glob.Match(entry, region); err != nil {
+				return false, fmt.Errorf("invalid region pattern '%s': %w", entry, err)
+			}
+
+			match = match || entry == RegionAll
+		}
+
+		if match && exclude {
+			return false, nil
+		}
+
+		matched = matched || match
+	}
+
+	return matched, nil
+}
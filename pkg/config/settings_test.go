@@ -0,0 +1,177 @@
+package config
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	libconfig "github.com/ekristen/libnuke/pkg/config"
+	"github.com/ekristen/libnuke/pkg/settings"
+)
+
+type testProperties map[string]string
+
+func (p testProperties) GetProperty(key string) (string, error) {
+	if p == nil {
+		return "", fmt.Errorf("does not support custom properties")
+	}
+
+	return p[key], nil
+}
+
+func TestConfig_ResolveSettings(t *testing.T) {
+	cases := []struct {
+		name         string
+		accountID    string
+		region       string
+		resourceType string
+		properties   testProperties
+		want         *settings.Setting
+	}{
+		{
+			name:         "account-and-region-match",
+			accountID:    "555133742",
+			region:       "eu-west-1",
+			resourceType: "RDSInstance",
+			want: &settings.Setting{
+				"DisableDeletionProtection": true,
+				"StartClusterToDelete":      true,
+			},
+		},
+		{
+			name:         "excluded-region",
+			accountID:    "555133742",
+			region:       "eu-central-1",
+			resourceType: "RDSInstance",
+			want: &settings.Setting{
+				"DisableDeletionProtection": false,
+				"StartClusterToDelete":      true,
+			},
+		},
+		{
+			name:         "other-account",
+			accountID:    "555133743",
+			region:       "eu-west-1",
+			resourceType: "RDSInstance",
+			want: &settings.Setting{
+				"DisableDeletionProtection": false,
+				"StartClusterToDelete":      true,
+			},
+		},
+		{
+			name:         "other-region",
+			accountID:    "555133742",
+			region:       "us-east-1",
+			resourceType: "RDSInstance",
+			want: &settings.Setting{
+				"DisableDeletionProtection": false,
+				"StartClusterToDelete":      true,
+			},
+		},
+		{
+			name:         "tag-match",
+			accountID:    "555133743",
+			region:       "eu-west-1",
+			resourceType: "S3Bucket",
+			properties:   testProperties{"tag:scratch": "true"},
+			want: &settings.Setting{
+				"BypassGovernanceRetention": true,
+			},
+		},
+		{
+			name:         "tag-mismatch",
+			accountID:    "555133743",
+			region:       "eu-west-1",
+			resourceType: "S3Bucket",
+			properties:   testProperties{"tag:scratch": "false"},
+			want:         &settings.Setting{},
+		},
+		{
+			name:         "no-properties",
+			accountID:    "555133743",
+			region:       "eu-west-1",
+			resourceType: "S3Bucket",
+			want:         &settings.Setting{},
+		},
+		{
+			name:         "multiple-overrides",
+			accountID:    "555133743",
+			region:       "us-east-1",
+			resourceType: "S3Bucket",
+			properties:   testProperties{"tag:scratch": "true", "tag:team": "data-platform"},
+			want: &settings.Setting{
+				"BypassGovernanceRetention": true,
+				"RemoveObjectLegalHold":     true,
+			},
+		},
+		{
+			name:         "tag-pattern-wrong-region",
+			accountID:    "555133743",
+			region:       "eu-west-1",
+			resourceType: "S3Bucket",
+			properties:   testProperties{"tag:team": "data-platform"},
+			want:         &settings.Setting{},
+		},
+	}
+
+	cfg, err := New(libconfig.Options{
+		Path: "testdata/settings-overrides.yaml",
+	})
+	assert.NoError(t, err)
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, cfg.ResolveSettings(tc.accountID, tc.region, tc.resourceType, tc.properties))
+		})
+	}
+
+	// The global settings must not be modified by the overrides
+	assert.Equal(t, &settings.Setting{
+		"DisableDeletionProtection": false,
+		"StartClusterToDelete":      true,
+	}, cfg.Settings.Get("RDSInstance"))
+}
+
+func TestConfig_ValidateSettingsOverrides(t *testing.T) {
+	cases := []struct {
+		name     string
+		override *SettingsOverride
+		wantErr  bool
+	}{
+		{
+			name: "valid",
+			override: &SettingsOverride{
+				Regions:  []string{"all", "!eu-*"},
+				Settings: settings.Settings{"S3Bucket": &settings.Setting{"BypassGovernanceRetention": true}},
+			},
+		},
+		{name: "empty", override: nil, wantErr: true},
+		{name: "no-settings", override: &SettingsOverride{Accounts: []string{"555133742"}}, wantErr: true},
+		{
+			name: "self-referencing-group",
+			override: &SettingsOverride{
+				Regions:  []string{"loop"},
+				Settings: settings.Settings{"S3Bucket": &settings.Setting{}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &Config{
+				RegionGroups:      RegionGroups{"loop": {"loop"}},
+				SettingsOverrides: []*SettingsOverride{tc.override},
+			}
+
+			err := cfg.ValidateSettingsOverrides()
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,161 @@
+// Package hooks runs the configured commands and HTTP endpoints around the removal of resources, so custom actions such
+// as deregistering a resource from a CMDB can be integrated without changes to the resources.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+
+	"github.com/ekristen/aws-nuke/v3/pkg/config"
+	"github.com/ekristen/aws-nuke/v3/pkg/nuke"
+)
+
+// Input is the JSON document a hook receives, on the standard input of a command or as the body of an HTTP request.
+type Input struct {
+	Stage        string            `json:"stage"`
+	ResourceType string            `json:"resourceType"`
+	Region       string            `json:"region"`
+	Name         string            `json:"name,omitempty"`
+	Properties   map[string]string `json:"properties,omitempty"`
+}
+
+// NewInput returns the input of a hook for the item at the stage.
+func NewInput(stage string, item *queue.Item) *Input {
+	return &Input{
+		Stage:        stage,
+		ResourceType: item.Type,
+		Region:       item.Owner,
+		Name:         nuke.ItemName(item),
+		Properties:   nuke.ItemProperties(item),
+	}
+}
+
+// Runner runs the configured hooks for the items that are removed.
+type Runner struct {
+	Hooks []*config.Hook
+
+	Client *http.Client
+}
+
+// Before runs the hooks of the before stage for the item, it is meant to be registered as a before remove handler. It
+// stops at the first hook that fails, which prevents the item from being removed in this attempt.
+func (r *Runner) Before(ctx context.Context, item *queue.Item) error {
+	input := NewInput(config.HookStageBefore, item)
+
+	for i, hook := range r.Hooks {
+		if hook.Stage != config.HookStageBefore || !hook.HasResourceType(item.Type) {
+			continue
+		}
+
+		if err := r.Run(ctx, hook, input); err != nil {
+			return fmt.Errorf("%s failed: %w", hookName(i, hook), err)
+		}
+	}
+
+	return nil
+}
+
+// After runs the hooks of the after stage for the item, it is meant to be registered as an after remove handler. All
+// hooks are run, even when one of them fails.
+func (r *Runner) After(ctx context.Context, item *queue.Item) error {
+	input := NewInput(config.HookStageAfter, item)
+
+	var errs []error
+	for i, hook := range r.Hooks {
+		if hook.Stage != config.HookStageAfter || !hook.HasResourceType(item.Type) {
+			continue
+		}
+
+		if err := r.Run(ctx, hook, input); err != nil {
+			errs = append(errs, fmt.Errorf("%s failed: %w", hookName(i, hook), err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Run runs a single hook with the input, a command or an HTTP request depending on the hook.
+func (r *Runner) Run(ctx context.Context, hook *config.Hook, input *Input) error {
+	body, err := json.Marshal(input)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, hook.GetTimeout())
+	defer cancel()
+
+	if len(hook.Command) > 0 {
+		return runCommand(ctx, hook, body)
+	}
+
+	return r.post(ctx, hook, body)
+}
+
+// runCommand runs the command of the hook with the input on its standard input. The output of a command that fails is
+// part of the error.
+func runCommand(ctx context.Context, hook *config.Hook, body []byte) error {
+	cmd := exec.CommandContext(ctx, hook.Command[0], hook.Command[1:]...) //nolint:gosec
+	cmd.Stdin = bytes.NewReader(body)
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if out := strings.TrimSpace(string(output)); out != "" {
+			return fmt.Errorf("%w: %s", err, out)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// post posts the input to the URL of the hook.
+func (r *Runner) post(ctx context.Context, hook *config.Hook, body []byte) error {
+	client := r.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, os.ExpandEnv(hook.URL), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range hook.Headers {
+		req.Header.Set(key, os.ExpandEnv(value))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected response status: %s", resp.Status)
+	}
+
+	return nil
+}
+
+// hookName returns the name of the hook for errors, or its position when it does not have a name.
+func hookName(i int, hook *config.Hook) string {
+	if hook.Name != "" {
+		return fmt.Sprintf("hook %s", hook.Name)
+	}
+
+	return fmt.Sprintf("hook %d", i)
+}
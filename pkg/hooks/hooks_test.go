@@ -0,0 +1,131 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+	"github.com/ekristen/libnuke/pkg/types"
+
+	"github.com/ekristen/aws-nuke/v3/pkg/config"
+)
+
+type testResource struct {
+	name string
+}
+
+func (r *testResource) Remove(_ context.Context) error {
+	return nil
+}
+
+func (r *testResource) Properties() types.Properties {
+	return types.NewProperties().
+		Set("Name", r.name).
+		Set("_tagPrefix", "tag")
+}
+
+func (r *testResource) String() string {
+	return r.name
+}
+
+func newTestItem(name string) *queue.Item {
+	return &queue.Item{
+		Resource: &testResource{name: name},
+		Type:     "TestResource",
+		Owner:    "us-east-1",
+	}
+}
+
+func TestNewInput(t *testing.T) {
+	input := NewInput(config.HookStageBefore, newTestItem("one"))
+
+	assert.Equal(t, &Input{
+		Stage:        "before",
+		ResourceType: "TestResource",
+		Region:       "us-east-1",
+		Name:         "one",
+		Properties:   map[string]string{"Name": "one"},
+	}, input)
+}
+
+func TestRunner_BeforeCommand(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "input.json")
+
+	runner := &Runner{Hooks: []*config.Hook{
+		{Stage: config.HookStageAfter, Command: []string{"sh", "-c", "exit 1"}},
+		{Stage: config.HookStageBefore, Command: []string{"sh", "-c", `cat > "$0"`, out}},
+		{Stage: config.HookStageBefore, Command: []string{"sh", "-c", "exit 1"}, ResourceTypes: []string{"OtherResource"}},
+	}}
+
+	require.NoError(t, runner.Before(context.TODO(), newTestItem("one")))
+
+	raw, err := os.ReadFile(out)
+	require.NoError(t, err)
+
+	input := &Input{}
+	require.NoError(t, json.Unmarshal(raw, input))
+	assert.Equal(t, NewInput(config.HookStageBefore, newTestItem("one")), input)
+}
+
+func TestRunner_BeforeVeto(t *testing.T) {
+	runner := &Runner{Hooks: []*config.Hook{
+		{Name: "cmdb", Stage: config.HookStageBefore, Command: []string{"sh", "-c", "echo still registered; exit 3"}},
+	}}
+
+	err := runner.Before(context.TODO(), newTestItem("one"))
+	assert.EqualError(t, err, "hook cmdb failed: exit status 3: still registered")
+}
+
+func TestRunner_AfterHTTP(t *testing.T) {
+	var requests []*Input
+	var headers []http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+
+		input := &Input{}
+		assert.NoError(t, json.Unmarshal(body, input))
+		requests = append(requests, input)
+		headers = append(headers, r.Header)
+
+		if len(requests) > 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("HOOK_TOKEN", "secret")
+
+	runner := &Runner{Hooks: []*config.Hook{
+		{Stage: config.HookStageAfter, URL: server.URL, Headers: map[string]string{"Authorization": "Bearer ${HOOK_TOKEN}"}},
+		{Stage: config.HookStageAfter, URL: server.URL},
+	}}
+
+	err := runner.After(context.TODO(), newTestItem("one"))
+	assert.EqualError(t, err, "hook 1 failed: unexpected response status: 500 Internal Server Error")
+
+	require.Len(t, requests, 2)
+	assert.Equal(t, "after", requests[0].Stage)
+	assert.Equal(t, "Bearer secret", headers[0].Get("Authorization"))
+	assert.Equal(t, "application/json", headers[0].Get("Content-Type"))
+}
+
+func TestRunner_Timeout(t *testing.T) {
+	runner := &Runner{Hooks: []*config.Hook{
+		{Stage: config.HookStageBefore, Command: []string{"sleep", "5"}, Timeout: 50 * time.Millisecond},
+	}}
+
+	start := time.Now()
+	assert.Error(t, runner.Before(context.TODO(), newTestItem("one")))
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
@@ -0,0 +1,59 @@
+// Package inventory provides a file based snapshot of resources that can be used to evaluate the configuration
+// without having to query the AWS APIs.
+package inventory
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Resource is a single resource in an inventory snapshot.
+type Resource struct {
+	// Type is the aws-nuke resource type, for example S3Bucket
+	Type string `json:"type"`
+
+	// Region is the region the resource was found in, including the special "global" region
+	Region string `json:"region"`
+
+	// Name is the legacy string representation of the resource, used by filters without a property
+	Name string `json:"name,omitempty"`
+
+	// Properties are the properties of the resource as they would be returned by Properties()
+	Properties map[string]string `json:"properties"`
+}
+
+// GetProperty returns the value of a property using the same semantics as a queued item during a run. An empty key
+// refers to the legacy string representation of the resource.
+func (r *Resource) GetProperty(key string) (string, error) {
+	if key == "" {
+		if r.Name == "" {
+			return "", fmt.Errorf("%s does not support legacy IDs", r.Type)
+		}
+
+		return r.Name, nil
+	}
+
+	return r.Properties[key], nil
+}
+
+// Load reads an inventory snapshot from a JSON file. The file must contain a list of resources.
+func Load(path string) ([]*Resource, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []*Resource
+	if err := json.Unmarshal(raw, &resources); err != nil {
+		return nil, fmt.Errorf("unable to parse inventory %s: %w", path, err)
+	}
+
+	for i, r := range resources {
+		if r == nil || r.Type == "" {
+			return nil, fmt.Errorf("inventory %s: resource %d is missing a type", path, i)
+		}
+	}
+
+	return resources, nil
+}
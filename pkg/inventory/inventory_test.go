@@ -0,0 +1,100 @@
+package inventory
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeInventory(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "inventory.json")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestLoad_RoundTrip(t *testing.T) {
+	resources := []*Resource{
+		{
+			Type:       "S3Bucket",
+			Region:     "global",
+			Name:       "s3://my-bucket",
+			Properties: map[string]string{"Name": "my-bucket", "tag:env": "dev"},
+		},
+		{
+			Type:       "IAMRole",
+			Region:     "global",
+			Properties: map[string]string{"Name": "admin"},
+		},
+	}
+
+	raw, err := json.Marshal(resources)
+	require.NoError(t, err)
+
+	loaded, err := Load(writeInventory(t, string(raw)))
+	require.NoError(t, err)
+	assert.Equal(t, resources, loaded)
+}
+
+func TestLoad_Malformed(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name:    "invalid-json",
+			content: `[{"type": "S3Bucket",`,
+			wantErr: "unable to parse inventory",
+		},
+		{
+			name:    "not-a-list",
+			content: `{"type": "S3Bucket"}`,
+			wantErr: "unable to parse inventory",
+		},
+		{
+			name:    "missing-type",
+			content: `[{"type": "S3Bucket"}, {"region": "us-east-1"}]`,
+			wantErr: "resource 1 is missing a type",
+		},
+		{
+			name:    "empty-resource",
+			content: `[null]`,
+			wantErr: "resource 0 is missing a type",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Load(writeInventory(t, tc.content))
+			assert.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestLoad_NotFound(t *testing.T) {
+	_, err := Load(filepath.Join(t.TempDir(), "missing.json"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestResource_GetProperty(t *testing.T) {
+	r := &Resource{Type: "S3Bucket", Name: "s3://my-bucket", Properties: map[string]string{"Name": "my-bucket"}}
+
+	value, err := r.GetProperty("")
+	assert.NoError(t, err)
+	assert.Equal(t, "s3://my-bucket", value)
+
+	value, err = r.GetProperty("Name")
+	assert.NoError(t, err)
+	assert.Equal(t, "my-bucket", value)
+
+	value, err = r.GetProperty("Missing")
+	assert.NoError(t, err)
+	assert.Equal(t, "", value)
+
+	_, err = (&Resource{Type: "IAMRole"}).GetProperty("")
+	assert.EqualError(t, err, "IAMRole does not support legacy IDs")
+}
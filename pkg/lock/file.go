@@ -0,0 +1,190 @@
+package lock
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileBackend stores each lock in a file in a directory. The file is created exclusively, so only one run can hold the
+// lock at a time.
+type FileBackend struct {
+	directory string
+}
+
+// NewFileBackend returns a backend that stores the locks in the directory.
+func NewFileBackend(directory string) *FileBackend {
+	return &FileBackend{
+		directory: directory,
+	}
+}
+
+func (b *FileBackend) path(accountID string) string {
+	return filepath.Join(b.directory, fmt.Sprintf("aws-nuke-%s.lock", accountID))
+}
+
+// Acquire creates the lock file for the account, an expired lock file is replaced.
+//
+// The lock file is written to a temporary file first and linked to its path, so it is created exclusively and never
+// read half written. Only the run that creates the takeover file of an expired lock may replace it, so two runs that
+// find the same expired lock cannot both take it over.
+func (b *FileBackend) Acquire(_ context.Context, info *Info) error {
+	raw, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+
+	path := b.path(info.AccountID)
+
+	for attempt := 0; attempt < 2; attempt++ {
+		err = b.create(path, raw)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return err
+		}
+
+		held, err := b.read(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if !held.Expired(time.Now()) {
+			return &HeldError{Info: held}
+		}
+
+		return b.takeOver(path, held, raw)
+	}
+
+	return fmt.Errorf("unable to acquire the lock of account %s, it changed while acquiring it", info.AccountID)
+}
+
+// takeOver replaces the expired lock held by the run of held with the lock in raw.
+func (b *FileBackend) takeOver(path string, held *Info, raw []byte) error {
+	guard, err := os.OpenFile(b.takeOverPath(path, held.ID), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("the expired lock of account %s is being taken over by another run", held.AccountID)
+	}
+	if err != nil {
+		return err
+	}
+	_ = guard.Close()
+	defer os.Remove(guard.Name())
+
+	if _, err := b.removeIf(path, held.ID); err != nil {
+		return err
+	}
+
+	err = b.create(path, raw)
+	if errors.Is(err, os.ErrExist) {
+		// Another run acquired the lock after the expired lock was removed
+		current, err := b.read(path)
+		if err != nil {
+			return err
+		}
+
+		return &HeldError{Info: current}
+	}
+
+	return err
+}
+
+// Release removes the lock file when it is still held by the info.
+func (b *FileBackend) Release(_ context.Context, info *Info) error {
+	_, err := b.removeIf(b.path(info.AccountID), info.ID)
+	return err
+}
+
+// Break removes the lock file of the account and the takeover files left behind by runs that were killed while
+// taking over its expired lock.
+func (b *FileBackend) Break(_ context.Context, accountID string) error {
+	path := b.path(accountID)
+
+	guards, err := filepath.Glob(b.takeOverPath(path, "*"))
+	if err != nil {
+		return err
+	}
+
+	for _, name := range append(guards, path) {
+		if err := os.Remove(name); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (b *FileBackend) takeOverPath(path, id string) string {
+	return fmt.Sprintf("%s.%s.takeover", path, id)
+}
+
+// create writes raw to a temporary file and links it to path, it returns an error matching os.ErrExist when path
+// already exists.
+func (b *FileBackend) create(path string, raw []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(raw); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Link(tmp.Name(), path)
+}
+
+// removeIf removes the lock file when it is held by the run with the id. The file is moved aside before its holder is
+// checked, a lock of another run that was moved is put back.
+func (b *FileBackend) removeIf(path, id string) (bool, error) {
+	moved := fmt.Sprintf("%s.%s.stale", path, randomID())
+	if err := os.Rename(path, moved); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	defer os.Remove(moved)
+
+	held, err := b.read(moved)
+	if err != nil {
+		return false, err
+	}
+
+	if held.ID == id {
+		return true, nil
+	}
+
+	// Another run acquired the lock in the meantime, put its lock back unless yet another run has created one since
+	if err := os.Link(moved, path); err != nil && !errors.Is(err, os.ErrExist) {
+		return false, err
+	}
+
+	return false, nil
+}
+
+func (b *FileBackend) read(path string) (*Info, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &Info{}
+	if err := json.Unmarshal(raw, info); err != nil {
+		return nil, fmt.Errorf("unable to parse lock file %s: %w", path, err)
+	}
+
+	return info, nil
+}
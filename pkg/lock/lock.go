@@ -0,0 +1,96 @@
+// Package lock prevents concurrent runs against the same account. A run acquires the lock of the account before it
+// starts and releases it when it is done, a lock that is not released expires after its TTL.
+package lock
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+
+	"github.com/ekristen/aws-nuke/v3/pkg/awsutil"
+	"github.com/ekristen/aws-nuke/v3/pkg/config"
+)
+
+// Info describes the holder of a lock.
+type Info struct {
+	ID         string    `json:"id"`
+	AccountID  string    `json:"accountId"`
+	Holder     string    `json:"holder"`
+	AcquiredAt time.Time `json:"acquiredAt"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+}
+
+// NewInfo returns the lock info for a new holder of the lock of the account.
+func NewInfo(accountID, holder string, ttl time.Duration) *Info {
+	now := time.Now().UTC()
+
+	return &Info{
+		ID:         randomID(),
+		AccountID:  accountID,
+		Holder:     holder,
+		AcquiredAt: now,
+		ExpiresAt:  now.Add(ttl),
+	}
+}
+
+func randomID() string {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+
+	return hex.EncodeToString(id)
+}
+
+// Expired returns true if the lock has expired at the time.
+func (i *Info) Expired(now time.Time) bool {
+	return !now.Before(i.ExpiresAt)
+}
+
+// HeldError is returned when the lock of the account is held by someone else.
+type HeldError struct {
+	Info *Info
+}
+
+func (e *HeldError) Error() string {
+	return fmt.Sprintf("account %s is locked by %s since %s until %s, use --break-lock to clear it",
+		e.Info.AccountID, e.Info.Holder, e.Info.AcquiredAt.Format(time.RFC3339), e.Info.ExpiresAt.Format(time.RFC3339))
+}
+
+// Backend stores the locks.
+type Backend interface {
+	// Acquire acquires the lock for the account of the info. It returns a HeldError when the lock is held by someone
+	// else and has not expired. An expired lock is taken over.
+	Acquire(ctx context.Context, info *Info) error
+
+	// Release releases the lock, it does nothing when the lock is no longer held by the info.
+	Release(ctx context.Context, info *Info) error
+
+	// Break clears the lock of the account regardless of who holds it.
+	Break(ctx context.Context, accountID string) error
+}
+
+// ConfigFactory returns the SDK v2 config for a region and service, it matches awsutil.Credentials.NewConfig.
+type ConfigFactory func(ctx context.Context, region, serviceType string) (*aws.Config, error)
+
+// NewBackend returns the backend for the lock configuration.
+func NewBackend(ctx context.Context, cfg *config.Lock, configFactory ConfigFactory) (Backend, error) {
+	if cfg.Directory != "" {
+		return NewFileBackend(cfg.Directory), nil
+	}
+
+	region := cfg.Region
+	if region == "" {
+		region = awsutil.DefaultRegionID
+	}
+
+	awsCfg, err := configFactory(ctx, region, "s3")
+	if err != nil {
+		return nil, err
+	}
+
+	return NewS3Backend(s3.NewFromConfig(*awsCfg), cfg.Bucket, cfg.Prefix), nil
+}
@@ -0,0 +1,226 @@
+package lock
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+)
+
+func TestInfo_Expired(t *testing.T) {
+	info := NewInfo("000000000000", "tester", time.Hour)
+
+	assert.Len(t, info.ID, 32)
+	assert.False(t, info.Expired(time.Now()))
+	assert.True(t, info.Expired(time.Now().Add(2*time.Hour)))
+}
+
+func TestHeldError(t *testing.T) {
+	at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	err := &HeldError{Info: &Info{AccountID: "000000000000", Holder: "tester", AcquiredAt: at, ExpiresAt: at.Add(time.Hour)}}
+
+	assert.EqualError(t, err, "account 000000000000 is locked by tester since 2024-01-01T00:00:00Z "+
+		"until 2024-01-01T01:00:00Z, use --break-lock to clear it")
+}
+
+func testBackend(t *testing.T, backend Backend) {
+	t.Helper()
+
+	ctx := context.TODO()
+
+	first := NewInfo("000000000000", "first", time.Hour)
+	require.NoError(t, backend.Acquire(ctx, first))
+
+	// A second holder is refused while the lock is held
+	second := NewInfo("000000000000", "second", time.Hour)
+	err := backend.Acquire(ctx, second)
+	var held *HeldError
+	require.ErrorAs(t, err, &held)
+	assert.Equal(t, "first", held.Info.Holder)
+
+	// Another account is not affected
+	other := NewInfo("111111111111", "other", time.Hour)
+	require.NoError(t, backend.Acquire(ctx, other))
+
+	// Releasing with another holder does not clear the lock
+	require.NoError(t, backend.Release(ctx, second))
+	require.ErrorAs(t, backend.Acquire(ctx, second), &held)
+
+	// Releasing with the holder clears the lock
+	require.NoError(t, backend.Release(ctx, first))
+	require.NoError(t, backend.Acquire(ctx, second))
+
+	// Breaking clears the lock regardless of the holder
+	require.NoError(t, backend.Break(ctx, "000000000000"))
+	require.NoError(t, backend.Acquire(ctx, first))
+
+	// An expired lock is taken over
+	require.NoError(t, backend.Break(ctx, "000000000000"))
+	expired := NewInfo("000000000000", "expired", -time.Minute)
+	require.NoError(t, backend.Acquire(ctx, expired))
+	require.NoError(t, backend.Acquire(ctx, second))
+
+	// Releasing the expired lock does not clear the lock of the new holder
+	require.NoError(t, backend.Release(ctx, expired))
+	require.ErrorAs(t, backend.Acquire(ctx, first), &held)
+	assert.Equal(t, "second", held.Info.Holder)
+
+	// Releasing or breaking a lock that is not held does nothing
+	require.NoError(t, backend.Release(ctx, second))
+	require.NoError(t, backend.Release(ctx, second))
+	require.NoError(t, backend.Break(ctx, "000000000000"))
+}
+
+func TestFileBackend(t *testing.T) {
+	dir := t.TempDir()
+
+	testBackend(t, NewFileBackend(dir))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "aws-nuke-111111111111.lock", entries[0].Name())
+}
+
+// fakeS3 implements the conditional writes of S3 in memory.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	etags   map[string]string
+	version int
+}
+
+func newFakeS3() *fakeS3 {
+	return &fakeS3{
+		objects: map[string][]byte{},
+		etags:   map[string]string{},
+	}
+}
+
+func (f *fakeS3) PutObject(_ context.Context, params *s3.PutObjectInput, _ ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := aws.ToString(params.Key)
+	if aws.ToString(params.IfNoneMatch) == "*" {
+		if _, ok := f.objects[key]; ok {
+			return nil, &smithy.GenericAPIError{Code: "PreconditionFailed"}
+		}
+	}
+
+	body, err := io.ReadAll(params.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	f.version++
+	f.objects[key] = body
+	f.etags[key] = fmt.Sprintf("%q", fmt.Sprint(f.version))
+
+	return &s3.PutObjectOutput{ETag: aws.String(f.etags[key])}, nil
+}
+
+func (f *fakeS3) GetObject(_ context.Context, params *s3.GetObjectInput, _ ...func(*s3.Options)) (*s3.GetObjectOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := aws.ToString(params.Key)
+	body, ok := f.objects[key]
+	if !ok {
+		return nil, &s3types.NoSuchKey{}
+	}
+
+	return &s3.GetObjectOutput{
+		Body: io.NopCloser(bytes.NewReader(body)),
+		ETag: aws.String(f.etags[key]),
+	}, nil
+}
+
+func (f *fakeS3) DeleteObject(
+	_ context.Context, params *s3.DeleteObjectInput, _ ...func(*s3.Options)) (*s3.DeleteObjectOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := aws.ToString(params.Key)
+	if params.IfMatch != nil {
+		if _, ok := f.objects[key]; !ok {
+			return nil, &smithy.GenericAPIError{Code: "NoSuchKey"}
+		}
+		if f.etags[key] != aws.ToString(params.IfMatch) {
+			return nil, &smithy.GenericAPIError{Code: "PreconditionFailed"}
+		}
+	}
+
+	delete(f.objects, key)
+	delete(f.etags, key)
+
+	return &s3.DeleteObjectOutput{}, nil
+}
+
+func TestS3Backend(t *testing.T) {
+	svc := newFakeS3()
+
+	testBackend(t, NewS3Backend(svc, "locks", "aws-nuke"))
+
+	assert.Len(t, svc.objects, 1)
+	assert.Contains(t, svc.objects, "aws-nuke/aws-nuke-111111111111.lock")
+}
+
+func TestFileBackend_ConcurrentTakeOver(t *testing.T) {
+	dir := t.TempDir()
+	backend := NewFileBackend(dir)
+	ctx := context.TODO()
+
+	require.NoError(t, backend.Acquire(ctx, NewInfo("000000000000", "expired", -time.Minute)))
+
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	results := make([]error, 64)
+	infos := make([]*Info, len(results))
+	for i := range results {
+		infos[i] = NewInfo("000000000000", fmt.Sprintf("run-%d", i), time.Hour)
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			results[i] = backend.Acquire(ctx, infos[i])
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	var holder *Info
+	for i, err := range results {
+		if err == nil {
+			require.Nil(t, holder, "the expired lock was taken over by more than one run")
+			holder = infos[i]
+		}
+	}
+	require.NotNil(t, holder, "the expired lock was not taken over")
+
+	held, err := backend.read(backend.path("000000000000"))
+	require.NoError(t, err)
+	assert.Equal(t, holder.ID, held.ID)
+
+	// No temporary, moved or takeover files are left behind
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	require.NoError(t, backend.Release(ctx, holder))
+	entries, err = os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
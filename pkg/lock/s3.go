@@ -0,0 +1,174 @@
+package lock
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+)
+
+// S3API is the subset of the S3 client that is used by the S3 backend.
+type S3API interface {
+	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+	GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error)
+	DeleteObject(ctx context.Context, params *s3.DeleteObjectInput,
+		optFns ...func(*s3.Options)) (*s3.DeleteObjectOutput, error)
+}
+
+// S3Backend stores each lock as an object in a bucket. Conditional writes ensure only one run can create the object.
+type S3Backend struct {
+	svc    S3API
+	bucket string
+	prefix string
+}
+
+// NewS3Backend returns a backend that stores the locks in the bucket under the prefix.
+func NewS3Backend(svc S3API, bucket, prefix string) *S3Backend {
+	return &S3Backend{
+		svc:    svc,
+		bucket: bucket,
+		prefix: prefix,
+	}
+}
+
+func (b *S3Backend) key(accountID string) string {
+	return path.Join(b.prefix, fmt.Sprintf("aws-nuke-%s.lock", accountID))
+}
+
+// Acquire creates the lock object for the account if it does not exist, an expired lock object is replaced.
+func (b *S3Backend) Acquire(ctx context.Context, info *Info) error {
+	raw, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+
+	key := b.key(info.AccountID)
+
+	for attempt := 0; attempt < 2; attempt++ {
+		_, err := b.svc.PutObject(ctx, &s3.PutObjectInput{
+			Bucket:      aws.String(b.bucket),
+			Key:         aws.String(key),
+			Body:        bytes.NewReader(raw),
+			IfNoneMatch: aws.String("*"),
+			ContentType: aws.String("application/json"),
+		})
+		if err == nil {
+			return nil
+		}
+
+		if !isConditionFailed(err) {
+			return err
+		}
+
+		held, etag, err := b.read(ctx, key)
+		if err != nil {
+			return err
+		}
+		if held == nil {
+			continue
+		}
+
+		if !held.Expired(time.Now()) {
+			return &HeldError{Info: held}
+		}
+
+		// Only the expired lock is removed, if someone else replaced it in the meantime the next attempt reports it
+		if _, err := b.svc.DeleteObject(ctx, &s3.DeleteObjectInput{
+			Bucket:  aws.String(b.bucket),
+			Key:     aws.String(key),
+			IfMatch: etag,
+		}); err != nil && !isConditionFailed(err) {
+			return err
+		}
+	}
+
+	return fmt.Errorf("unable to acquire the lock of account %s, it changed while acquiring it", info.AccountID)
+}
+
+// Release removes the lock object when it is still held by the info.
+func (b *S3Backend) Release(ctx context.Context, info *Info) error {
+	key := b.key(info.AccountID)
+
+	held, etag, err := b.read(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	if held == nil || held.ID != info.ID {
+		return nil
+	}
+
+	_, err = b.svc.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket:  aws.String(b.bucket),
+		Key:     aws.String(key),
+		IfMatch: etag,
+	})
+	if isConditionFailed(err) {
+		return nil
+	}
+
+	return err
+}
+
+// Break removes the lock object of the account.
+func (b *S3Backend) Break(ctx context.Context, accountID string) error {
+	_, err := b.svc.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(b.bucket),
+		Key:    aws.String(b.key(accountID)),
+	})
+
+	return err
+}
+
+// read returns the lock object and its ETag, or nil when the object does not exist.
+func (b *S3Backend) read(ctx context.Context, key string) (*Info, *string, error) {
+	resp, err := b.svc.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(b.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		var noSuchKey *s3types.NoSuchKey
+		if errors.As(err, &noSuchKey) {
+			return nil, nil, nil
+		}
+
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	info := &Info{}
+	if err := json.Unmarshal(raw, info); err != nil {
+		return nil, nil, fmt.Errorf("unable to parse lock object s3://%s/%s: %w", b.bucket, key, err)
+	}
+
+	return info, resp.ETag, nil
+}
+
+// isConditionFailed returns true if the error is caused by a failed conditional write.
+func isConditionFailed(err error) bool {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	switch apiErr.ErrorCode() {
+	case "PreconditionFailed", "ConditionalRequestConflict":
+		return true
+	default:
+		return false
+	}
+}
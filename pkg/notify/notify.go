@@ -0,0 +1,238 @@
+// Package notify sends notifications about the lifecycle events of a run to Slack, Microsoft Teams or a generic HTTP
+// endpoint.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+
+	"github.com/ekristen/aws-nuke/v3/pkg/config"
+	"github.com/ekristen/aws-nuke/v3/pkg/nuke"
+)
+
+// DefaultTimeout is how long a single notification may take before it is abandoned.
+const DefaultTimeout = 10 * time.Second
+
+// DefaultTemplate is the template used to render the message when a notification does not configure one.
+const DefaultTemplate = `{{ if eq .Event "run-start" -}}
+aws-nuke run started for account {{ .Account }}{{ if .DryRun }} (dry run){{ end }}
+{{- else if eq .Event "scan-complete" -}}
+aws-nuke scan completed for account {{ .Account }}{{ if .DryRun }} (dry run){{ end }}: ` +
+	`{{ .Totals.Removable }} of {{ .Totals.Total }} resources would be removed, {{ .Totals.Filtered }} filtered
+{{- range $type, $totals := .ResourceTypes }}{{ if $totals.Removable }}
+> {{ $type }}: {{ $totals.Removable }}{{ end }}{{ end }}
+{{- else if eq .Event "removal-complete" -}}
+aws-nuke removal completed for account {{ .Account }}: ` +
+	`{{ .Totals.Finished }} resources removed, {{ .Totals.Filtered }} filtered
+{{- else if eq .Event "removal-failed" -}}
+aws-nuke removal failed for account {{ .Account }}: ` +
+	`{{ .Totals.Failed }} resources could not be removed, {{ .Totals.Finished }} removed
+{{- range $i, $failure := .Failures }}{{ if lt $i 20 }}
+> {{ $failure.ResourceType }} - {{ $failure.Region }} - {{ $failure.Resource }}: {{ $failure.Reason }}{{ end }}{{ end }}
+{{- end }}`
+
+// Totals are the number of resources in each state of a run.
+type Totals struct {
+	Total     int `json:"total"`
+	Removable int `json:"removable"`
+	Filtered  int `json:"filtered"`
+	Finished  int `json:"finished"`
+	Failed    int `json:"failed"`
+}
+
+// Failure is a resource that could not be removed.
+type Failure struct {
+	ResourceType string `json:"resourceType"`
+	Region       string `json:"region"`
+	Resource     string `json:"resource"`
+	Reason       string `json:"reason"`
+}
+
+// Data is the data a notification template is rendered with. It is also the body of a webhook notification that does
+// not configure a template.
+type Data struct {
+	Event         string             `json:"event"`
+	AccountID     string             `json:"accountId"`
+	AccountAlias  string             `json:"accountAlias,omitempty"`
+	DryRun        bool               `json:"dryRun"`
+	Time          time.Time          `json:"time"`
+	Totals        Totals             `json:"totals"`
+	ResourceTypes map[string]*Totals `json:"resourceTypes,omitempty"`
+	Failures      []Failure          `json:"failures,omitempty"`
+}
+
+// Account returns the alias and ID of the account, or only the ID when the account does not have an alias.
+func (d *Data) Account() string {
+	if d.AccountAlias == "" {
+		return d.AccountID
+	}
+
+	return fmt.Sprintf("%s (%s)", d.AccountAlias, d.AccountID)
+}
+
+// NewData returns the data for an event of a run, with the totals counted from the queue.
+func NewData(event nuke.RunEvent, q *queue.Queue) *Data {
+	data := &Data{
+		Event:         string(event),
+		Time:          time.Now().UTC(),
+		ResourceTypes: make(map[string]*Totals),
+	}
+
+	if q == nil {
+		return data
+	}
+
+	for _, item := range q.GetItems() {
+		totals, ok := data.ResourceTypes[item.Type]
+		if !ok {
+			totals = &Totals{}
+			data.ResourceTypes[item.Type] = totals
+		}
+
+		for _, t := range []*Totals{&data.Totals, totals} {
+			t.Total++
+
+			switch item.GetState() {
+			case queue.ItemStateNew, queue.ItemStateNewDependency:
+				t.Removable++
+			case queue.ItemStateFiltered:
+				t.Filtered++
+			case queue.ItemStateFinished:
+				t.Finished++
+			case queue.ItemStateFailed:
+				t.Failed++
+			}
+		}
+
+		if item.GetState() == queue.ItemStateFailed {
+			data.Failures = append(data.Failures, Failure{
+				ResourceType: item.Type,
+				Region:       item.Owner,
+				Resource:     nuke.ItemName(item),
+				Reason:       item.GetReason(),
+			})
+		}
+	}
+
+	return data
+}
+
+// Notifier sends the configured notifications for the lifecycle events of a run.
+type Notifier struct {
+	Notifications []*config.Notification
+	AccountID     string
+	AccountAlias  string
+	DryRun        bool
+
+	Client *http.Client
+	Logger *logrus.Entry
+}
+
+// Handle sends the notifications for the event, it is meant to be registered as a run event handler. Failing to send
+// a notification is logged but never fails the run.
+func (n *Notifier) Handle(ctx context.Context, event nuke.RunEvent, q *queue.Queue) {
+	data := NewData(event, q)
+	data.AccountID = n.AccountID
+	data.AccountAlias = n.AccountAlias
+	data.DryRun = n.DryRun
+
+	for _, notification := range n.Notifications {
+		if !notification.HasEvent(data.Event) {
+			continue
+		}
+
+		if err := n.Send(ctx, notification, data); err != nil && n.Logger != nil {
+			n.Logger.WithError(err).Warnf("unable to send %s notification for %s", notification.Type, data.Event)
+		}
+	}
+}
+
+// Send renders the payload of the notification with the data and posts it to the endpoint.
+func (n *Notifier) Send(ctx context.Context, notification *config.Notification, data *Data) error {
+	body, err := Payload(notification, data)
+	if err != nil {
+		return err
+	}
+
+	client := n.Client
+	if client == nil {
+		client = &http.Client{Timeout: DefaultTimeout}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, os.ExpandEnv(notification.URL), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range notification.Headers {
+		req.Header.Set(key, os.ExpandEnv(value))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected response status: %s", resp.Status)
+	}
+
+	return nil
+}
+
+// Payload returns the body of the request for the notification. Slack and Teams receive the rendered message in the
+// format of their incoming webhooks, a generic webhook receives the rendered template as-is or the data as JSON when
+// it does not configure a template.
+func Payload(notification *config.Notification, data *Data) ([]byte, error) {
+	if notification.Type == config.NotificationTypeWebhook && notification.Template == "" {
+		return json.Marshal(data)
+	}
+
+	tmpl := notification.Template
+	if tmpl == "" {
+		tmpl = DefaultTemplate
+	}
+
+	t, err := template.New("notification").Parse(tmpl)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+
+	switch notification.Type {
+	case config.NotificationTypeSlack:
+		return json.Marshal(map[string]string{
+			"text": buf.String(),
+		})
+	case config.NotificationTypeTeams:
+		// Teams renders the text as markdown, where a single line break does not start a new line
+		return json.Marshal(map[string]string{
+			"@type":    "MessageCard",
+			"@context": "https://schema.org/extensions",
+			"summary":  fmt.Sprintf("aws-nuke %s", data.Event),
+			"text":     strings.ReplaceAll(buf.String(), "\n", "\n\n"),
+		})
+	default:
+		return buf.Bytes(), nil
+	}
+}
@@ -0,0 +1,197 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+
+	"github.com/ekristen/aws-nuke/v3/pkg/config"
+	"github.com/ekristen/aws-nuke/v3/pkg/nuke"
+)
+
+type testResource struct {
+	name string
+}
+
+func (r *testResource) Remove(_ context.Context) error {
+	return nil
+}
+
+func (r *testResource) String() string {
+	return r.name
+}
+
+type testRequest struct {
+	Headers http.Header
+	Body    []byte
+}
+
+// newTestServer returns a server that records the requests it receives.
+func newTestServer(t *testing.T, status int) (server *httptest.Server, requests func() []testRequest) {
+	var mu sync.Mutex
+	var received []testRequest
+
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+
+		mu.Lock()
+		received = append(received, testRequest{Headers: r.Header, Body: body})
+		mu.Unlock()
+
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []testRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return received
+	}
+}
+
+func testQueue() *queue.Queue {
+	q := queue.New()
+	q.Items = []*queue.Item{
+		{Type: "EC2Instance", Owner: "us-east-1", State: queue.ItemStateFinished, Resource: &testResource{"i-1"}},
+		{Type: "EC2Instance", Owner: "us-east-1", State: queue.ItemStateFiltered, Resource: &testResource{"i-2"}},
+		{
+			Type: "S3Bucket", Owner: "global", State: queue.ItemStateFailed, Reason: "access denied",
+			Resource: &testResource{"s3://bucket"},
+		},
+		{Type: "S3Bucket", Owner: "global", State: queue.ItemStateNew, Resource: &testResource{"s3://other"}},
+	}
+
+	return q
+}
+
+func TestNewData(t *testing.T) {
+	data := NewData(nuke.RunEventRemovalFailed, testQueue())
+
+	assert.Equal(t, "removal-failed", data.Event)
+	assert.Equal(t, Totals{Total: 4, Removable: 1, Filtered: 1, Finished: 1, Failed: 1}, data.Totals)
+	assert.Equal(t, &Totals{Total: 2, Filtered: 1, Finished: 1}, data.ResourceTypes["EC2Instance"])
+	assert.Equal(t, &Totals{Total: 2, Removable: 1, Failed: 1}, data.ResourceTypes["S3Bucket"])
+	assert.Equal(t, []Failure{
+		{ResourceType: "S3Bucket", Region: "global", Resource: "s3://bucket", Reason: "access denied"},
+	}, data.Failures)
+}
+
+func TestData_Account(t *testing.T) {
+	assert.Equal(t, "123456789012", (&Data{AccountID: "123456789012"}).Account())
+	assert.Equal(t, "sandbox (123456789012)", (&Data{AccountID: "123456789012", AccountAlias: "sandbox"}).Account())
+}
+
+func TestNotifier_HandleSlack(t *testing.T) {
+	server, requests := newTestServer(t, http.StatusOK)
+
+	notifier := &Notifier{
+		Notifications: []*config.Notification{
+			{Type: config.NotificationTypeSlack, URL: server.URL},
+		},
+		AccountID:    "123456789012",
+		AccountAlias: "sandbox",
+	}
+
+	notifier.Handle(context.TODO(), nuke.RunEventRemovalFailed, testQueue())
+
+	received := requests()
+	if !assert.Len(t, received, 1) {
+		return
+	}
+
+	assert.Equal(t, "application/json", received[0].Headers.Get("Content-Type"))
+
+	var payload map[string]string
+	assert.NoError(t, json.Unmarshal(received[0].Body, &payload))
+	assert.Equal(t, "aws-nuke removal failed for account sandbox (123456789012): "+
+		"1 resources could not be removed, 1 removed\n"+
+		"> S3Bucket - global - s3://bucket: access denied", payload["text"])
+}
+
+func TestNotifier_HandleTeams(t *testing.T) {
+	server, requests := newTestServer(t, http.StatusOK)
+
+	notifier := &Notifier{
+		Notifications: []*config.Notification{
+			{Type: config.NotificationTypeTeams, URL: server.URL},
+		},
+		AccountID: "123456789012",
+		DryRun:    true,
+	}
+
+	notifier.Handle(context.TODO(), nuke.RunEventScanComplete, testQueue())
+
+	received := requests()
+	if !assert.Len(t, received, 1) {
+		return
+	}
+
+	var payload map[string]string
+	assert.NoError(t, json.Unmarshal(received[0].Body, &payload))
+	assert.Equal(t, "MessageCard", payload["@type"])
+	assert.Equal(t, "aws-nuke scan-complete", payload["summary"])
+	assert.Equal(t, "aws-nuke scan completed for account 123456789012 (dry run): "+
+		"1 of 4 resources would be removed, 1 filtered\n\n"+
+		"> S3Bucket: 1", payload["text"])
+}
+
+func TestNotifier_HandleWebhook(t *testing.T) {
+	server, requests := newTestServer(t, http.StatusAccepted)
+	t.Setenv("AWS_NUKE_TEST_WEBHOOK_TOKEN", "secret")
+
+	notifier := &Notifier{
+		Notifications: []*config.Notification{
+			{
+				Type:    config.NotificationTypeWebhook,
+				URL:     server.URL,
+				Headers: map[string]string{"Authorization": "Bearer ${AWS_NUKE_TEST_WEBHOOK_TOKEN}"},
+			},
+			{
+				Type:     config.NotificationTypeWebhook,
+				URL:      server.URL,
+				Template: `{"account": "{{ .AccountID }}", "failed": {{ .Totals.Failed }}}`,
+			},
+			{
+				Type:   config.NotificationTypeWebhook,
+				URL:    server.URL,
+				Events: []string{config.NotificationEventRunStart},
+			},
+		},
+		AccountID: "123456789012",
+	}
+
+	notifier.Handle(context.TODO(), nuke.RunEventRemovalComplete, testQueue())
+
+	received := requests()
+	if !assert.Len(t, received, 2) {
+		return
+	}
+
+	assert.Equal(t, "Bearer secret", received[0].Headers.Get("Authorization"))
+
+	var data Data
+	assert.NoError(t, json.Unmarshal(received[0].Body, &data))
+	assert.Equal(t, "removal-complete", data.Event)
+	assert.Equal(t, "123456789012", data.AccountID)
+	assert.Equal(t, 1, data.ResourceTypes["EC2Instance"].Finished)
+
+	assert.JSONEq(t, `{"account": "123456789012", "failed": 1}`, string(received[1].Body))
+}
+
+func TestNotifier_SendError(t *testing.T) {
+	server, _ := newTestServer(t, http.StatusInternalServerError)
+
+	notifier := &Notifier{}
+	err := notifier.Send(context.TODO(), &config.Notification{Type: config.NotificationTypeSlack, URL: server.URL},
+		NewData(nuke.RunEventStart, nil))
+	assert.EqualError(t, err, "unexpected response status: 500 Internal Server Error")
+}
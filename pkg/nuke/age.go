@@ -0,0 +1,83 @@
+package nuke
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+	"github.com/ekristen/libnuke/pkg/resource"
+)
+
+// CreationTimeGetter is implemented by resources that know when they were created but do not expose it as one of the
+// CreationTimeProperties in a format that can be parsed.
+type CreationTimeGetter interface {
+	GetCreationTime() *time.Time
+}
+
+// CreationTimeProperties are the properties that resources use for the time they were created, in order of preference.
+var CreationTimeProperties = []string{
+	"CreationDate",
+	"CreationTime",
+	"CreatedTime",
+	"CreatedDate",
+	"CreatedAt",
+	"CreateDate",
+	"CreateTime",
+	"LaunchTime",
+}
+
+// creationTimeLayouts are the layouts the creation time properties are parsed with. Properties set from a time.Time
+// use RFC3339, the last one is the format of time.Time.String.
+var creationTimeLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC3339,
+	"2006-01-02 15:04:05.999999999 -0700 MST",
+}
+
+// CreationTime returns the time a resource was created, or nil when it is not known. Resources that implement the
+// CreationTimeGetter are asked first, otherwise the first creation time property that can be parsed is used.
+func CreationTime(r resource.Resource) *time.Time {
+	if getter, ok := r.(CreationTimeGetter); ok {
+		if createdAt := getter.GetCreationTime(); createdAt != nil {
+			return createdAt
+		}
+	}
+
+	getter, ok := r.(resource.PropertyGetter)
+	if !ok {
+		return nil
+	}
+
+	properties := getter.Properties()
+	for _, key := range CreationTimeProperties {
+		value := properties.Get(key)
+		if value == "" {
+			continue
+		}
+
+		for _, layout := range creationTimeLayouts {
+			if createdAt, err := time.Parse(layout, value); err == nil {
+				return &createdAt
+			}
+		}
+	}
+
+	return nil
+}
+
+// MinAgeFilter returns an item filter that filters every resource that was created less than the minimum age ago.
+// Resources whose creation time is not known are not filtered.
+func MinAgeFilter(minAge time.Duration, now func() time.Time) ItemFilter {
+	return func(item *queue.Item) error {
+		createdAt := CreationTime(item.Resource)
+		if createdAt == nil {
+			return nil
+		}
+
+		if age := now().Sub(*createdAt); age < minAge {
+			return fmt.Errorf("created %s ago, younger than min-age %s", age.Truncate(time.Second), minAge)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,120 @@
+package nuke
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gotidy/ptr"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+	"github.com/ekristen/libnuke/pkg/types"
+)
+
+type testAgeResource struct {
+	properties types.Properties
+}
+
+func (r *testAgeResource) Remove(_ context.Context) error {
+	return nil
+}
+
+func (r *testAgeResource) Properties() types.Properties {
+	return r.properties
+}
+
+type testAgeGetterResource struct {
+	testAgeResource
+	createdAt *time.Time
+}
+
+func (r *testAgeGetterResource) GetCreationTime() *time.Time {
+	return r.createdAt
+}
+
+func TestCreationTime(t *testing.T) {
+	createdAt := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+
+	cases := []struct {
+		name     string
+		resource *testAgeResource
+		want     *time.Time
+	}{
+		{
+			name:     "rfc3339",
+			resource: &testAgeResource{types.NewProperties().Set("LaunchTime", createdAt)},
+			want:     &createdAt,
+		},
+		{
+			name:     "rfc3339-nano",
+			resource: &testAgeResource{types.NewProperties().Set("CreatedAt", "2024-05-01T12:30:00.000Z")},
+			want:     &createdAt,
+		},
+		{
+			name:     "time-string",
+			resource: &testAgeResource{types.NewProperties().Set("CreateDate", createdAt.String())},
+			want:     &createdAt,
+		},
+		{
+			name: "preference",
+			resource: &testAgeResource{types.NewProperties().
+				Set("LaunchTime", createdAt.Add(time.Hour)).
+				Set("CreationDate", createdAt)},
+			want: &createdAt,
+		},
+		{
+			name: "skip-unparsable",
+			resource: &testAgeResource{types.NewProperties().
+				Set("CreationDate", "0xc000123456").
+				Set("CreatedTime", createdAt)},
+			want: &createdAt,
+		},
+		{
+			name:     "unknown",
+			resource: &testAgeResource{types.NewProperties().Set("Name", "test")},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := CreationTime(tc.resource)
+			if tc.want == nil {
+				assert.Nil(t, got)
+				return
+			}
+
+			assert.NotNil(t, got)
+			assert.True(t, tc.want.Equal(*got), "got %s", got)
+		})
+	}
+}
+
+func TestCreationTime_Getter(t *testing.T) {
+	createdAt := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+
+	r := &testAgeGetterResource{
+		testAgeResource: testAgeResource{types.NewProperties().Set("CreatedTime", "0xc000123456")},
+		createdAt:       &createdAt,
+	}
+	assert.Equal(t, &createdAt, CreationTime(r))
+
+	// The properties are used when the getter does not know the creation time
+	r.createdAt = nil
+	r.properties.Set("CreatedTime", createdAt.Add(time.Hour))
+	assert.Equal(t, createdAt.Add(time.Hour), *CreationTime(r))
+}
+
+func TestMinAgeFilter(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+	filter := MinAgeFilter(30*time.Minute, func() time.Time { return now })
+
+	young := &queue.Item{Resource: &testAgeResource{types.NewProperties().Set("CreationDate", now.Add(-5*time.Minute))}}
+	assert.EqualError(t, filter(young), "created 5m0s ago, younger than min-age 30m0s")
+
+	old := &queue.Item{Resource: &testAgeResource{types.NewProperties().Set("CreationDate", now.Add(-time.Hour))}}
+	assert.NoError(t, filter(old))
+
+	unknown := &queue.Item{Resource: &testAgeResource{types.NewProperties().Set("Name", ptr.String("test"))}}
+	assert.NoError(t, filter(unknown))
+}
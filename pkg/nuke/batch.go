@@ -0,0 +1,184 @@
+package nuke
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	liberrors "github.com/ekristen/libnuke/pkg/errors"
+	"github.com/ekristen/libnuke/pkg/queue"
+	"github.com/ekristen/libnuke/pkg/registry"
+	"github.com/ekristen/libnuke/pkg/resource"
+)
+
+// BatchRemover is an optional capability of listers of resource types that can be removed with a batch API. The
+// removal loop removes the resources of such a resource type in a region together instead of one at a time, and the
+// pages of a stream lister are removed with it. It returns an error for each resource, nil for the resources that were
+// removed. The batch remover splits the resources into as many requests as the batch API requires.
+type BatchRemover interface {
+	RemoveBatch(ctx context.Context, opts interface{}, resources []resource.Resource) []error
+}
+
+// RemoveEach removes the resources one at a time, it is used by batch removers when a batch API fails as a whole.
+func RemoveEach(ctx context.Context, resources []resource.Resource) []error {
+	errs := make([]error, len(resources))
+	for i, r := range resources {
+		errs[i] = r.Remove(ctx)
+	}
+
+	return errs
+}
+
+// removeBatch removes the resources of the items with the batch remover of the lister when it has one, and one at a
+// time otherwise. It returns an error for each item.
+func removeBatch(ctx context.Context, lister interface{}, opts interface{}, items []*queue.Item) []error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	resources := make([]resource.Resource, len(items))
+	for i, item := range items {
+		resources[i] = item.Resource
+	}
+
+	remover, ok := lister.(BatchRemover)
+	if !ok {
+		return RemoveEach(ctx, resources)
+	}
+
+	results := remover.RemoveBatch(ctx, opts, resources)
+	if len(results) == len(items) {
+		return results
+	}
+
+	// A batch remover that does not return an error per resource is a bug, nothing is known to be removed
+	errs := make([]error, len(items))
+	for i := range errs {
+		errs[i] = fmt.Errorf("batch remover returned %d results for %d resources", len(results), len(items))
+	}
+
+	return errs
+}
+
+// HandleRemoveBatches removes the items that are ready for removal and whose resource type has a batch remover, one
+// batch per owner and resource type. The items are filtered and handed to the before remove handlers one by one
+// first. It returns the state each handled item had before, the removal loop does not remove them again.
+func (n *Nuke) HandleRemoveBatches(ctx context.Context) map[*queue.Item]queue.ItemState {
+	handled := make(map[*queue.Item]queue.ItemState)
+
+	var keys []string
+	batches := make(map[string][]*queue.Item)
+	for _, item := range n.Queue.GetItems() {
+		switch item.GetState() {
+		case queue.ItemStateNew, queue.ItemStateHold, queue.ItemStateFailed:
+		default:
+			continue
+		}
+
+		// The resources of a stream are removed in batches by the stream itself
+		if _, ok := item.Resource.(*StreamResource); ok {
+			continue
+		}
+
+		if _, ok := registry.GetLister(item.Type).(BatchRemover); !ok {
+			continue
+		}
+
+		key := item.Owner + "\x00" + item.Type
+		if _, ok := batches[key]; !ok {
+			keys = append(keys, key)
+		}
+		batches[key] = append(batches[key], item)
+	}
+
+	for _, key := range keys {
+		var batch []*queue.Item
+		for _, item := range batches[key] {
+			handled[item] = item.GetState()
+			if n.beforeRemoveItem(ctx, item) {
+				batch = append(batch, item)
+			}
+		}
+
+		if len(batch) == 0 {
+			continue
+		}
+
+		// The items of a batch share the options of the lister of their owner and resource type
+		errs := removeBatch(ctx, registry.GetLister(batch[0].Type), batch[0].Opts, batch)
+		for i, item := range batch {
+			n.setRemoveResult(ctx, item, errs[i])
+		}
+	}
+
+	return handled
+}
+
+// beforeRemoveItem enforces the item filters once more and runs the before remove handlers, once per attempt to remove
+// the item. It returns false when the item must not be removed, the state of the item is set accordingly.
+func (n *Nuke) beforeRemoveItem(ctx context.Context, item *queue.Item) bool {
+	if refresher, ok := item.Resource.(TagRefresher); ok && n.refreshTags {
+		if err := refresher.RefreshTags(ctx); err != nil {
+			item.State = queue.ItemStateFailed
+			item.Reason = fmt.Sprintf("unable to refresh tags: %s", err)
+			delete(n.beforeRemoved, item)
+			return false
+		}
+	}
+
+	// The item filters are enforced a second time, nothing that one of them protects is ever removed
+	if n.FilterItem(item) {
+		n.log.
+			WithField("type", item.Type).
+			WithField("owner", item.Owner).
+			Warnf("refusing to remove resource: %s", item.Reason)
+		delete(n.beforeRemoved, item)
+		return false
+	}
+
+	// A resource that is on hold is removed again on every run of the queue, the handlers run once per attempt
+	if n.beforeRemoved[item] {
+		return true
+	}
+
+	if err := n.beforeRemove(ctx, item); err != nil {
+		item.State = queue.ItemStateFailed
+		item.Reason = err.Error()
+		return false
+	}
+
+	if n.beforeRemoved == nil {
+		n.beforeRemoved = make(map[*queue.Item]bool)
+	}
+	n.beforeRemoved[item] = true
+
+	return true
+}
+
+// setRemoveResult sets the state of an item after the removal of its resource, pending when it was removed. The after
+// remove handlers are run for a resource that was removed.
+func (n *Nuke) setRemoveResult(ctx context.Context, item *queue.Item, err error) {
+	if err != nil {
+		var resErr liberrors.ErrHoldResource
+		if errors.As(err, &resErr) {
+			item.State = queue.ItemStateHold
+			item.Reason = resErr.Error()
+			return
+		}
+
+		// The attempt ends when it failed or the resource was removed, a retry runs the handlers again
+		delete(n.beforeRemoved, item)
+
+		item.State = queue.ItemStateFailed
+		item.Reason = err.Error()
+		return
+	}
+
+	delete(n.beforeRemoved, item)
+	invalidateListCache(item)
+
+	item.State = queue.ItemStatePending
+	item.Reason = ""
+
+	n.afterRemove(ctx, item)
+}
@@ -0,0 +1,174 @@
+package nuke
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	liberrors "github.com/ekristen/libnuke/pkg/errors"
+	"github.com/ekristen/libnuke/pkg/filter"
+	libnuke "github.com/ekristen/libnuke/pkg/nuke"
+	"github.com/ekristen/libnuke/pkg/queue"
+	"github.com/ekristen/libnuke/pkg/registry"
+	"github.com/ekristen/libnuke/pkg/resource"
+	libsettings "github.com/ekristen/libnuke/pkg/settings"
+)
+
+const batchTestResourceType = "NukeTestBatch"
+
+// batchTestBatches records the names of the resources of every batch that is removed.
+var batchTestBatches = struct {
+	sync.Mutex
+	names [][]string
+}{}
+
+func init() {
+	registry.Register(&registry.Registration{
+		Name:     batchTestResourceType,
+		Scope:    Account,
+		Resource: &testResource{},
+		Lister:   &batchTestLister{},
+	})
+}
+
+// batchTestLister removes the test resources in batches, the resource named stuck is never removed.
+type batchTestLister struct {
+	testResourceLister
+}
+
+func (l *batchTestLister) RemoveBatch(ctx context.Context, _ interface{}, resources []resource.Resource) []error {
+	names := make([]string, len(resources))
+	errs := make([]error, len(resources))
+	for i, r := range resources {
+		names[i] = r.(*testResource).Name
+		if names[i] == "stuck" {
+			errs[i] = errors.New("stuck")
+			continue
+		}
+
+		errs[i] = r.Remove(ctx)
+	}
+
+	batchTestBatches.Lock()
+	batchTestBatches.names = append(batchTestBatches.names, names)
+	batchTestBatches.Unlock()
+
+	return errs
+}
+
+// batchTestShortRemover returns fewer results than resources.
+type batchTestShortRemover struct{}
+
+func (r *batchTestShortRemover) RemoveBatch(_ context.Context, _ interface{}, _ []resource.Resource) []error {
+	return []error{nil}
+}
+
+func TestNuke_RunBatches(t *testing.T) {
+	batchTestBatches.Lock()
+	batchTestBatches.names = nil
+	batchTestBatches.Unlock()
+
+	setTestResources(map[string][]string{
+		"us-east-1": {"one", "two", "refused"},
+		"eu-west-1": {"three"},
+	})
+
+	n := newStreamTestNuke(t, batchTestResourceType, "us-east-1", "eu-west-1")
+
+	n.RegisterBeforeRemoveHandler(func(_ context.Context, item *queue.Item) error {
+		if item.Resource.(*testResource).Name == "refused" {
+			return errors.New("refused")
+		}
+		return nil
+	})
+
+	err := n.Run(context.TODO())
+	var removalErr *RemovalError
+	assert.ErrorAs(t, err, &removalErr)
+
+	assert.Equal(t, 3, n.Queue.Count(queue.ItemStateFinished))
+	assert.Equal(t, 1, n.Queue.Count(queue.ItemStateFailed))
+
+	batchTestBatches.Lock()
+	defer batchTestBatches.Unlock()
+	assert.ElementsMatch(t, [][]string{{"one", "two"}, {"three"}}, batchTestBatches.names)
+}
+
+func TestNuke_RunBatchesFailed(t *testing.T) {
+	setTestResources(map[string][]string{
+		"us-east-1": {"stuck", "one"},
+	})
+
+	n := newStreamTestNuke(t, batchTestResourceType, "us-east-1")
+
+	err := n.Run(context.TODO())
+	var removalErr *RemovalError
+	assert.ErrorAs(t, err, &removalErr)
+
+	assert.Equal(t, SummaryRow{Region: "total", Removed: 1, Failed: 1}, NewSummary(n.Queue).Total)
+
+	testResources.Lock()
+	defer testResources.Unlock()
+	assert.Equal(t, []string{"stuck"}, testResources.names["us-east-1"])
+}
+
+func TestRemoveBatch(t *testing.T) {
+	items := []*queue.Item{
+		{Resource: &testResource{Name: "one"}},
+		{Resource: &testResource{Name: "two"}},
+	}
+
+	errs := removeBatch(context.TODO(), &batchTestShortRemover{}, nil, items)
+	assert.Len(t, errs, 2)
+	for _, err := range errs {
+		assert.EqualError(t, err, "batch remover returned 1 results for 2 resources")
+	}
+
+	assert.Nil(t, removeBatch(context.TODO(), &batchTestShortRemover{}, nil, nil))
+}
+
+// holdTestResource is on hold for a number of removals before it is removed or fails with err.
+type holdTestResource struct {
+	testResource
+
+	holds int
+	err   error
+}
+
+func (r *holdTestResource) Remove(_ context.Context) error {
+	if r.holds > 0 {
+		r.holds--
+		return liberrors.ErrHoldResource("waiting")
+	}
+
+	return r.err
+}
+
+func TestNuke_HandleRemoveBeforeHandlersOnce(t *testing.T) {
+	n := New(&libnuke.Parameters{}, filter.Filters{}, &libsettings.Settings{})
+
+	var calls int
+	n.RegisterBeforeRemoveHandler(func(_ context.Context, _ *queue.Item) error {
+		calls++
+		return nil
+	})
+
+	r := &holdTestResource{holds: 2, err: errors.New("failed")}
+	item := &queue.Item{Resource: r, State: queue.ItemStateNew, Type: testResourceType, Owner: "us-east-1"}
+
+	// The handlers run once while the resource is on hold
+	for _, want := range []queue.ItemState{queue.ItemStateHold, queue.ItemStateHold, queue.ItemStateFailed} {
+		n.HandleRemove(context.TODO(), item)
+		assert.Equal(t, want, item.State)
+	}
+	assert.Equal(t, 1, calls)
+
+	// A retry of the failed removal is another attempt
+	r.err = nil
+	n.HandleRemove(context.TODO(), item)
+	assert.Equal(t, queue.ItemStatePending, item.State)
+	assert.Equal(t, 2, calls)
+}
@@ -0,0 +1,116 @@
+package nuke
+
+import (
+	"fmt"
+	"sync"
+)
+
+// ListCacheKey identifies a cached list, the resource type that is listed and the parameters of the list.
+type ListCacheKey struct {
+	ResourceType string
+	Params       string
+}
+
+// ListCache caches the lists that the listers of dependent resource types share, for example the users that the
+// listers of the access keys, policies and MFA devices of users walk. A ListCache is shared by the listers of a region
+// for the whole run, the lists of a resource type are invalidated when one of its resources is removed.
+type ListCache struct {
+	lock    sync.Mutex
+	entries map[string]map[string]*listCacheEntry
+}
+
+type listCacheEntry struct {
+	done  chan struct{}
+	value interface{}
+	err   error
+}
+
+// NewListCache returns an empty ListCache.
+func NewListCache() *ListCache {
+	return &ListCache{
+		entries: make(map[string]map[string]*listCacheEntry),
+	}
+}
+
+// Invalidate drops the cached lists of the resource types.
+func (c *ListCache) Invalidate(resourceTypes ...string) {
+	if c == nil {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, resourceType := range resourceTypes {
+		delete(c.entries, resourceType)
+	}
+}
+
+// get returns the cached list of the key or calls list. Concurrent calls for the same key wait for the first one,
+// errors are returned to all of them but not cached. A panic of list is passed on to the caller.
+func (c *ListCache) get(key ListCacheKey, list func() (interface{}, error)) (interface{}, error) {
+	c.lock.Lock()
+	params, ok := c.entries[key.ResourceType]
+	if !ok {
+		params = make(map[string]*listCacheEntry)
+		c.entries[key.ResourceType] = params
+	}
+
+	entry, ok := params[key.Params]
+	if ok {
+		c.lock.Unlock()
+		<-entry.done
+		return entry.value, entry.err
+	}
+
+	entry = &listCacheEntry{done: make(chan struct{})}
+	params[key.Params] = entry
+	c.lock.Unlock()
+
+	// The waiters are released even when list panics, the panic is returned to them as an error and is not cached
+	panicked := true
+	defer func() {
+		if panicked {
+			entry.value, entry.err = nil, fmt.Errorf("list of %s panicked", key.ResourceType)
+		}
+
+		close(entry.done)
+
+		if entry.err != nil {
+			c.lock.Lock()
+			if c.entries[key.ResourceType][key.Params] == entry {
+				delete(c.entries[key.ResourceType], key.Params)
+			}
+			c.lock.Unlock()
+		}
+	}()
+
+	entry.value, entry.err = list()
+	panicked = false
+
+	return entry.value, entry.err
+}
+
+// Cached returns the list of the key from the cache of the options, or calls list and caches its result. Without a
+// cache, list is always called.
+func Cached[T any](opts *ListerOpts, key ListCacheKey, list func() (T, error)) (T, error) {
+	if opts == nil || opts.Cache == nil {
+		return list()
+	}
+
+	value, err := opts.Cache.get(key, func() (interface{}, error) {
+		return list()
+	})
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	typed, ok := value.(T)
+	if !ok {
+		var zero T
+		return zero, fmt.Errorf("cached list of %s has type %T, not %T", key.ResourceType, value, zero)
+	}
+
+	return typed, nil
+}
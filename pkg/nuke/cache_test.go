@@ -0,0 +1,185 @@
+package nuke
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+)
+
+func TestCached(t *testing.T) {
+	opts := &ListerOpts{Cache: NewListCache()}
+
+	calls := 0
+	list := func() ([]string, error) {
+		calls++
+		return []string{"user-a", "user-b"}, nil
+	}
+
+	for i := 0; i < 2; i++ {
+		users, err := Cached(opts, ListCacheKey{ResourceType: "IAMUser"}, list)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"user-a", "user-b"}, users)
+	}
+	assert.Equal(t, 1, calls)
+
+	// The parameters are part of the key
+	_, err := Cached(opts, ListCacheKey{ResourceType: "IAMUser", Params: "path=/admin/"}, list)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls)
+
+	// Invalidating a resource type drops the lists of all its parameters
+	opts.Cache.Invalidate("IAMUser")
+	_, err = Cached(opts, ListCacheKey{ResourceType: "IAMUser"}, list)
+	assert.NoError(t, err)
+	_, err = Cached(opts, ListCacheKey{ResourceType: "IAMUser", Params: "path=/admin/"}, list)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, calls)
+
+	// A list of another type under the same key is an error
+	_, err = Cached(opts, ListCacheKey{ResourceType: "IAMUser"}, func() (int, error) { return 0, nil })
+	assert.EqualError(t, err, "cached list of IAMUser has type []string, not int")
+}
+
+func TestCached_Errors(t *testing.T) {
+	opts := &ListerOpts{Cache: NewListCache()}
+
+	calls := 0
+	list := func() ([]string, error) {
+		calls++
+		if calls == 1 {
+			return nil, errors.New("throttled")
+		}
+		return []string{"bucket"}, nil
+	}
+
+	_, err := Cached(opts, ListCacheKey{ResourceType: "S3Bucket"}, list)
+	assert.EqualError(t, err, "throttled")
+
+	buckets, err := Cached(opts, ListCacheKey{ResourceType: "S3Bucket"}, list)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"bucket"}, buckets)
+	assert.Equal(t, 2, calls)
+}
+
+func TestCached_WithoutCache(t *testing.T) {
+	calls := 0
+	list := func() (int, error) {
+		calls++
+		return calls, nil
+	}
+
+	for _, opts := range []*ListerOpts{nil, {}} {
+		_, err := Cached(opts, ListCacheKey{ResourceType: "IAMUser"}, list)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 2, calls)
+
+	var cache *ListCache
+	cache.Invalidate("IAMUser")
+}
+
+func TestCached_Concurrent(t *testing.T) {
+	opts := &ListerOpts{Cache: NewListCache()}
+
+	var calls int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tables, err := Cached(opts, ListCacheKey{ResourceType: "DynamoDBTable"}, func() ([]string, error) {
+				atomic.AddInt32(&calls, 1)
+				time.Sleep(10 * time.Millisecond)
+				return []string{"table"}, nil
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"table"}, tables)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestCached_Panic(t *testing.T) {
+	opts := &ListerOpts{Cache: NewListCache()}
+	key := ListCacheKey{ResourceType: "IAMUser"}
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	go func() {
+		defer func() {
+			_ = recover()
+		}()
+
+		_, _ = Cached(opts, key, func() ([]string, error) {
+			close(started)
+			<-release
+			panic("lister panic")
+		})
+	}()
+
+	<-started
+
+	waited := make(chan error, 1)
+	go func() {
+		_, err := Cached(opts, key, func() ([]string, error) {
+			return []string{"unexpected"}, nil
+		})
+		waited <- err
+	}()
+
+	// The waiter is released with an error instead of blocking forever
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+
+	select {
+	case err := <-waited:
+		assert.EqualError(t, err, "list of IAMUser panicked")
+	case <-time.After(time.Second):
+		t.Fatal("waiter is blocked")
+	}
+
+	// The panic is not cached
+	users, err := Cached(opts, key, func() ([]string, error) {
+		return []string{"user"}, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"user"}, users)
+}
+
+func TestNuke_RunInvalidatesListCache(t *testing.T) {
+	setTestResources(map[string][]string{
+		"eu-west-1": {"unprotected"},
+	})
+
+	n := newTestNuke(t, "eu-west-1")
+	cache := NewListCache()
+	n.Scanners[Account][0].Options.(*ListerOpts).Cache = cache
+
+	calls := 0
+	list := func() ([]string, error) {
+		calls++
+		return []string{"unprotected"}, nil
+	}
+
+	opts := &ListerOpts{Cache: cache}
+	_, err := Cached(opts, ListCacheKey{ResourceType: testResourceType}, list)
+	assert.NoError(t, err)
+
+	assert.NoError(t, n.Run(context.TODO()))
+	assert.Equal(t, 1, n.Queue.Count(queue.ItemStateFinished))
+
+	// The removal dropped the cached list of the resource type
+	_, err = Cached(opts, ListCacheKey{ResourceType: testResourceType}, list)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls)
+}
@@ -0,0 +1,41 @@
+package nuke
+
+// ConfigError is returned by Run when the parameters or the filters of the run are invalid.
+type ConfigError struct {
+	Err error
+}
+
+func (e *ConfigError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+// GuardError is returned by Run when a safety guard refused the run, such as the validate handlers, the queue validate
+// handlers or the approval handlers.
+type GuardError struct {
+	Err error
+}
+
+func (e *GuardError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *GuardError) Unwrap() error {
+	return e.Err
+}
+
+// RemovalError is returned by Run when resources could not be removed, after they failed or waited too many times.
+type RemovalError struct {
+	Err error
+}
+
+func (e *RemovalError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RemovalError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,43 @@
+package nuke
+
+import (
+	"context"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+
+	"github.com/ekristen/aws-nuke/v3/pkg/config"
+)
+
+// RunEvent is a lifecycle event of a run. The events are the events of the notifications configuration, they are
+// defined there so the configuration can be validated without the run.
+type RunEvent string
+
+const (
+	// RunEventStart is emitted when the run starts, before the scan.
+	RunEventStart RunEvent = config.NotificationEventRunStart
+
+	// RunEventScanComplete is emitted when the scan has completed and the queue has been filtered.
+	RunEventScanComplete RunEvent = config.NotificationEventScanComplete
+
+	// RunEventRemovalComplete is emitted when all resources in the queue have been removed.
+	RunEventRemovalComplete RunEvent = config.NotificationEventRemovalComplete
+
+	// RunEventRemovalFailed is emitted when the removal ended with resources that could not be removed.
+	RunEventRemovalFailed RunEvent = config.NotificationEventRemovalFailed
+)
+
+// RunEventHandler is called for every lifecycle event of a run with the current queue. Handlers are called
+// synchronously and must not modify the queue.
+type RunEventHandler func(ctx context.Context, event RunEvent, q *queue.Queue)
+
+// RegisterRunEventHandler registers a handler that is called for the lifecycle events of a run. It is optional.
+func (n *Nuke) RegisterRunEventHandler(handler RunEventHandler) {
+	n.RunEventHandlers = append(n.RunEventHandlers, handler)
+}
+
+// emit calls the run event handlers for the event.
+func (n *Nuke) emit(ctx context.Context, event RunEvent) {
+	for _, handler := range n.RunEventHandlers {
+		handler(ctx, event, n.Queue)
+	}
+}
@@ -0,0 +1,35 @@
+package nuke
+
+import (
+	"strings"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+	"github.com/ekristen/libnuke/pkg/resource"
+)
+
+// ItemName returns how the resource of the item is printed, it is empty when the resource is not a LegacyStringer.
+func ItemName(item *queue.Item) string {
+	if rString, ok := item.Resource.(resource.LegacyStringer); ok {
+		return rString.String()
+	}
+
+	return ""
+}
+
+// ItemProperties returns the properties of the resource of the item, it is nil when the resource has no properties.
+// Internal keys such as the tag prefix are not properties of the resource and are left out.
+func ItemProperties(item *queue.Item) map[string]string {
+	rProp, ok := item.Resource.(resource.PropertyGetter)
+	if !ok {
+		return nil
+	}
+
+	properties := make(map[string]string)
+	for key, value := range rProp.Properties() {
+		if !strings.HasPrefix(key, "_") {
+			properties[key] = value
+		}
+	}
+
+	return properties
+}
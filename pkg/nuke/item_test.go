@@ -0,0 +1,20 @@
+package nuke
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+)
+
+func TestItemNameAndProperties(t *testing.T) {
+	item := &queue.Item{Resource: &testResource{Name: "tagged", Owner: "us-east-1"}}
+
+	assert.Equal(t, "tagged", ItemName(item))
+	assert.Equal(t, map[string]string{
+		"Name":             "tagged",
+		"Owner":            "us-east-1",
+		"tag:nuke:protect": "true",
+	}, ItemProperties(item))
+}
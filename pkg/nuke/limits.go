@@ -0,0 +1,71 @@
+package nuke
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+
+	"github.com/ekristen/aws-nuke/v3/pkg/config"
+)
+
+// RemovalCounts returns the number of resources that would be removed by resource type.
+func RemovalCounts(q *queue.Queue) map[string]int {
+	counts := make(map[string]int)
+	for _, item := range q.GetItems() {
+		switch item.GetState() {
+		case queue.ItemStateNew, queue.ItemStateNewDependency:
+			if stream, ok := item.Resource.(*StreamResource); ok {
+				counts[item.Type] += stream.Removable
+				continue
+			}
+
+			counts[item.Type]++
+		}
+	}
+
+	return counts
+}
+
+// RemovalLimitsHandler returns a queue validate handler that refuses the removal when any of the removal limits is
+// exceeded. The number of removals by resource type is logged when it does.
+func RemovalLimitsHandler(limits *config.RemovalLimits, logger *logrus.Entry) QueueValidateHandler {
+	return func(q *queue.Queue) error {
+		counts := RemovalCounts(q)
+		total, nukeable, _ := ScanCounts(q)
+
+		exceeded := limits.Exceeded(counts, total)
+		if len(exceeded) == 0 {
+			return nil
+		}
+
+		resourceTypes := make([]string, 0, len(counts))
+		for resourceType := range counts {
+			resourceTypes = append(resourceTypes, resourceType)
+		}
+		sort.Slice(resourceTypes, func(i, j int) bool {
+			if counts[resourceTypes[i]] == counts[resourceTypes[j]] {
+				return resourceTypes[i] < resourceTypes[j]
+			}
+			return counts[resourceTypes[i]] > counts[resourceTypes[j]]
+		})
+
+		printLog := logger.WithField("_handler", "println")
+		printLog.Errorf("Removal limits exceeded, %d of %d discovered resources would be removed:",
+			nukeable, total)
+
+		for _, resourceType := range resourceTypes {
+			if limit, ok := limits.ResourceTypeLimit(resourceType); ok {
+				printLog.Errorf("> %s: %d (limit: %d)", resourceType, counts[resourceType], limit)
+				continue
+			}
+
+			printLog.Errorf("> %s: %d", resourceType, counts[resourceType])
+		}
+
+		return fmt.Errorf("removal limits exceeded: %s", strings.Join(exceeded, "; "))
+	}
+}
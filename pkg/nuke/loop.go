@@ -0,0 +1,330 @@
+package nuke
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	liberrors "github.com/ekristen/libnuke/pkg/errors"
+	libnuke "github.com/ekristen/libnuke/pkg/nuke"
+	"github.com/ekristen/libnuke/pkg/queue"
+	"github.com/ekristen/libnuke/pkg/registry"
+	"github.com/ekristen/libnuke/pkg/resource"
+)
+
+// The run loop below mirrors the one of libnuke v1.3.0 (run, handleFailure, handleWaiting, HandleQueue, HandleRemove,
+// HandleWaitDependency and HandleWait). The methods of the embedded libnuke Nuke call each other directly, so the
+// loop cannot be reused with only HandleRemove and HandleWait replaced. Everything aws-nuke adds to the loop goes
+// through the registered handlers, batches and streams, the loop itself is kept as close to libnuke as possible.
+// It has to be compared with libnuke whenever libnuke is upgraded, TestLibnukeVersion fails as a reminder.
+
+// mirroredLibnukeVersion is the version of libnuke the run loop mirrors.
+const mirroredLibnukeVersion = "v1.3.0"
+
+// run handles the processing and loop of the queue of items
+func (n *Nuke) run(ctx context.Context) error {
+	if n.runSleep == 0 {
+		n.runSleep = 5 * time.Second
+	}
+
+	for {
+		// HandleQueue is used to handle the queue of resources. It will iterate over the queue and trigger the
+		// appropriate handlers based on the state of the resource.
+		n.HandleQueue(ctx)
+
+		// handleFailure will check to see if we are in a final failure state and should error out and exit
+		if err := n.handleFailure(); err != nil {
+			return err
+		}
+
+		// handleWaiting will check to see if we have waited to long for resources to retry and error and exit
+		if err := n.handleWaiting(); err != nil {
+			return err
+		}
+
+		// unfinishedCount is used to determine if there are any resources that are still in a state
+		// that is not the finished state
+		unfinishedCount := n.Queue.Count(queue.ItemStateNew, queue.ItemStateNewDependency,
+			queue.ItemStatePending, queue.ItemStatePendingDependency, queue.ItemStateFailed,
+			queue.ItemStateWaiting, queue.ItemStateHold,
+		)
+
+		// If there are no resources in the queue that are in a state that is not finished, then we are done
+		if unfinishedCount == 0 {
+			break
+		}
+
+		time.Sleep(n.runSleep)
+	}
+
+	return nil
+}
+
+// handleFailure is used to handle the failure state of resources. It will determine if there have been too many
+// failures and exit accordingly, writing to screen the failure state of each resource
+func (n *Nuke) handleFailure() error {
+	printLog := n.log.WithField("_handler", "println")
+
+	// processingCount is used to determine if there are any resources that are not in the failed state
+	processingCount := n.Queue.Count(queue.ItemStatePending, queue.ItemStatePendingDependency, queue.ItemStateHold,
+		queue.ItemStateWaiting, queue.ItemStateNew, queue.ItemStateNewDependency)
+
+	// failedCount is used to determine if there are any resources that are in the failed state
+	failedCount := n.Queue.Count(queue.ItemStateFailed)
+
+	// if there are no resources being processed and there are resources in the failed state, then we enter this
+	// loop to determine how many times we've tried the failed resources
+	if processingCount == 0 && failedCount > 0 {
+		// if failCount is greater than 2, then we are done, print status and return failed error
+		if n.failedCount >= 2 {
+			printLog.Errorf("There are resources in failed state, but none are ready for deletion, anymore.")
+
+			for _, item := range n.Queue.GetItems() {
+				if item.GetState() != queue.ItemStateFailed {
+					continue
+				}
+
+				item.Print()
+				printLog.Error(item.GetReason())
+			}
+
+			return fmt.Errorf("failed")
+		}
+
+		n.failedCount++
+	} else {
+		n.failedCount = 0
+	}
+
+	return nil
+}
+
+// handleWaiting is used to handle the waiting state of resources. It will determine if there have been too many
+// wait retries and exit accordingly.
+func (n *Nuke) handleWaiting() error {
+	// if MaxWaitRetries is set to 0, then we do not need to do anything, we will retry indefinitely
+	if n.Parameters.MaxWaitRetries == 0 {
+		return nil
+	}
+
+	// pendingCount is used to determine if there are any resources that are still in a pending or hold
+	pendingCount := n.Queue.Count(queue.ItemStateWaiting, queue.ItemStatePending,
+		queue.ItemStatePendingDependency, queue.ItemStateHold)
+
+	// newCount is used to determine if there are any resources that are still in a new state
+	newCount := n.Queue.Count(queue.ItemStateNew, queue.ItemStateNewDependency)
+
+	// If MaxWaitRetries is set, then we need to know if all resources have been moved from new to a pending state.
+	// If there are pending, then we need to know how many times to retry before giving up, otherwise we try
+	// indefinitely.
+	if pendingCount > 0 && newCount == 0 {
+		if n.waitingCount >= n.Parameters.MaxWaitRetries {
+			return fmt.Errorf("max wait retries of %d exceeded", n.Parameters.MaxWaitRetries)
+		}
+		n.waitingCount++
+	} else {
+		n.waitingCount = 0
+	}
+
+	return nil
+}
+
+// HandleQueue is used to handle the queue of resources. It will iterate over the queue and trigger the appropriate
+// handlers based on the state of the resource.
+func (n *Nuke) HandleQueue(ctx context.Context) {
+	listCache := make(libnuke.ListCache)
+
+	// The resource types with a batch remover are removed first, one batch per owner and resource type
+	batched := n.HandleRemoveBatches(ctx)
+
+	for _, item := range n.Queue.GetItems() {
+		if state, ok := batched[item]; ok {
+			if state == queue.ItemStateFailed {
+				n.HandleWait(ctx, item, listCache)
+			}
+			item.Print()
+			continue
+		}
+
+		switch item.GetState() {
+		case queue.ItemStateNew, queue.ItemStateHold:
+			n.HandleRemove(ctx, item)
+			item.Print()
+		case queue.ItemStateNewDependency, queue.ItemStatePendingDependency:
+			n.HandleWaitDependency(ctx, item)
+			item.Print()
+		case queue.ItemStateFailed:
+			n.HandleRemove(ctx, item)
+			n.HandleWait(ctx, item, listCache)
+			item.Print()
+		case queue.ItemStatePending:
+			n.HandleWait(ctx, item, listCache)
+			item.State = queue.ItemStateWaiting
+			item.Print()
+		case queue.ItemStateWaiting:
+			n.HandleWait(ctx, item, listCache)
+			item.Print()
+		}
+	}
+
+	countWaiting := n.Queue.Count(
+		queue.ItemStateWaiting,
+		queue.ItemStatePending,
+		queue.ItemStatePendingDependency,
+		queue.ItemStateNewDependency,
+		queue.ItemStateHold,
+	)
+	countFailed := n.Queue.Count(queue.ItemStateFailed)
+	countSkipped := n.Queue.Count(queue.ItemStateFiltered)
+	countFinished := n.Queue.Count(queue.ItemStateFinished)
+
+	printLog := n.log.WithField("_handler", "println")
+	printLog.
+		WithFields(logrus.Fields{
+			"waiting":  countWaiting,
+			"failed":   countFailed,
+			"skipped":  countSkipped,
+			"finished": countFinished,
+		}).
+		Infof("Removal requested: %d waiting, %d failed, %d skipped, %d finished\n\n",
+			countWaiting, countFailed, countSkipped, countFinished)
+}
+
+// HandleRemove is used to handle the removal of a resource. It will remove the resource and set the state of the
+// resource to pending if it was successful or failed if it was not.
+func (n *Nuke) HandleRemove(ctx context.Context, item *queue.Item) {
+	// The resources of a stream are filtered and handed to the before remove handlers one by one as it is removed
+	if _, ok := item.Resource.(*StreamResource); !ok && !n.beforeRemoveItem(ctx, item) {
+		return
+	}
+
+	n.setRemoveResult(ctx, item, item.Resource.Remove(ctx))
+}
+
+// beforeRemove runs the before remove handlers for an item, it stops at the first error.
+func (n *Nuke) beforeRemove(ctx context.Context, item *queue.Item) error {
+	for _, handler := range n.BeforeRemoveHandlers {
+		if err := handler(ctx, item); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// afterRemove runs the after remove handlers for an item, their errors are only logged.
+func (n *Nuke) afterRemove(ctx context.Context, item *queue.Item) {
+	for _, handler := range n.AfterRemoveHandlers {
+		if err := handler(ctx, item); err != nil {
+			n.log.
+				WithError(err).
+				WithField("type", item.Type).
+				WithField("owner", item.Owner).
+				Warn("after remove handler failed")
+		}
+	}
+}
+
+// invalidateListCache drops the cached lists of the resource type of the item, so the listers that depend on it see
+// the removal.
+func invalidateListCache(item *queue.Item) {
+	if opts, ok := item.Opts.(*ListerOpts); ok {
+		opts.Cache.Invalidate(item.Type)
+	}
+}
+
+// HandleWaitDependency is used to handle the waiting of a resource. It will check if the resource has any dependencies
+// and if it does, it will check if the dependencies have been removed. If they have, it will trigger the remove handler.
+func (n *Nuke) HandleWaitDependency(ctx context.Context, item *queue.Item) {
+	reg := registry.GetRegistration(item.Type)
+	depCount := 0
+	for _, dep := range reg.DependsOn {
+		cnt := n.Queue.CountByType(dep,
+			queue.ItemStateNew, queue.ItemStateNewDependency,
+			queue.ItemStatePending, queue.ItemStatePendingDependency,
+			queue.ItemStateWaiting, queue.ItemStateHold)
+		depCount += cnt
+	}
+
+	if depCount == 0 {
+		n.HandleRemove(ctx, item)
+		return
+	}
+
+	item.State = queue.ItemStatePendingDependency
+	item.Reason = fmt.Sprintf("left: %d", depCount)
+}
+
+// HandleWait is used to handle the waiting of a resource. It will check if the resource has been removed. If it has,
+// it will set the state of the resource to finished. If it has not, it will set the state of the resource to waiting.
+func (n *Nuke) HandleWait(ctx context.Context, item *queue.Item, cache libnuke.ListCache) {
+	var err error
+
+	waitHook, hookOk := item.Resource.(resource.HandleWaitHook)
+	if hookOk {
+		if hookErr := waitHook.HandleWait(ctx); hookErr != nil {
+			var waitErr liberrors.ErrWaitResource
+			if errors.As(hookErr, &waitErr) {
+				item.State = queue.ItemStateWaiting
+				return
+			}
+
+			item.State = queue.ItemStateFailed
+			item.Reason = hookErr.Error()
+			return
+		}
+	}
+
+	// A stream was removed when its removal succeeded, its resources are not listed again to confirm it
+	if _, ok := item.Resource.(*StreamResource); ok {
+		if item.State != queue.ItemStateFailed {
+			item.State = queue.ItemStateFinished
+			item.Reason = ""
+		}
+		return
+	}
+
+	ownerID := item.Owner
+	_, ok := cache[ownerID]
+	if !ok {
+		cache[ownerID] = make(map[string][]resource.Resource)
+	}
+
+	left, ok := cache[ownerID][item.Type]
+	if !ok {
+		left, err = item.List(ctx, item.Opts)
+		if err != nil {
+			item.State = queue.ItemStateFailed
+			item.Reason = err.Error()
+			return
+		}
+		cache[ownerID][item.Type] = left
+	}
+
+	for _, r := range left {
+		if !item.Equals(r) {
+			continue
+		}
+
+		if rSet, okSet := r.(resource.SettingsGetter); okSet {
+			rSet.Settings(n.ResolveSettings(item))
+		}
+
+		checker, filterOk := r.(resource.Filter)
+		if filterOk {
+			if filterErr := checker.Filter(); filterErr != nil {
+				break
+			}
+		}
+
+		return
+	}
+
+	invalidateListCache(item)
+
+	item.State = queue.ItemStateFinished
+	item.Reason = ""
+}
@@ -0,0 +1,26 @@
+package nuke
+
+import (
+	"runtime/debug"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLibnukeVersion fails when libnuke is upgraded, the run loop has to be compared with the one of the new version
+// and mirroredLibnukeVersion updated.
+func TestLibnukeVersion(t *testing.T) {
+	info, ok := debug.ReadBuildInfo()
+	require.True(t, ok)
+
+	for _, dep := range info.Deps {
+		if dep.Path == "github.com/ekristen/libnuke" {
+			assert.Equal(t, mirroredLibnukeVersion, dep.Version,
+				"libnuke was upgraded, compare the run loop in loop.go with the one of the new version")
+			return
+		}
+	}
+
+	t.Fatal("libnuke is not a dependency")
+}
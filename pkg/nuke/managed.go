@@ -0,0 +1,57 @@
+package nuke
+
+import (
+	"errors"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+	"github.com/ekristen/libnuke/pkg/types"
+)
+
+var (
+	// ErrAWSManaged is the reason AWS-managed resources are filtered.
+	ErrAWSManaged = errors.New("AWS-managed resource, set include-aws-managed to remove it")
+
+	// ErrDefault is the reason default resources are filtered.
+	ErrDefault = errors.New("default resource, set include-defaults to remove it")
+)
+
+// ManagedResource is implemented by resources that can be managed by AWS instead of the account, such as service-linked
+// roles or AWS managed keys.
+type ManagedResource interface {
+	IsManaged() bool
+}
+
+// DefaultResource is implemented by resources that can be a default that AWS creates with the account or with another
+// resource, such as the default security group of a VPC.
+type DefaultResource interface {
+	IsDefault() bool
+}
+
+// SetManagedProperties sets the Managed and Default properties of a resource that implements ManagedResource or
+// DefaultResource, so they can be used in filters like any other property.
+func SetManagedProperties(properties types.Properties, r interface{}) types.Properties {
+	if managed, ok := r.(ManagedResource); ok {
+		properties.Set("Managed", managed.IsManaged())
+	}
+
+	if def, ok := r.(DefaultResource); ok {
+		properties.Set("Default", def.IsDefault())
+	}
+
+	return properties
+}
+
+// ManagedFilter returns an item filter that filters AWS-managed and default resources, unless they are included.
+func ManagedFilter(includeManaged, includeDefaults bool) ItemFilter {
+	return func(item *queue.Item) error {
+		if managed, ok := item.Resource.(ManagedResource); ok && !includeManaged && managed.IsManaged() {
+			return ErrAWSManaged
+		}
+
+		if def, ok := item.Resource.(DefaultResource); ok && !includeDefaults && def.IsDefault() {
+			return ErrDefault
+		}
+
+		return nil
+	}
+}
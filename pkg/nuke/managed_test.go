@@ -0,0 +1,82 @@
+package nuke
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+	"github.com/ekristen/libnuke/pkg/types"
+)
+
+type testManagedResource struct {
+	managed   bool
+	defaulted bool
+}
+
+func (r *testManagedResource) Remove(_ context.Context) error {
+	return nil
+}
+
+func (r *testManagedResource) IsManaged() bool {
+	return r.managed
+}
+
+func (r *testManagedResource) IsDefault() bool {
+	return r.defaulted
+}
+
+func TestManagedFilter(t *testing.T) {
+	managed := &queue.Item{Resource: &testManagedResource{managed: true}}
+	defaulted := &queue.Item{Resource: &testManagedResource{defaulted: true}}
+	plain := &queue.Item{Resource: &testManagedResource{}}
+	other := &queue.Item{Resource: &testAgeResource{}}
+
+	cases := []struct {
+		name            string
+		includeManaged  bool
+		includeDefaults bool
+		want            map[*queue.Item]error
+	}{
+		{
+			name: "exclude-all",
+			want: map[*queue.Item]error{managed: ErrAWSManaged, defaulted: ErrDefault},
+		},
+		{
+			name:           "include-managed",
+			includeManaged: true,
+			want:           map[*queue.Item]error{defaulted: ErrDefault},
+		},
+		{
+			name:            "include-defaults",
+			includeDefaults: true,
+			want:            map[*queue.Item]error{managed: ErrAWSManaged},
+		},
+		{
+			name:            "include-all",
+			includeManaged:  true,
+			includeDefaults: true,
+			want:            map[*queue.Item]error{},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			filter := ManagedFilter(tc.includeManaged, tc.includeDefaults)
+			for _, item := range []*queue.Item{managed, defaulted, plain, other} {
+				assert.Equal(t, tc.want[item], filter(item))
+			}
+		})
+	}
+}
+
+func TestSetManagedProperties(t *testing.T) {
+	properties := SetManagedProperties(types.NewProperties(), &testManagedResource{managed: true})
+	assert.Equal(t, "true", properties.Get("Managed"))
+	assert.Equal(t, "false", properties.Get("Default"))
+
+	properties = SetManagedProperties(types.NewProperties(), &testAgeResource{})
+	assert.Empty(t, properties.Get("Managed"))
+	assert.Empty(t, properties.Get("Default"))
+}
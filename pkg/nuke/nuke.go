@@ -0,0 +1,416 @@
+package nuke
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ekristen/libnuke/pkg/filter"
+	libnuke "github.com/ekristen/libnuke/pkg/nuke"
+	"github.com/ekristen/libnuke/pkg/queue"
+	"github.com/ekristen/libnuke/pkg/registry"
+	"github.com/ekristen/libnuke/pkg/resource"
+	libsettings "github.com/ekristen/libnuke/pkg/settings"
+)
+
+// SettingsResolver returns the settings for a single item in the queue. It allows settings to be scoped by account,
+// region or properties of the resource instead of only by resource type.
+type SettingsResolver func(item *queue.Item) *libsettings.Setting
+
+// QueueValidateHandler validates the queue after the scan and before any resource is removed. An error refuses the
+// removal of the resources.
+type QueueValidateHandler func(q *queue.Queue) error
+
+// ApprovalHandler approves the removal of the resources in the queue. It is only called when resources are going to be
+// removed and may block until the approval appears. An error refuses the removal of the resources.
+type ApprovalHandler func(ctx context.Context, q *queue.Queue) error
+
+// BeforeRemoveHandler is called right before an item is removed. An error fails the removal of the item, it is
+// retried like any other failed removal.
+type BeforeRemoveHandler func(ctx context.Context, item *queue.Item) error
+
+// AfterRemoveHandler is called right after the resource of an item has been removed successfully. An error is logged,
+// it does not change the state of the item.
+type AfterRemoveHandler func(ctx context.Context, item *queue.Item) error
+
+// SummaryWriter writes an additional section of the summary that is printed at the end of a run.
+type SummaryWriter func(w io.Writer) error
+
+// ItemFilter filters a single item in the queue. It returns an error when the item must not be removed, the error is
+// used as the reason the item was filtered.
+type ItemFilter func(item *queue.Item) error
+
+// Nuke wraps the libnuke Nuke to drive the scan and the removal of the queue. The run loop mirrors the one from
+// libnuke, but it's owned by aws-nuke so that behavior that has to happen for each item, such as resolving the
+// settings of an item, can be added without changes to the library.
+type Nuke struct {
+	*libnuke.Nuke
+
+	QueueValidateHandlers []QueueValidateHandler
+	ApprovalHandlers      []ApprovalHandler
+	ItemFilters           []ItemFilter
+	BeforeRemoveHandlers  []BeforeRemoveHandler
+	AfterRemoveHandlers   []AfterRemoveHandler
+	RunEventHandlers      []RunEventHandler
+	SummaryWriters        []SummaryWriter
+	ListFailures          []*ListFailure
+
+	settingsResolver SettingsResolver
+	mutateOpts       MutateOptsFunc
+
+	deferredResourceTypes map[string][]string // deferredResourceTypes are the schedules of the deferred resource types
+	refreshTags           bool                // refreshTags refreshes the tags of resources right before their removal
+
+	// beforeRemoved are the items whose before remove handlers ran for the current attempt to remove them
+	beforeRemoved map[*queue.Item]bool
+
+	scanConcurrency           int64         // scanConcurrency is the number of listers that run at the same time
+	scanConcurrencyPerScanner int64         // scanConcurrencyPerScanner is the number of listers per scanner
+	scanProgress              time.Duration // scanProgress is how often the progress of the scan is printed
+
+	log      *logrus.Entry // log is the logger that is used for the run
+	runSleep time.Duration // runSleep is how long to sleep between runs of the queue
+
+	failedCount  int // failedCount is used to track how many times we've retried all failed resources
+	waitingCount int // waitingCount is used to track how many times we've waiting for resources to move states
+}
+
+// New returns an instance of Nuke that is properly configured for initial use
+func New(params *libnuke.Parameters, filters filter.Filters, settings *libsettings.Settings) *Nuke {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	return &Nuke{
+		Nuke:     libnuke.New(params, filters, settings),
+		log:      logger.WithField("component", "nuke"),
+		runSleep: 5 * time.Second,
+	}
+}
+
+// SetLogger sets the logger that is used for the run and the underlying library.
+func (n *Nuke) SetLogger(logger *logrus.Entry) {
+	n.log = logger
+	n.Nuke.SetLogger(logger)
+}
+
+// SetDeferredResourceTypes sets the resource types that were left out of the run by their schedules, with the names of
+// the schedules. They are listed in the summary.
+func (n *Nuke) SetDeferredResourceTypes(deferred map[string][]string) {
+	n.deferredResourceTypes = deferred
+}
+
+// SetRefreshTags sets whether the tags of the resources that are a TagRefresher are read again right before they are
+// removed, so the item filters see the current tags instead of the tags at the time of the scan.
+func (n *Nuke) SetRefreshTags(refresh bool) {
+	n.refreshTags = refresh
+}
+
+// SetRunSleep sets the sleep duration between runs of the queue.
+func (n *Nuke) SetRunSleep(duration time.Duration) {
+	n.runSleep = duration
+	n.Nuke.SetRunSleep(duration)
+}
+
+// RegisterSettingsResolver registers the function that is used to resolve the settings for each item. Without a
+// resolver the settings for the resource type are used.
+func (n *Nuke) RegisterSettingsResolver(resolver SettingsResolver) {
+	n.settingsResolver = resolver
+}
+
+// ResolveSettings returns the settings for an item in the queue.
+func (n *Nuke) ResolveSettings(item *queue.Item) *libsettings.Setting {
+	if n.settingsResolver != nil {
+		return n.settingsResolver(item)
+	}
+
+	return n.Settings.Get(item.Type)
+}
+
+// RegisterValidateHandler registers a handler that validates the run before the scan, such as the account blocklist.
+// An error is a guard refusing the run, unlike the errors of the parameters and the filters, which are errors of the
+// configuration.
+func (n *Nuke) RegisterValidateHandler(handler func() error) {
+	n.Nuke.RegisterValidateHandler(func() error {
+		if err := handler(); err != nil {
+			return &GuardError{Err: err}
+		}
+
+		return nil
+	})
+}
+
+// RegisterQueueValidateHandler registers a handler that validates the queue after the scan. It is optional.
+func (n *Nuke) RegisterQueueValidateHandler(handler QueueValidateHandler) {
+	n.QueueValidateHandlers = append(n.QueueValidateHandlers, handler)
+}
+
+// ValidateQueue runs the queue validate handlers against the queue.
+func (n *Nuke) ValidateQueue() error {
+	for _, handler := range n.QueueValidateHandlers {
+		if err := handler(n.Queue); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RegisterApprovalHandler registers a handler that approves the removal of the queue. It is optional.
+func (n *Nuke) RegisterApprovalHandler(handler ApprovalHandler) {
+	n.ApprovalHandlers = append(n.ApprovalHandlers, handler)
+}
+
+// Approve runs the approval handlers against the queue.
+func (n *Nuke) Approve(ctx context.Context) error {
+	for _, handler := range n.ApprovalHandlers {
+		if err := handler(ctx, n.Queue); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RegisterBeforeRemoveHandler registers a handler that is called right before each item is removed. It is optional.
+func (n *Nuke) RegisterBeforeRemoveHandler(handler BeforeRemoveHandler) {
+	n.BeforeRemoveHandlers = append(n.BeforeRemoveHandlers, handler)
+}
+
+// RegisterAfterRemoveHandler registers a handler that is called right after each item has been removed. It is
+// optional.
+func (n *Nuke) RegisterAfterRemoveHandler(handler AfterRemoveHandler) {
+	n.AfterRemoveHandlers = append(n.AfterRemoveHandlers, handler)
+}
+
+// RegisterItemFilter registers a filter that is applied to every item after the scan and once more right before the
+// item is removed. It is optional.
+func (n *Nuke) RegisterItemFilter(itemFilter ItemFilter) {
+	n.ItemFilters = append(n.ItemFilters, itemFilter)
+}
+
+// FilterItem runs the item filters against an item and sets its state to filtered if any of them returns an error.
+// It returns true if the item was filtered.
+func (n *Nuke) FilterItem(item *queue.Item) bool {
+	for _, itemFilter := range n.ItemFilters {
+		if err := itemFilter(item); err != nil {
+			item.State = queue.ItemStateFiltered
+			item.Reason = err.Error()
+			return true
+		}
+	}
+
+	return false
+}
+
+// Run is the main entry point. It will run the validation handlers, prompt the user, scan for resources, filter them
+// and then process them.
+func (n *Nuke) Run(ctx context.Context) error {
+	n.Version()
+
+	printLog := n.log.WithField("_handler", "println")
+
+	if err := n.Validate(); err != nil {
+		var guardErr *GuardError
+		if errors.As(err, &guardErr) {
+			return err
+		}
+
+		return &ConfigError{Err: err}
+	}
+
+	if err := n.Prompt(); err != nil {
+		return err
+	}
+
+	n.emit(ctx, RunEventStart)
+
+	printLog.Info("starting scan for resources")
+
+	if err := n.Scan(ctx); err != nil {
+		return err
+	}
+
+	n.emit(ctx, RunEventScanComplete)
+
+	// The summary is printed however the run ends once the scan is complete
+	defer n.printSummary()
+
+	if n.Queue.Count(queue.ItemStateNew) == 0 {
+		printLog.Info("No resource to delete.")
+		return nil
+	}
+
+	// The queue is validated before anything is removed, this happens regardless of the prompt
+	if err := n.ValidateQueue(); err != nil {
+		if n.Parameters.NoDryRun {
+			return &GuardError{Err: err}
+		}
+
+		printLog.Warnf("%s - a run with --no-dry-run would be refused", err)
+	}
+
+	if !n.Parameters.NoDryRun {
+		printLog.Info("The above resources would be deleted with the supplied configuration. Provide --no-dry-run to actually destroy resources.")
+		return nil
+	}
+
+	// Approvals are only required to remove resources, they are requested before the final prompt
+	if err := n.Approve(ctx); err != nil {
+		return &GuardError{Err: err}
+	}
+
+	if err := n.Prompt(); err != nil {
+		return err
+	}
+
+	if err := n.run(ctx); err != nil {
+		n.emit(ctx, RunEventRemovalFailed)
+		return &RemovalError{Err: err}
+	}
+
+	n.emit(ctx, RunEventRemovalComplete)
+
+	printLog.
+		WithFields(logrus.Fields{
+			"failed":   n.Queue.Count(queue.ItemStateFailed),
+			"skipped":  n.Queue.Count(queue.ItemStateFiltered),
+			"finished": n.Queue.Count(queue.ItemStateFinished),
+		}).
+		Infof("Nuke complete: %d failed, %d skipped, %d finished.\n",
+			n.Queue.Count(queue.ItemStateFailed), n.Queue.Count(queue.ItemStateFiltered), n.Queue.Count(queue.ItemStateFinished))
+
+	return nil
+}
+
+// RegisterSummaryWriter registers a writer that adds a section to the summary printed at the end of a run. Writers
+// that write nothing add no section. It is optional.
+func (n *Nuke) RegisterSummaryWriter(writer SummaryWriter) {
+	n.SummaryWriters = append(n.SummaryWriters, writer)
+}
+
+// Summary returns the summary of the run, including the resource types that could not be listed.
+func (n *Nuke) Summary() *Summary {
+	summary := NewSummary(n.Queue)
+
+	summary.ListFailures = slices.Clone(n.ListFailures)
+	slices.SortStableFunc(summary.ListFailures, func(a, b *ListFailure) int {
+		if c := strings.Compare(a.Region, b.Region); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.ResourceType, b.ResourceType)
+	})
+
+	for _, resourceType := range slices.Sorted(maps.Keys(n.deferredResourceTypes)) {
+		summary.DeferredResourceTypes = append(summary.DeferredResourceTypes, &DeferredResourceType{
+			ResourceType: resourceType,
+			Schedules:    n.deferredResourceTypes[resourceType],
+		})
+	}
+
+	return summary
+}
+
+// printSummary prints the number of removed, failed, filtered and skipped resources per region and resource type and
+// the resource types that could not be listed, followed by the sections of the registered summary writers.
+func (n *Nuke) printSummary() {
+	printLog := n.log.WithField("_handler", "println")
+
+	for _, writer := range append([]SummaryWriter{n.Summary().Write}, n.SummaryWriters...) {
+		var buf bytes.Buffer
+		if err := writer(&buf); err != nil {
+			n.log.WithError(err).Warn("unable to write the summary")
+			continue
+		}
+
+		if buf.Len() == 0 {
+			continue
+		}
+
+		for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+			printLog.Info(line)
+		}
+	}
+}
+
+// Scan runs the registered scanners, resolves the settings for each item and filters them. It will also print the
+// current status of the resources.
+func (n *Nuke) Scan(ctx context.Context) error {
+	itemQueue := queue.New()
+
+	if err := n.scanAll(ctx, itemQueue); err != nil {
+		return err
+	}
+
+	printLog := n.log.WithField("_handler", "println")
+
+	total, nukeable, filtered := ScanCounts(itemQueue)
+
+	printLog.
+		WithFields(logrus.Fields{
+			"total":    total,
+			"nukeable": nukeable,
+			"filtered": filtered,
+		}).
+		Infof("Scan complete: %d total, %d nukeable, %d filtered.\n", total, nukeable, filtered)
+
+	n.Queue = itemQueue
+
+	return nil
+}
+
+// processItem is used to add an item returned by a lister to the queue, resolve its settings and filter it
+func (n *Nuke) processItem(item *queue.Item, itemQueue *queue.Queue) error {
+	// Experimental Feature
+	if n.Parameters.WaitOnDependencies && item.State == queue.ItemStateNew {
+		reg := registry.GetRegistration(item.Type)
+		if len(reg.DependsOn) > 0 {
+			item.State = queue.ItemStateNewDependency
+		}
+	}
+
+	itemQueue.Items = append(itemQueue.Items, item)
+
+	// The resources of a stream were filtered and printed while they were listed
+	if _, ok := item.Resource.(*StreamResource); ok {
+		return nil
+	}
+
+	if err := n.filterItem(item); err != nil {
+		return err
+	}
+
+	// If quiet and filtered, skip printing to screen
+	if n.Parameters.Quiet && item.State == queue.ItemStateFiltered {
+		return nil
+	}
+
+	item.Print()
+
+	return nil
+}
+
+// filterItem resolves the settings of an item and filters it with the filters of the configuration and the item
+// filters.
+func (n *Nuke) filterItem(item *queue.Item) error {
+	if sGetter, ok := item.Resource.(resource.SettingsGetter); ok {
+		sGetter.Settings(n.ResolveSettings(item))
+	}
+
+	if err := n.Filter(item); err != nil {
+		return err
+	}
+
+	if item.State != queue.ItemStateFiltered {
+		n.FilterItem(item)
+	}
+
+	return nil
+}
@@ -0,0 +1,491 @@
+package nuke
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gotidy/ptr"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ekristen/libnuke/pkg/filter"
+	libnuke "github.com/ekristen/libnuke/pkg/nuke"
+	"github.com/ekristen/libnuke/pkg/queue"
+	"github.com/ekristen/libnuke/pkg/registry"
+	"github.com/ekristen/libnuke/pkg/resource"
+	"github.com/ekristen/libnuke/pkg/scanner"
+	libsettings "github.com/ekristen/libnuke/pkg/settings"
+	"github.com/ekristen/libnuke/pkg/types"
+
+	"github.com/ekristen/aws-nuke/v3/pkg/config"
+)
+
+const testResourceType = "NukeTestResource"
+
+// testResources holds the resources that the test lister returns, removing a resource drops it from the list.
+var testResources = struct {
+	sync.Mutex
+	names map[string][]string
+}{}
+
+func init() {
+	registry.Register(&registry.Registration{
+		Name:     testResourceType,
+		Scope:    Account,
+		Resource: &testResource{},
+		Lister:   &testResourceLister{},
+		Settings: []string{"IncludeProtected", "Marker"},
+	})
+}
+
+func setTestResources(names map[string][]string) {
+	testResources.Lock()
+	defer testResources.Unlock()
+	testResources.names = names
+}
+
+type testResourceLister struct{}
+
+func (l *testResourceLister) List(_ context.Context, o interface{}) ([]resource.Resource, error) {
+	opts := o.(*ListerOpts)
+
+	testResources.Lock()
+	defer testResources.Unlock()
+
+	resources := make([]resource.Resource, 0)
+	for _, name := range testResources.names[*opts.AccountID] {
+		resources = append(resources, &testResource{Name: name, Owner: *opts.AccountID})
+	}
+
+	return resources, nil
+}
+
+type testResource struct {
+	Name  string
+	Owner string
+
+	settings *libsettings.Setting
+}
+
+func (r *testResource) Filter() error {
+	if r.Name == "protected" && !r.settings.GetBool("IncludeProtected") {
+		return errors.New("protected resource")
+	}
+
+	return nil
+}
+
+func (r *testResource) Remove(_ context.Context) error {
+	testResources.Lock()
+	defer testResources.Unlock()
+
+	var left []string
+	for _, name := range testResources.names[r.Owner] {
+		if name != r.Name {
+			left = append(left, name)
+		}
+	}
+	testResources.names[r.Owner] = left
+
+	return nil
+}
+
+func (r *testResource) Settings(setting *libsettings.Setting) {
+	r.settings = setting
+}
+
+func (r *testResource) Properties() types.Properties {
+	properties := types.NewPropertiesFromStruct(r)
+	if r.Name == "tagged" {
+		properties.SetTag(ptr.String("nuke:protect"), "true")
+	}
+
+	return properties
+}
+
+func (r *testResource) String() string {
+	return r.Name
+}
+
+func newTestNuke(t *testing.T, owners ...string) *Nuke {
+	n := New(&libnuke.Parameters{
+		ForceSleep: 3,
+		NoDryRun:   true,
+	}, filter.Filters{}, &libsettings.Settings{
+		testResourceType: &libsettings.Setting{"Marker": "global"},
+	})
+	n.SetRunSleep(time.Millisecond)
+
+	for _, owner := range owners {
+		s, err := scanner.New(&scanner.Config{
+			Owner:         owner,
+			ResourceTypes: []string{testResourceType},
+			Opts:          &ListerOpts{AccountID: &owner},
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, n.RegisterScanner(Account, s))
+	}
+
+	return n
+}
+
+func TestNuke_ScanWithoutResolver(t *testing.T) {
+	setTestResources(map[string][]string{
+		"eu-west-1": {"protected", "unprotected"},
+	})
+
+	n := newTestNuke(t, "eu-west-1")
+	assert.NoError(t, n.Scan(context.TODO()))
+
+	assert.Equal(t, 2, n.Queue.Total())
+	assert.Equal(t, 1, n.Queue.Count(queue.ItemStateNew))
+	assert.Equal(t, 1, n.Queue.Count(queue.ItemStateFiltered))
+
+	for _, item := range n.Queue.GetItems() {
+		assert.Equal(t, "global", item.Resource.(*testResource).settings.GetString("Marker"))
+	}
+}
+
+func TestNuke_ScanWithResolver(t *testing.T) {
+	setTestResources(map[string][]string{
+		"eu-west-1": {"protected", "unprotected"},
+		"us-east-1": {"protected", "unprotected"},
+	})
+
+	n := newTestNuke(t, "eu-west-1", "us-east-1")
+	n.RegisterSettingsResolver(func(item *queue.Item) *libsettings.Setting {
+		if item.Owner == "eu-west-1" {
+			return &libsettings.Setting{"IncludeProtected": true, "Marker": "scoped"}
+		}
+
+		return n.Settings.Get(item.Type)
+	})
+
+	assert.NoError(t, n.Scan(context.TODO()))
+
+	assert.Equal(t, 4, n.Queue.Total())
+	assert.Equal(t, 3, n.Queue.Count(queue.ItemStateNew))
+	assert.Equal(t, 1, n.Queue.Count(queue.ItemStateFiltered))
+
+	for _, item := range n.Queue.GetItems() {
+		want := "global"
+		if item.Owner == "eu-west-1" {
+			want = "scoped"
+		}
+
+		assert.Equal(t, want, item.Resource.(*testResource).settings.GetString("Marker"))
+
+		if item.State == queue.ItemStateFiltered {
+			assert.Equal(t, "us-east-1", item.Owner)
+			assert.Equal(t, "protected resource", item.Reason)
+		}
+	}
+}
+
+func TestNuke_Run(t *testing.T) {
+	setTestResources(map[string][]string{
+		"eu-west-1": {"protected", "unprotected"},
+		"us-east-1": {"protected", "unprotected"},
+	})
+
+	n := newTestNuke(t, "eu-west-1", "us-east-1")
+	n.RegisterSettingsResolver(func(item *queue.Item) *libsettings.Setting {
+		if item.Owner == "eu-west-1" {
+			return &libsettings.Setting{"IncludeProtected": true}
+		}
+
+		return n.Settings.Get(item.Type)
+	})
+
+	assert.NoError(t, n.Run(context.TODO()))
+
+	assert.Equal(t, 3, n.Queue.Count(queue.ItemStateFinished))
+	assert.Equal(t, 1, n.Queue.Count(queue.ItemStateFiltered))
+
+	testResources.Lock()
+	defer testResources.Unlock()
+	assert.Empty(t, testResources.names["eu-west-1"])
+	assert.Equal(t, []string{"protected"}, testResources.names["us-east-1"])
+}
+
+func TestNuke_RunRemovalLimits(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	cases := []struct {
+		name     string
+		noDryRun bool
+		wantErr  bool
+	}{
+		{name: "dry-run", noDryRun: false},
+		{name: "no-dry-run", noDryRun: true, wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			setTestResources(map[string][]string{
+				"us-east-1": {"one", "two", "three"},
+			})
+
+			n := newTestNuke(t, "us-east-1")
+			n.Parameters.NoDryRun = tc.noDryRun
+			limits := &config.RemovalLimits{MaxTotal: 2}
+			n.RegisterQueueValidateHandler(RemovalLimitsHandler(limits, logger.WithField("test", true)))
+
+			err := n.Run(context.TODO())
+			if tc.wantErr {
+				assert.EqualError(t, err, "removal limits exceeded: 3 resources would be removed, the maximum is 2")
+				var guardErr *GuardError
+				assert.ErrorAs(t, err, &guardErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, 3, n.Queue.Count(queue.ItemStateNew))
+
+			testResources.Lock()
+			defer testResources.Unlock()
+			assert.Len(t, testResources.names["us-east-1"], 3)
+		})
+	}
+}
+
+func TestNuke_RunValidateErrors(t *testing.T) {
+	setTestResources(map[string][]string{
+		"us-east-1": {"one"},
+	})
+
+	n := newTestNuke(t, "us-east-1")
+	n.Parameters.ForceSleep = 1
+
+	var configErr *ConfigError
+	var guardErr *GuardError
+	err := n.Run(context.TODO())
+	assert.ErrorAs(t, err, &configErr)
+	assert.False(t, errors.As(err, &guardErr))
+
+	n = newTestNuke(t, "us-east-1")
+	n.RegisterValidateHandler(func() error {
+		return errors.New("account is blocklisted")
+	})
+
+	err = n.Run(context.TODO())
+	assert.EqualError(t, err, "account is blocklisted")
+	assert.ErrorAs(t, err, &guardErr)
+	assert.False(t, errors.As(err, &configErr))
+}
+
+func TestNuke_ItemFilters(t *testing.T) {
+	setTestResources(map[string][]string{
+		"us-east-1": {"tagged", "untagged"},
+	})
+
+	cfg := &config.Config{ProtectionTags: []string{"nuke:protect=true"}}
+
+	n := newTestNuke(t, "us-east-1")
+	n.RegisterItemFilter(ProtectionTagsFilter(cfg))
+
+	assert.NoError(t, n.Run(context.TODO()))
+
+	assert.Equal(t, 1, n.Queue.Count(queue.ItemStateFinished))
+	assert.Equal(t, 1, n.Queue.Count(queue.ItemStateFiltered))
+
+	for _, item := range n.Queue.GetItems() {
+		if item.State == queue.ItemStateFiltered {
+			assert.Equal(t, "protected by tag 'nuke:protect=true'", item.Reason)
+		}
+	}
+
+	testResources.Lock()
+	defer testResources.Unlock()
+	assert.Equal(t, []string{"tagged"}, testResources.names["us-east-1"])
+}
+
+func TestNuke_ItemFiltersBeforeRemove(t *testing.T) {
+	setTestResources(map[string][]string{
+		"us-east-1": {"tagged", "untagged"},
+	})
+
+	n := newTestNuke(t, "us-east-1")
+	assert.NoError(t, n.Scan(context.TODO()))
+	assert.Equal(t, 2, n.Queue.Count(queue.ItemStateNew))
+
+	// A filter that is only registered after the scan is still enforced right before the removal
+	n.RegisterItemFilter(ProtectionTagsFilter(&config.Config{ProtectionTags: []string{"nuke:protect"}}))
+	n.HandleQueue(context.TODO())
+
+	assert.Equal(t, 1, n.Queue.Count(queue.ItemStatePending, queue.ItemStateWaiting))
+	assert.Equal(t, 1, n.Queue.Count(queue.ItemStateFiltered))
+
+	testResources.Lock()
+	defer testResources.Unlock()
+	assert.Equal(t, []string{"tagged"}, testResources.names["us-east-1"])
+}
+
+func TestNuke_RunEvents(t *testing.T) {
+	cases := []struct {
+		name     string
+		noDryRun bool
+		want     []RunEvent
+	}{
+		{name: "dry-run", want: []RunEvent{RunEventStart, RunEventScanComplete}},
+		{
+			name:     "no-dry-run",
+			noDryRun: true,
+			want:     []RunEvent{RunEventStart, RunEventScanComplete, RunEventRemovalComplete},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			setTestResources(map[string][]string{
+				"us-east-1": {"one", "two"},
+			})
+
+			n := newTestNuke(t, "us-east-1")
+			n.Parameters.NoDryRun = tc.noDryRun
+
+			var events []RunEvent
+			n.RegisterRunEventHandler(func(_ context.Context, event RunEvent, q *queue.Queue) {
+				// The queue is only populated once the scan has completed
+				if event != RunEventStart {
+					assert.Equal(t, 2, q.Total())
+				}
+				events = append(events, event)
+			})
+
+			assert.NoError(t, n.Run(context.TODO()))
+			assert.Equal(t, tc.want, events)
+		})
+	}
+}
+
+func TestNuke_RunApproval(t *testing.T) {
+	cases := []struct {
+		name      string
+		noDryRun  bool
+		approval  error
+		wantCalls int
+		wantErr   string
+		wantLeft  int
+	}{
+		{name: "dry-run", wantLeft: 2},
+		{name: "approved", noDryRun: true, wantCalls: 1},
+		{name: "refused", noDryRun: true, approval: errors.New("refused"), wantCalls: 1, wantErr: "refused", wantLeft: 2},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			setTestResources(map[string][]string{
+				"us-east-1": {"one", "two"},
+			})
+
+			n := newTestNuke(t, "us-east-1")
+			n.Parameters.NoDryRun = tc.noDryRun
+
+			calls := 0
+			n.RegisterApprovalHandler(func(_ context.Context, q *queue.Queue) error {
+				calls++
+				assert.Equal(t, 2, q.Count(queue.ItemStateNew))
+				return tc.approval
+			})
+
+			err := n.Run(context.TODO())
+			if tc.wantErr != "" {
+				assert.EqualError(t, err, tc.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tc.wantCalls, calls)
+
+			testResources.Lock()
+			defer testResources.Unlock()
+			assert.Len(t, testResources.names["us-east-1"], tc.wantLeft)
+		})
+	}
+}
+
+func TestNuke_BeforeRemoveHandlers(t *testing.T) {
+	setTestResources(map[string][]string{
+		"us-east-1": {"one", "two"},
+	})
+
+	n := newTestNuke(t, "us-east-1")
+	assert.NoError(t, n.Scan(context.TODO()))
+
+	var handled []string
+	n.RegisterBeforeRemoveHandler(func(_ context.Context, item *queue.Item) error {
+		handled = append(handled, item.Resource.(*testResource).Name)
+		if item.Resource.(*testResource).Name == "two" {
+			return errors.New("unable to record")
+		}
+		return nil
+	})
+
+	n.HandleQueue(context.TODO())
+
+	assert.ElementsMatch(t, []string{"one", "two"}, handled)
+	assert.Equal(t, 1, n.Queue.Count(queue.ItemStatePending, queue.ItemStateWaiting))
+	assert.Equal(t, 1, n.Queue.Count(queue.ItemStateFailed))
+
+	testResources.Lock()
+	defer testResources.Unlock()
+	assert.Equal(t, []string{"two"}, testResources.names["us-east-1"])
+}
+
+func TestNuke_AfterRemoveHandlers(t *testing.T) {
+	setTestResources(map[string][]string{
+		"us-east-1": {"one", "two"},
+	})
+
+	n := newTestNuke(t, "us-east-1")
+	assert.NoError(t, n.Scan(context.TODO()))
+
+	n.RegisterBeforeRemoveHandler(func(_ context.Context, item *queue.Item) error {
+		if item.Resource.(*testResource).Name == "two" {
+			return errors.New("refused")
+		}
+		return nil
+	})
+
+	var handled []string
+	n.RegisterAfterRemoveHandler(func(_ context.Context, item *queue.Item) error {
+		handled = append(handled, item.Resource.(*testResource).Name)
+		return errors.New("unable to deregister")
+	})
+
+	n.HandleQueue(context.TODO())
+
+	// A failing after remove handler does not change the state of the item
+	assert.Equal(t, []string{"one"}, handled)
+	assert.Equal(t, 1, n.Queue.Count(queue.ItemStatePending, queue.ItemStateWaiting))
+	assert.Equal(t, 1, n.Queue.Count(queue.ItemStateFailed))
+}
+
+func TestNuke_RunRemovalFailed(t *testing.T) {
+	setTestResources(map[string][]string{
+		"us-east-1": {"one", "two"},
+	})
+
+	n := newTestNuke(t, "us-east-1")
+	n.RegisterBeforeRemoveHandler(func(_ context.Context, item *queue.Item) error {
+		if item.Resource.(*testResource).Name == "two" {
+			return errors.New("refused")
+		}
+		return nil
+	})
+
+	err := n.Run(context.TODO())
+	var removalErr *RemovalError
+	assert.ErrorAs(t, err, &removalErr)
+	assert.EqualError(t, err, "failed")
+
+	summary := NewSummary(n.Queue)
+	assert.Equal(t, []string{testResourceType}, summary.FailedResourceTypes())
+	assert.Equal(t, SummaryRow{Region: "total", Removed: 1, Failed: 1}, summary.Total)
+}
@@ -0,0 +1,34 @@
+package nuke
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+	"github.com/ekristen/libnuke/pkg/resource"
+
+	"github.com/ekristen/aws-nuke/v3/pkg/config"
+)
+
+// TagRefresher is implemented by resources that can read their tags again. When protection tags are configured the tags
+// are refreshed right before the resource is removed, so a protection tag that was added after the scan is enforced.
+type TagRefresher interface {
+	RefreshTags(ctx context.Context) error
+}
+
+// ProtectionTagsFilter returns an item filter that filters every resource that has one of the protection tags of the
+// configuration. Resources that do not expose their tags as properties cannot be protected by tag.
+func ProtectionTagsFilter(c *config.Config) ItemFilter {
+	return func(item *queue.Item) error {
+		getter, ok := item.Resource.(resource.PropertyGetter)
+		if !ok {
+			return nil
+		}
+
+		if tag := c.ProtectedBy(getter.Properties()); tag != "" {
+			return fmt.Errorf("protected by tag '%s'", tag)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,114 @@
+package nuke
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gotidy/ptr"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+	"github.com/ekristen/libnuke/pkg/types"
+
+	"github.com/ekristen/aws-nuke/v3/pkg/config"
+)
+
+// refreshTestResource is a test resource whose tags change between the scan and the removal.
+type refreshTestResource struct {
+	testResource
+
+	tags      map[string]string
+	refreshed map[string]string
+	err       error
+}
+
+func (r *refreshTestResource) RefreshTags(_ context.Context) error {
+	if r.err != nil {
+		return r.err
+	}
+
+	r.tags = r.refreshed
+	return nil
+}
+
+func (r *refreshTestResource) Properties() types.Properties {
+	properties := types.NewProperties().Set("Name", r.Name)
+	for key, value := range r.tags {
+		properties.SetTag(ptr.String(key), value)
+	}
+
+	return properties
+}
+
+func TestNuke_RefreshTagsBeforeRemove(t *testing.T) {
+	cases := []struct {
+		name        string
+		refresh     bool
+		refreshed   map[string]string
+		err         error
+		wantState   queue.ItemState
+		wantReason  string
+		wantRemoved bool
+	}{
+		{
+			name:       "tag-added-after-scan",
+			refresh:    true,
+			refreshed:  map[string]string{"do-not-nuke": "true"},
+			wantState:  queue.ItemStateFiltered,
+			wantReason: "protected by tag 'do-not-nuke'",
+		},
+		{
+			name:        "untagged",
+			refresh:     true,
+			refreshed:   map[string]string{"env": "dev"},
+			wantState:   queue.ItemStatePending,
+			wantRemoved: true,
+		},
+		{
+			name:       "refresh-failed",
+			refresh:    true,
+			err:        errors.New("access denied"),
+			wantState:  queue.ItemStateFailed,
+			wantReason: "unable to refresh tags: access denied",
+		},
+		{
+			name:        "refresh-disabled",
+			refreshed:   map[string]string{"do-not-nuke": "true"},
+			wantState:   queue.ItemStatePending,
+			wantRemoved: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			setTestResources(map[string][]string{
+				"us-east-1": {"one"},
+			})
+
+			n := newTestNuke(t)
+			n.RegisterItemFilter(ProtectionTagsFilter(&config.Config{ProtectionTags: []string{"do-not-nuke"}}))
+			n.SetRefreshTags(tc.refresh)
+
+			item := &queue.Item{
+				Resource: &refreshTestResource{
+					testResource: testResource{Name: "one", Owner: "us-east-1"},
+					refreshed:    tc.refreshed,
+					err:          tc.err,
+				},
+				State: queue.ItemStateNew,
+				Type:  testResourceType,
+				Owner: "us-east-1",
+			}
+
+			n.HandleRemove(context.TODO(), item)
+
+			assert.Equal(t, tc.wantState, item.GetState())
+			assert.Equal(t, tc.wantReason, item.GetReason())
+
+			testResources.Lock()
+			defer testResources.Unlock()
+			assert.Equal(t, tc.wantRemoved, len(testResources.names["us-east-1"]) == 0)
+		})
+	}
+}
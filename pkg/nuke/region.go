@@ -27,9 +27,14 @@ type Region struct {
 	NewConfig       ConfigFactory  // SDK v2
 	ResTypeResolver ResourceTypeResolver
 
-	cache    map[string]*session.Session
-	cfgCache map[string]*awsv2.Config
-	lock     *sync.RWMutex
+	cache map[string]*regionClients
+	lock  *sync.RWMutex
+}
+
+// regionClients are the SDK v1 session and the SDK v2 config of a service in the region, each is created on first use.
+type regionClients struct {
+	session *session.Session
+	config  *awsv2.Config
 }
 
 // NewRegion creates a new Region and returns it.
@@ -40,66 +45,93 @@ func NewRegion(name string, typeResolver ResourceTypeResolver, sessionFactory Se
 		NewConfig:       cfgFactory,
 		ResTypeResolver: typeResolver,
 		lock:            &sync.RWMutex{},
-		cache:           make(map[string]*session.Session),
-		cfgCache:        make(map[string]*awsv2.Config),
+		cache:           make(map[string]*regionClients),
 	}
 }
 
 // Session returns a session for a given resource type for the region it's associated to.
 func (region *Region) Session(resourceType string) (*session.Session, error) {
-	svcType := region.ResTypeResolver(region.Name, resourceType)
-	if svcType == "" {
-		return nil, liberrors.ErrSkipRequest(fmt.Sprintf(
-			"No service available in region '%s' to handle '%s'",
-			region.Name, resourceType))
+	svcType, err := region.serviceType(resourceType)
+	if err != nil {
+		return nil, err
 	}
 
 	// Need to read
 	region.lock.RLock()
-	sess := region.cache[svcType]
+	clients := region.cache[svcType]
 	region.lock.RUnlock()
-	if sess != nil {
-		return sess, nil
+	if clients != nil && clients.session != nil {
+		return clients.session, nil
 	}
 
 	// Need to write:
 	region.lock.Lock()
-	sess, err := region.NewSession(region.Name, svcType)
-	if err != nil {
-		region.lock.Unlock()
-		return nil, err
+	defer region.lock.Unlock()
+
+	clients = region.clients(svcType)
+	if clients.session == nil {
+		sess, err := region.NewSession(region.Name, svcType)
+		if err != nil {
+			return nil, err
+		}
+		clients.session = sess
 	}
-	region.cache[svcType] = sess
-	region.lock.Unlock()
-	return sess, nil
+
+	return clients.session, nil
 }
 
 // Config returns an SDK v2 config for a given resource type for the region
 // it's associated to.
 func (region *Region) Config(resourceType string) (*awsv2.Config, error) {
-	svcType := region.ResTypeResolver(region.Name, resourceType)
-	if svcType == "" {
-		return nil, liberrors.ErrSkipRequest(fmt.Sprintf(
-			"No service available in region '%s' to handle '%s'",
-			region.Name, resourceType))
+	svcType, err := region.serviceType(resourceType)
+	if err != nil {
+		return nil, err
 	}
 
 	// Need to read
 	region.lock.RLock()
-	cfg := region.cfgCache[svcType]
+	clients := region.cache[svcType]
 	region.lock.RUnlock()
-	if cfg != nil {
-		return cfg, nil
+	if clients != nil && clients.config != nil {
+		return clients.config, nil
 	}
 
 	// Need to write:
 	region.lock.Lock()
-	cfg, err := region.NewConfig(context.TODO(), region.Name, svcType)
-	if err != nil {
-		region.lock.Unlock()
-		return nil, err
+	defer region.lock.Unlock()
+
+	clients = region.clients(svcType)
+	if clients.config == nil {
+		cfg, err := region.NewConfig(context.TODO(), region.Name, svcType)
+		if err != nil {
+			return nil, err
+		}
+		clients.config = cfg
 	}
-	region.cfgCache[svcType] = cfg
-	region.lock.Unlock()
-	return cfg, nil
+
+	return clients.config, nil
+}
+
+// serviceType returns the service type of the resource type, resource types without a service in the region are
+// skipped.
+func (region *Region) serviceType(resourceType string) (string, error) {
+	svcType := region.ResTypeResolver(region.Name, resourceType)
+	if svcType == "" {
+		return "", liberrors.ErrSkipRequest(fmt.Sprintf(
+			"No service available in region '%s' to handle '%s'",
+			region.Name, resourceType))
+	}
+
+	return svcType, nil
+}
+
+// clients returns the cache entry of the service type, the lock must be held for writing.
+func (region *Region) clients(svcType string) *regionClients {
+	clients := region.cache[svcType]
+	if clients == nil {
+		clients = &regionClients{}
+		region.cache[svcType] = clients
+	}
+
+	return clients
 }
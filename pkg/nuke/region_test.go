@@ -0,0 +1,96 @@
+package nuke
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	awsv2 "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go/aws/session" //nolint:staticcheck
+
+	liberrors "github.com/ekristen/libnuke/pkg/errors"
+)
+
+func init() {
+	RegisterSDKv2("NukeTestSDKv2")
+}
+
+// newTestRegion returns a region with factories that count how often they are called per service type.
+func newTestRegion(sessions, configs map[string]int) *Region {
+	return NewRegion("us-east-1",
+		func(_, resourceType string) string {
+			if resourceType == "NukeTestUnavailable" {
+				return ""
+			}
+			return "svc-" + resourceType
+		},
+		func(_, svcType string) (*session.Session, error) {
+			sessions[svcType]++
+			if svcType == "svc-NukeTestFailing" {
+				return nil, errors.New("no credentials")
+			}
+			return &session.Session{}, nil
+		},
+		func(_ context.Context, _, svcType string) (*awsv2.Config, error) {
+			configs[svcType]++
+			return &awsv2.Config{}, nil
+		},
+	)
+}
+
+func TestListerOpts_SetClients(t *testing.T) {
+	sessions := map[string]int{}
+	configs := map[string]int{}
+	opts := &ListerOpts{Region: newTestRegion(sessions, configs)}
+
+	assert.NoError(t, opts.SetClients("NukeTestSDKv1"))
+	assert.NotNil(t, opts.Session)
+	assert.NotNil(t, opts.Config)
+
+	// Only the config is created for resource types that are fully migrated to SDK v2
+	assert.NoError(t, opts.SetClients("NukeTestSDKv2"))
+	assert.Nil(t, opts.Session)
+	assert.NotNil(t, opts.Config)
+
+	// The session and the config are cached per service type
+	assert.NoError(t, opts.SetClients("NukeTestSDKv1"))
+	assert.NoError(t, opts.SetClients("NukeTestSDKv2"))
+
+	assert.Equal(t, map[string]int{"svc-NukeTestSDKv1": 1}, sessions)
+	assert.Equal(t, map[string]int{"svc-NukeTestSDKv1": 1, "svc-NukeTestSDKv2": 1}, configs)
+}
+
+func TestListerOpts_SetClientsErrors(t *testing.T) {
+	opts := &ListerOpts{Region: newTestRegion(map[string]int{}, map[string]int{})}
+
+	err := opts.SetClients("NukeTestUnavailable")
+	var errSkipRequest liberrors.ErrSkipRequest
+	assert.ErrorAs(t, err, &errSkipRequest)
+
+	assert.EqualError(t, opts.SetClients("NukeTestFailing"), "no credentials")
+}
+
+func TestIsSDKv2(t *testing.T) {
+	assert.True(t, IsSDKv2("NukeTestSDKv2"))
+	assert.False(t, IsSDKv2("NukeTestSDKv1"))
+	assert.Contains(t, GetSDKv2ResourceTypes(), "NukeTestSDKv2")
+}
+
+func TestMutateOpts(t *testing.T) {
+	opts := &ListerOpts{Region: newTestRegion(map[string]int{}, map[string]int{})}
+
+	mutated, err := MutateOpts(opts, "NukeTestSDKv1")
+	assert.NoError(t, err)
+	assert.NotNil(t, mutated.(*ListerOpts).Session)
+	assert.NotNil(t, mutated.(*ListerOpts).Logger)
+
+	// The options of the scanner are not changed, they are shared by the listers running in parallel
+	assert.Nil(t, opts.Session)
+	assert.Nil(t, opts.Logger)
+
+	_, err = MutateOpts(opts, "NukeTestUnavailable")
+	var errSkipRequest liberrors.ErrSkipRequest
+	assert.ErrorAs(t, err, &errSkipRequest)
+}
@@ -17,37 +17,37 @@ const Account registry.Scope = "account"
 // the interface{} to get the options it needs.
 type ListerOpts struct {
 	Region    *Region
-	Session   *session.Session // SDK v1
+	Session   *session.Session // SDK v1, nil for resource types that are fully migrated to SDK v2
 	Config    *aws.Config      // SDK v2
 	AccountID *string
 	Logger    *logrus.Entry
+	Cache     *ListCache // shared by the listers of the region, see Cached
+
+	// ResolveSettings resolves the settings of a resource as the run does, for listers whose resources depend on the
+	// settings of another resource type. It is nil when the listers run without a run.
+	ResolveSettings SettingsResolver
 }
 
+// MutateOptsFunc returns the lister options for a resource type. An error skips the resource type, ErrSkipRequest and
+// ErrUnknownEndpoint quietly, any other error is reported as a lister failure.
+type MutateOptsFunc func(opts interface{}, resourceType string) (interface{}, error)
+
 // MutateOpts is a function that will be called for each resource type to mutate the options for the scanner based on
 // whatever criteria you want. However, in this case for the aws-nuke tool, it's mutating the opts to create the proper
 // session for the proper region for the resourceType. For example IAM only happens in the global region, not us-east-2.
-var MutateOpts = func(opts interface{}, resourceType string) interface{} {
-	o := opts.(*ListerOpts)
-
-	session, err := o.Region.Session(resourceType)
-	if err != nil {
-		panic(err)
-	}
-
-	o.Session = session
+// The options of the scanner are copied, the listers of the resource types run in parallel.
+var MutateOpts = func(opts interface{}, resourceType string) (interface{}, error) {
+	o := *opts.(*ListerOpts)
 
-	cfg, err := o.Region.Config(resourceType)
-	if err != nil {
-		panic(err)
+	if err := o.SetClients(resourceType); err != nil {
+		return nil, err
 	}
 
-	o.Config = cfg
-
 	if o.Logger != nil {
 		o.Logger = o.Logger.WithField("resource", resourceType)
 	} else {
 		o.Logger = logrus.WithField("resource", resourceType)
 	}
 
-	return o
+	return &o, nil
 }
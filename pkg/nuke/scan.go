@@ -0,0 +1,343 @@
+package nuke
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	liberrors "github.com/ekristen/libnuke/pkg/errors"
+	"github.com/ekristen/libnuke/pkg/queue"
+	"github.com/ekristen/libnuke/pkg/registry"
+	"github.com/ekristen/libnuke/pkg/resource"
+	"github.com/ekristen/libnuke/pkg/scanner"
+	"github.com/ekristen/libnuke/pkg/utils"
+)
+
+const (
+	// DefaultScanConcurrency is the number of listers that run at the same time across all scanners.
+	DefaultScanConcurrency = 32
+
+	// DefaultScanProgressInterval is how often the progress of the scan is printed.
+	DefaultScanProgressInterval = 15 * time.Second
+)
+
+// scanJob lists one resource type for one scanner.
+type scanJob struct {
+	scanner      *scanner.Scanner
+	resourceType string
+}
+
+// scanResult is the outcome of a scan job.
+type scanResult struct {
+	job      *scanJob
+	items    []*queue.Item
+	err      error
+	duration time.Duration
+}
+
+// scanProgress is the progress of the scan of one owner, for AWS the owner is the region.
+type scanProgress struct {
+	done    int
+	total   int
+	items   int
+	running int
+	started time.Time
+}
+
+// SetScanConcurrency sets the number of listers that run at the same time across all scanners, and the number of
+// listers that run at the same time for a single scanner. A limit of zero uses the default.
+func (n *Nuke) SetScanConcurrency(budget, perScanner int64) {
+	n.scanConcurrency = budget
+	n.scanConcurrencyPerScanner = perScanner
+}
+
+// RegisterMutateOptsFunc registers the function that mutates the lister options for each resource type of a scanner.
+// It is optional.
+func (n *Nuke) RegisterMutateOptsFunc(mutateOpts MutateOptsFunc) {
+	n.mutateOpts = mutateOpts
+}
+
+// scanAll runs the listers of all scanners within the concurrency budget. Resource types that were slow to list for
+// one scanner are started first for the other scanners, so that a slow lister does not hold up the end of the scan.
+// Like the scanners of libnuke, at most the queue size of a scanner is queued, the other items of it are dropped.
+func (n *Nuke) scanAll(ctx context.Context, itemQueue *queue.Queue) error { //nolint:funlen,gocyclo
+	// The listers that are still running are canceled when the scan is stopped early
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	budget := n.scanConcurrency
+	if budget <= 0 {
+		budget = DefaultScanConcurrency
+	}
+
+	perScanner := n.scanConcurrencyPerScanner
+	if perScanner <= 0 || perScanner > budget {
+		perScanner = budget
+	}
+
+	// The jobs are interleaved across the scanners, so every scanner makes progress from the start
+	var pending []*scanJob
+	progress := map[string]*scanProgress{}
+	var owners []string
+	var scanners []*scanner.Scanner
+	for _, ownerScanners := range n.Scanners {
+		for _, s := range ownerScanners {
+			scanners = append(scanners, s)
+			if _, ok := progress[s.Owner]; !ok {
+				progress[s.Owner] = &scanProgress{started: time.Now()}
+				owners = append(owners, s.Owner)
+			}
+			progress[s.Owner].total += len(s.ResourceTypes)
+		}
+	}
+
+	for i := 0; ; i++ {
+		added := false
+		for _, s := range scanners {
+			if i < len(s.ResourceTypes) {
+				pending = append(pending, &scanJob{scanner: s, resourceType: s.ResourceTypes[i]})
+				added = true
+			}
+		}
+		if !added {
+			break
+		}
+	}
+
+	durations := map[string]time.Duration{}
+	queued := map[*scanner.Scanner]int{}
+	queueFull := map[*scanner.Scanner]bool{}
+
+	// Every job can send its result without a receiver, so no lister is blocked when the scan returns early
+	results := make(chan *scanResult, len(pending))
+	running := int64(0)
+
+	ticker := time.NewTicker(n.scanProgressInterval())
+	defer ticker.Stop()
+
+	printLog := n.log.WithField("_handler", "println")
+
+	for len(pending) > 0 || running > 0 {
+		// Start as many jobs as the budget allows
+		for running < budget {
+			job := n.nextScanJob(pending, progress, durations, perScanner)
+			if job == nil {
+				break
+			}
+
+			pending = removeScanJob(pending, job)
+			running++
+			progress[job.scanner.Owner].running++
+
+			go func(job *scanJob) {
+				start := time.Now()
+				items, err := n.runScanJob(ctx, job)
+				results <- &scanResult{job: job, items: items, err: err, duration: time.Since(start)}
+			}(job)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			n.printScanProgress(owners, progress)
+		case result := <-results:
+			running--
+
+			owner := result.job.scanner.Owner
+			p := progress[owner]
+			p.running--
+			p.done++
+
+			if result.err != nil {
+				n.ListFailures = append(n.ListFailures, &ListFailure{
+					Region:       owner,
+					ResourceType: result.job.resourceType,
+					Error:        result.err,
+				})
+			}
+
+			if result.duration > durations[result.job.resourceType] {
+				durations[result.job.resourceType] = result.duration
+			}
+
+			for _, item := range result.items {
+				s := result.job.scanner
+				if size := cap(s.Items); size > 0 && queued[s] >= size {
+					if !queueFull[s] {
+						n.log.WithField("owner", owner).Warn("item queue is full, not all resources will be enqueued")
+						queueFull[s] = true
+					}
+					break
+				}
+				queued[s]++
+
+				if err := n.processItem(item, itemQueue); err != nil {
+					return err
+				}
+
+				if stream, ok := item.Resource.(*StreamResource); ok {
+					p.items += stream.Count
+				} else {
+					p.items++
+				}
+			}
+
+			if p.done == p.total {
+				printLog.Infof("Scan of %s complete: %d resource types, %d resources in %s.",
+					owner, p.total, p.items, time.Since(p.started).Round(time.Second))
+			}
+		}
+	}
+
+	return nil
+}
+
+// nextScanJob returns the pending job with the highest priority whose scanner is below its limit. Jobs for resource
+// types that have not been listed yet come first, then the resource types that were the slowest to list.
+func (n *Nuke) nextScanJob(
+	pending []*scanJob, progress map[string]*scanProgress, durations map[string]time.Duration, perScanner int64,
+) *scanJob {
+	var next *scanJob
+	var nextDuration time.Duration
+
+	for _, job := range pending {
+		if int64(progress[job.scanner.Owner].running) >= perScanner {
+			continue
+		}
+
+		duration, known := durations[job.resourceType]
+		if !known {
+			duration = time.Duration(1<<63 - 1)
+		}
+
+		if next == nil || duration > nextDuration {
+			next = job
+			nextDuration = duration
+		}
+	}
+
+	return next
+}
+
+func removeScanJob(pending []*scanJob, job *scanJob) []*scanJob {
+	for i, p := range pending {
+		if p == job {
+			return append(pending[:i], pending[i+1:]...)
+		}
+	}
+
+	return pending
+}
+
+func (n *Nuke) scanProgressInterval() time.Duration {
+	if n.scanProgress > 0 {
+		return n.scanProgress
+	}
+
+	return DefaultScanProgressInterval
+}
+
+// printScanProgress prints how many resource types have been listed for each owner that is not complete.
+func (n *Nuke) printScanProgress(owners []string, progress map[string]*scanProgress) {
+	var parts []string
+	for _, owner := range owners {
+		p := progress[owner]
+		if p.done < p.total {
+			parts = append(parts, fmt.Sprintf("%s %d/%d", owner, p.done, p.total))
+		}
+	}
+
+	if len(parts) == 0 {
+		return
+	}
+
+	n.log.WithField("_handler", "println").Infof("Scan progress: %s", strings.Join(parts, ", "))
+}
+
+// runScanJob mutates the options and runs the lister of a scan job. Resource types that are not available are
+// skipped, other errors are logged and returned so they are reported without stopping the scan.
+func (n *Nuke) runScanJob(ctx context.Context, job *scanJob) ([]*queue.Item, error) {
+	logger := logrus.WithField("resource_type", job.resourceType).WithField("owner", job.scanner.Owner)
+
+	var err error
+	opts := job.scanner.Options
+	if n.mutateOpts != nil {
+		opts, err = n.mutateOpts(opts, job.resourceType)
+	}
+
+	var items []*queue.Item
+	if err == nil {
+		items, err = n.list(ctx, job.scanner.Owner, job.resourceType, opts)
+	}
+
+	if err != nil {
+		var errSkipRequest liberrors.ErrSkipRequest
+		var errUnknownEndpoint liberrors.ErrUnknownEndpoint
+		if errors.As(err, &errSkipRequest) || errors.As(err, &errUnknownEndpoint) {
+			logger.Debugf("skipping request: %v", err)
+			return nil, nil
+		}
+
+		dump := utils.Indent(fmt.Sprintf("%v", err), "    ")
+		logger.WithError(err).Errorf("listing failed:\n%s", dump)
+
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// list runs the lister of a resource type and returns its resources as queue items. A panic of the lister is
+// returned as an error.
+func (n *Nuke) list(ctx context.Context, owner, resourceType string, opts interface{}) (items []*queue.Item, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			items = nil
+			err = fmt.Errorf("%v\n\n%s", r, string(debug.Stack()))
+		}
+	}()
+
+	lister := registry.GetLister(resourceType)
+	if lister == nil {
+		return nil, fmt.Errorf("lister for resource type not found")
+	}
+
+	logger := logrus.WithField("resource_type", resourceType).WithField("owner", owner)
+	logger.Debug("attempting to run lister")
+
+	if streamLister, ok := lister.(StreamLister); ok {
+		return n.scanStream(ctx, streamLister, resourceType, owner, opts)
+	}
+
+	rs, err := lister.List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.WithField("count", len(rs)).Debugf("listing complete")
+
+	for _, r := range rs {
+		item := &queue.Item{
+			Resource: r,
+			State:    queue.ItemStateNew,
+			Type:     resourceType,
+			Owner:    owner,
+			Opts:     opts,
+			Logger:   n.log.Logger,
+		}
+
+		if itemHook, ok := r.(resource.QueueItemHook); ok {
+			itemHook.BeforeEnqueue(item)
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
@@ -0,0 +1,239 @@
+package nuke
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	liberrors "github.com/ekristen/libnuke/pkg/errors"
+	"github.com/ekristen/libnuke/pkg/filter"
+	libnuke "github.com/ekristen/libnuke/pkg/nuke"
+	"github.com/ekristen/libnuke/pkg/registry"
+	"github.com/ekristen/libnuke/pkg/resource"
+	"github.com/ekristen/libnuke/pkg/scanner"
+	libsettings "github.com/ekristen/libnuke/pkg/settings"
+)
+
+// scanTestListers tracks how many listers run at the same time, overall and per owner.
+var scanTestListers = struct {
+	sync.Mutex
+	running    int
+	maxRunning int
+	perOwner   map[string]int
+	maxOwner   int
+}{perOwner: map[string]int{}}
+
+var scanTestResourceTypes = []string{"NukeTestScanA", "NukeTestScanB", "NukeTestScanC"}
+
+func init() {
+	for _, resourceType := range scanTestResourceTypes {
+		registry.Register(&registry.Registration{
+			Name:     resourceType,
+			Scope:    Account,
+			Resource: &testResource{},
+			Lister:   &scanTestLister{},
+		})
+	}
+
+	registry.Register(&registry.Registration{
+		Name:     "NukeTestScanPanic",
+		Scope:    Account,
+		Resource: &testResource{},
+		Lister:   &scanTestLister{panic: true},
+	})
+
+	registry.Register(&registry.Registration{
+		Name:     "NukeTestScanError",
+		Scope:    Account,
+		Resource: &testResource{},
+		Lister:   &scanTestLister{err: errors.New("access denied")},
+	})
+}
+
+type scanTestLister struct {
+	panic bool
+	err   error
+}
+
+func (l *scanTestLister) List(_ context.Context, o interface{}) ([]resource.Resource, error) {
+	opts := o.(*ListerOpts)
+
+	if l.panic {
+		panic("lister panic")
+	}
+
+	if l.err != nil {
+		return nil, l.err
+	}
+
+	scanTestListers.Lock()
+	scanTestListers.running++
+	scanTestListers.perOwner[*opts.AccountID]++
+	scanTestListers.maxRunning = max(scanTestListers.maxRunning, scanTestListers.running)
+	scanTestListers.maxOwner = max(scanTestListers.maxOwner, scanTestListers.perOwner[*opts.AccountID])
+	scanTestListers.Unlock()
+
+	time.Sleep(10 * time.Millisecond)
+
+	scanTestListers.Lock()
+	scanTestListers.running--
+	scanTestListers.perOwner[*opts.AccountID]--
+	scanTestListers.Unlock()
+
+	return []resource.Resource{&testResource{Name: "scanned", Owner: *opts.AccountID}}, nil
+}
+
+func newScanTestNuke(t *testing.T, resourceTypes []string, owners ...string) *Nuke {
+	scanTestListers.Lock()
+	scanTestListers.maxRunning = 0
+	scanTestListers.maxOwner = 0
+	scanTestListers.Unlock()
+
+	n := New(&libnuke.Parameters{}, filter.Filters{}, &libsettings.Settings{})
+
+	for _, owner := range owners {
+		s, err := scanner.New(&scanner.Config{
+			Owner:         owner,
+			ResourceTypes: resourceTypes,
+			Opts:          &ListerOpts{AccountID: &owner},
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, n.RegisterScanner(Account, s))
+	}
+
+	return n
+}
+
+func TestNuke_ScanConcurrency(t *testing.T) {
+	cases := []struct {
+		name       string
+		budget     int64
+		perScanner int64
+		wantMax    int
+		wantOwner  int
+	}{
+		{name: "budget", budget: 2, wantMax: 2, wantOwner: 2},
+		{name: "per-scanner", budget: 8, perScanner: 1, wantMax: 4, wantOwner: 1},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			owners := []string{"eu-west-1", "us-east-1", "us-west-2", "ap-south-1"}
+			n := newScanTestNuke(t, scanTestResourceTypes, owners...)
+			n.SetScanConcurrency(tc.budget, tc.perScanner)
+
+			assert.NoError(t, n.Scan(context.TODO()))
+			assert.Equal(t, len(owners)*len(scanTestResourceTypes), n.Queue.Total())
+
+			assert.LessOrEqual(t, scanTestListers.maxRunning, tc.wantMax)
+			assert.LessOrEqual(t, scanTestListers.maxOwner, tc.wantOwner)
+		})
+	}
+}
+
+func TestNuke_ScanMutateOpts(t *testing.T) {
+	n := newScanTestNuke(t, scanTestResourceTypes, "eu-west-1")
+
+	var lock sync.Mutex
+	var mutated []string
+	n.RegisterMutateOptsFunc(func(opts interface{}, resourceType string) (interface{}, error) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		mutated = append(mutated, resourceType)
+
+		switch resourceType {
+		case "NukeTestScanA":
+			return nil, liberrors.ErrSkipRequest("service not available")
+		case "NukeTestScanB":
+			return nil, errors.New("no credentials")
+		}
+
+		return opts, nil
+	})
+
+	assert.NoError(t, n.Scan(context.TODO()))
+	assert.ElementsMatch(t, scanTestResourceTypes, mutated)
+
+	// A skipped resource type is not a failure, other errors are reported as lister failures
+	assert.Equal(t, 1, n.Queue.Total())
+	assert.Equal(t, "NukeTestScanC", n.Queue.GetItems()[0].Type)
+	assert.Equal(t, []*ListFailure{
+		{Region: "eu-west-1", ResourceType: "NukeTestScanB", Error: errors.New("no credentials")},
+	}, n.ListFailures)
+}
+
+func TestNuke_ScanListerFailures(t *testing.T) {
+	n := newScanTestNuke(t, []string{"NukeTestScanPanic", "NukeTestScanError", "NukeTestScanA"}, "eu-west-1")
+
+	assert.NoError(t, n.Scan(context.TODO()))
+	assert.Equal(t, 1, n.Queue.Total())
+	assert.Equal(t, "NukeTestScanA", n.Queue.GetItems()[0].Type)
+
+	failures := n.Summary().ListFailures
+	assert.Len(t, failures, 2)
+	assert.Equal(t, "NukeTestScanError", failures[0].ResourceType)
+	assert.EqualError(t, failures[0].Error, "access denied")
+	assert.Equal(t, "NukeTestScanPanic", failures[1].ResourceType)
+	assert.ErrorContains(t, failures[1].Error, "lister panic")
+}
+
+func TestNuke_ScanQueueSize(t *testing.T) {
+	n := New(&libnuke.Parameters{}, filter.Filters{}, &libsettings.Settings{})
+
+	for _, owner := range []string{"eu-west-1", "us-east-1"} {
+		s, err := scanner.New(&scanner.Config{
+			Owner:         owner,
+			ResourceTypes: scanTestResourceTypes,
+			Opts:          &ListerOpts{AccountID: &owner},
+			QueueSize:     2,
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, n.RegisterScanner(Account, s))
+	}
+
+	// Each scanner queues at most its queue size
+	assert.NoError(t, n.Scan(context.TODO()))
+	assert.Equal(t, 4, n.Queue.Total())
+}
+
+func TestNuke_NextScanJob(t *testing.T) {
+	east := &scanner.Scanner{Owner: "us-east-1"}
+	west := &scanner.Scanner{Owner: "us-west-2"}
+
+	pending := []*scanJob{
+		{scanner: east, resourceType: "Fast"},
+		{scanner: east, resourceType: "Slow"},
+		{scanner: west, resourceType: "Fast"},
+		{scanner: west, resourceType: "Slow"},
+		{scanner: west, resourceType: "Unknown"},
+	}
+	progress := map[string]*scanProgress{
+		"us-east-1": {},
+		"us-west-2": {},
+	}
+	durations := map[string]time.Duration{
+		"Fast": time.Second,
+		"Slow": time.Minute,
+	}
+
+	n := &Nuke{}
+
+	// Resource types that have not been listed yet come first
+	assert.Equal(t, pending[4], n.nextScanJob(pending, progress, durations, 2))
+
+	// Then the slowest resource types, in the original order
+	pending = removeScanJob(pending, pending[4])
+	assert.Equal(t, pending[1], n.nextScanJob(pending, progress, durations, 2))
+
+	// Scanners that reached their limit are skipped
+	progress["us-east-1"].running = 2
+	assert.Equal(t, pending[3], n.nextScanJob(pending, progress, durations, 2))
+
+	progress["us-west-2"].running = 2
+	assert.Nil(t, n.nextScanJob(pending, progress, durations, 2))
+}
@@ -0,0 +1,57 @@
+package nuke
+
+import (
+	"sort"
+)
+
+// sdkV2ResourceTypes are the resource types that are fully migrated to SDK v2.
+var sdkV2ResourceTypes = map[string]struct{}{}
+
+// RegisterSDKv2 marks resource types as fully migrated to SDK v2. Only the SDK v2 config is created for them, the
+// SDK v1 session of their ListerOpts is nil. Resource types that are not marked get both until they are migrated.
+func RegisterSDKv2(resourceTypes ...string) {
+	for _, resourceType := range resourceTypes {
+		sdkV2ResourceTypes[resourceType] = struct{}{}
+	}
+}
+
+// IsSDKv2 returns true if the resource type is fully migrated to SDK v2.
+func IsSDKv2(resourceType string) bool {
+	_, ok := sdkV2ResourceTypes[resourceType]
+	return ok
+}
+
+// GetSDKv2ResourceTypes returns the resource types that are fully migrated to SDK v2, sorted by name.
+func GetSDKv2ResourceTypes() []string {
+	resourceTypes := make([]string, 0, len(sdkV2ResourceTypes))
+	for resourceType := range sdkV2ResourceTypes {
+		resourceTypes = append(resourceTypes, resourceType)
+	}
+
+	sort.Strings(resourceTypes)
+
+	return resourceTypes
+}
+
+// SetClients sets the SDK v1 session and the SDK v2 config of the options for the resource type. The session is only
+// created for resource types that are not fully migrated to SDK v2.
+func (o *ListerOpts) SetClients(resourceType string) error {
+	o.Session = nil
+	if !IsSDKv2(resourceType) {
+		sess, err := o.Region.Session(resourceType)
+		if err != nil {
+			return err
+		}
+
+		o.Session = sess
+	}
+
+	cfg, err := o.Region.Config(resourceType)
+	if err != nil {
+		return err
+	}
+
+	o.Config = cfg
+
+	return nil
+}
@@ -0,0 +1,335 @@
+package nuke
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+	"github.com/ekristen/libnuke/pkg/resource"
+	"github.com/ekristen/libnuke/pkg/types"
+	"github.com/ekristen/libnuke/pkg/unique"
+)
+
+// StreamLister is an optional capability of listers of resource types with very large numbers of resources, like the
+// objects of S3 buckets. The resources are listed in pages that are filtered and removed one at a time, so the memory
+// used is bounded by the size of a page. The resources of a stream lister in a region are represented in the queue by
+// a single item, the resources are listed again when it is removed.
+type StreamLister interface {
+	// ListPages calls page for each page of resources, it stops at the first error of page.
+	ListPages(ctx context.Context, opts interface{}, page func([]resource.Resource) error) error
+}
+
+// ListPages returns all resources of a stream lister, it is used to implement the List method of stream listers.
+func ListPages(ctx context.Context, lister StreamLister, opts interface{}) ([]resource.Resource, error) {
+	resources := make([]resource.Resource, 0)
+	if err := lister.ListPages(ctx, opts, func(page []resource.Resource) error {
+		resources = append(resources, page...)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return resources, nil
+}
+
+// LastModifier is implemented by the resources of stream listers that know when they were created or last modified,
+// like the versions of S3 objects. A resource that was modified after the scan of its stream started is not removed.
+type LastModifier interface {
+	LastModified() *time.Time
+}
+
+// StreamResource is the queue item of the resources of a stream lister in a region. Removing it lists the resources
+// again, filters them and removes those that are not filtered, page by page. No state is kept per resource, so a
+// resource that appeared since the scan is recognized by what is listed with it: resources that were modified after
+// the scan started are left alone, and no more resources are removed than the scan found removable.
+type StreamResource struct {
+	nuke         *Nuke
+	lister       StreamLister
+	resourceType string
+	owner        string
+	opts         interface{}
+	started      time.Time // started is when the scan of the stream started
+
+	// Count is the number of resources, Removable the number of resources that are not filtered.
+	Count     int
+	Removable int
+
+	// Digest is the SHA-256 digest of the removable resources of the scan, in the order they were listed.
+	Digest string
+
+	// Removed counts the resources removed in all attempts, Failed and Filtered the resources of the last attempt.
+	Removed  int
+	Failed   int
+	Filtered int
+}
+
+// Remove lists the resources and removes those that are not filtered page by page, with the batch remover of the
+// lister when it has one. It fails when any of the resources could not be removed.
+func (r *StreamResource) Remove(ctx context.Context) error {
+	r.Failed = 0
+	r.Filtered = 0
+
+	var lastErr error
+	err := r.lister.ListPages(ctx, r.opts, func(page []resource.Resource) error {
+		items, err := r.nuke.streamItems(page, r.resourceType, r.owner, r.opts)
+		if err != nil {
+			return err
+		}
+
+		var removable []*queue.Item
+		for _, item := range items {
+			if item.State == queue.ItemStateFiltered {
+				r.Filtered++
+				continue
+			}
+
+			if reason := r.notScanned(item, r.Removed+len(removable)); reason != "" {
+				item.State = queue.ItemStateFiltered
+				item.Reason = reason
+				if !r.nuke.Parameters.Quiet {
+					item.Print()
+				}
+				r.Filtered++
+				continue
+			}
+
+			if err := r.nuke.beforeRemove(ctx, item); err != nil {
+				item.State = queue.ItemStateFailed
+				item.Reason = err.Error()
+				item.Print()
+				r.Failed++
+				lastErr = err
+				continue
+			}
+
+			removable = append(removable, item)
+		}
+
+		for i, err := range removeBatch(ctx, r.lister, r.opts, removable) {
+			item := removable[i]
+			if err != nil {
+				item.State = queue.ItemStateFailed
+				item.Reason = err.Error()
+				r.Failed++
+				lastErr = err
+			} else {
+				item.State = queue.ItemStateFinished
+				item.Reason = ""
+				r.Removed++
+				r.nuke.afterRemove(ctx, item)
+			}
+
+			item.Print()
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if r.Failed > 0 {
+		return fmt.Errorf("%d resources could not be removed, last error: %w", r.Failed, lastErr)
+	}
+
+	return nil
+}
+
+func (r *StreamResource) String() string {
+	return fmt.Sprintf("%d resources", r.Count)
+}
+
+func (r *StreamResource) Properties() types.Properties {
+	return types.NewProperties().
+		Set("Count", r.Count).
+		Set("Removable", r.Removable).
+		Set("Digest", r.Digest)
+}
+
+// summaryRow returns the outcome of the resources of the stream, the resources that were neither removed, failed nor
+// filtered were skipped.
+func (r *StreamResource) summaryRow(state queue.ItemState) SummaryRow {
+	row := SummaryRow{
+		Removed:  r.Removed,
+		Failed:   r.Failed,
+		Filtered: r.Filtered,
+	}
+
+	switch state {
+	case queue.ItemStateNew, queue.ItemStateNewDependency:
+		// Not removed, because it is a dry run or because the run stopped before it was removed
+		row.Filtered = r.Count - r.Removable
+		row.Skipped = r.Removable
+	case queue.ItemStateFiltered:
+		row.Filtered = r.Count
+	}
+
+	return row
+}
+
+// ScanCounts returns the number of resources found by the scan, the number of resources that would be removed and the
+// number of resources that are filtered. The resources of a stream are counted one by one.
+func ScanCounts(q *queue.Queue) (total, nukeable, filtered int) {
+	for _, item := range q.GetItems() {
+		if stream, ok := item.Resource.(*StreamResource); ok {
+			total += stream.Count
+			filtered += stream.Count - stream.Removable
+			if item.GetState() == queue.ItemStateFiltered {
+				filtered += stream.Removable
+			} else {
+				nukeable += stream.Removable
+			}
+			continue
+		}
+
+		total++
+		switch item.GetState() {
+		case queue.ItemStateNew, queue.ItemStateNewDependency:
+			nukeable++
+		case queue.ItemStateFiltered:
+			filtered++
+		}
+	}
+
+	return total, nukeable, filtered
+}
+
+// scanStream lists the resources of a stream lister page by page, filters and prints them, and returns the item that
+// represents them in the queue. No item is returned when there are no resources.
+func (n *Nuke) scanStream(
+	ctx context.Context, lister StreamLister, resourceType, owner string, opts interface{},
+) ([]*queue.Item, error) {
+	stream := &StreamResource{
+		nuke:         n,
+		lister:       lister,
+		resourceType: resourceType,
+		owner:        owner,
+		opts:         opts,
+		started:      time.Now(),
+	}
+
+	digest := sha256.New()
+	if err := lister.ListPages(ctx, opts, func(page []resource.Resource) error {
+		items, err := n.streamItems(page, resourceType, owner, opts)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			stream.Count++
+			if item.State != queue.ItemStateFiltered {
+				stream.Removable++
+				key := streamKey(item)
+				_, _ = digest.Write(key[:])
+			}
+
+			// If quiet and filtered, skip printing to screen
+			if n.Parameters.Quiet && item.State == queue.ItemStateFiltered {
+				continue
+			}
+
+			item.Print()
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if stream.Count == 0 {
+		return nil, nil
+	}
+
+	stream.Digest = hex.EncodeToString(digest.Sum(nil))
+
+	item := &queue.Item{
+		Resource: stream,
+		State:    queue.ItemStateNew,
+		Type:     resourceType,
+		Owner:    owner,
+		Opts:     opts,
+		Logger:   n.log.Logger,
+	}
+
+	if stream.Removable == 0 {
+		item.State = queue.ItemStateFiltered
+		item.Reason = "all resources are filtered"
+	}
+
+	return []*queue.Item{item}, nil
+}
+
+// notScanned returns why a resource that is not filtered was not found by the scan, or nothing when it may be removed.
+// A resource was not scanned when it was modified after the scan started, or when the removable resources of the scan
+// have all been removed or are about to be.
+func (r *StreamResource) notScanned(item *queue.Item, removing int) string {
+	if modifier, ok := item.Resource.(LastModifier); ok {
+		if modified := modifier.LastModified(); modified != nil && modified.After(r.started) {
+			return "modified after the scan"
+		}
+	}
+
+	if removing >= r.Removable {
+		return "more resources than the scan found"
+	}
+
+	return ""
+}
+
+// streamKey identifies a resource of a stream in its digest by its unique key, or by its name and properties when it
+// has none.
+func streamKey(item *queue.Item) [sha256.Size]byte {
+	if uniqueKey := unique.FromStruct(item.Resource); uniqueKey != nil {
+		return sha256.Sum256([]byte("unique:" + *uniqueKey))
+	}
+
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "%q\n", ItemName(item))
+
+	properties := ItemProperties(item)
+	keys := make([]string, 0, len(properties))
+	for key := range properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		_, _ = fmt.Fprintf(h, "%q=%q\n", key, properties[key])
+	}
+
+	var key [sha256.Size]byte
+	h.Sum(key[:0])
+
+	return key
+}
+
+// streamItems returns the queue items of a page of resources with their settings resolved and filtered.
+func (n *Nuke) streamItems(page []resource.Resource, resourceType, owner string, opts interface{}) ([]*queue.Item, error) {
+	items := make([]*queue.Item, 0, len(page))
+	for _, r := range page {
+		item := &queue.Item{
+			Resource: r,
+			State:    queue.ItemStateNew,
+			Type:     resourceType,
+			Owner:    owner,
+			Opts:     opts,
+			Logger:   n.log.Logger,
+		}
+
+		if itemHook, ok := r.(resource.QueueItemHook); ok {
+			itemHook.BeforeEnqueue(item)
+		}
+
+		if err := n.filterItem(item); err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
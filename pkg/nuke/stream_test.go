@@ -0,0 +1,354 @@
+package nuke
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ekristen/libnuke/pkg/filter"
+	libnuke "github.com/ekristen/libnuke/pkg/nuke"
+	"github.com/ekristen/libnuke/pkg/queue"
+	"github.com/ekristen/libnuke/pkg/registry"
+	"github.com/ekristen/libnuke/pkg/resource"
+	"github.com/ekristen/libnuke/pkg/scanner"
+	libsettings "github.com/ekristen/libnuke/pkg/settings"
+)
+
+const (
+	streamTestResourceType         = "NukeTestStream"
+	streamTestBatchResourceType    = "NukeTestStreamBatch"
+	streamTestModifiedResourceType = "NukeTestStreamModified"
+)
+
+// streamTestPages records the size of the pages removed by the batch remover.
+var streamTestPages = struct {
+	sync.Mutex
+	sizes []int
+}{}
+
+func init() {
+	registry.Register(&registry.Registration{
+		Name:     streamTestResourceType,
+		Scope:    Account,
+		Resource: &testResource{},
+		Lister:   &streamTestLister{},
+	})
+
+	registry.Register(&registry.Registration{
+		Name:     streamTestModifiedResourceType,
+		Scope:    Account,
+		Resource: &streamTestModifiedResource{},
+		Lister:   &streamTestModifiedLister{},
+	})
+
+	registry.Register(&registry.Registration{
+		Name:     streamTestBatchResourceType,
+		Scope:    Account,
+		Resource: &testResource{},
+		Lister:   &streamTestPageLister{},
+	})
+}
+
+// streamTestModified holds when the test resources were modified, the others were modified long ago.
+var streamTestModified = struct {
+	sync.Mutex
+	at map[string]time.Time
+}{at: map[string]time.Time{}}
+
+// streamTestLister lists the test resources in pages of two.
+type streamTestLister struct{}
+
+func (l *streamTestLister) List(ctx context.Context, o interface{}) ([]resource.Resource, error) {
+	return ListPages(ctx, l, o)
+}
+
+func (l *streamTestLister) ListPages(_ context.Context, o interface{}, page func([]resource.Resource) error) error {
+	opts := o.(*ListerOpts)
+
+	testResources.Lock()
+	names := append([]string{}, testResources.names[*opts.AccountID]...)
+	testResources.Unlock()
+
+	for i := 0; i < len(names); i += 2 {
+		var resources []resource.Resource
+		for _, name := range names[i:min(i+2, len(names))] {
+			resources = append(resources, &testResource{Name: name, Owner: *opts.AccountID})
+		}
+
+		if err := page(resources); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// streamTestPageLister removes the test resources a page at a time, the resource named stuck is never removed.
+type streamTestPageLister struct {
+	streamTestLister
+}
+
+func (l *streamTestPageLister) List(ctx context.Context, o interface{}) ([]resource.Resource, error) {
+	return ListPages(ctx, l, o)
+}
+
+func (l *streamTestPageLister) RemoveBatch(ctx context.Context, _ interface{}, page []resource.Resource) []error {
+	streamTestPages.Lock()
+	streamTestPages.sizes = append(streamTestPages.sizes, len(page))
+	streamTestPages.Unlock()
+
+	errs := make([]error, len(page))
+	for i, r := range page {
+		if r.(*testResource).Name == "stuck" {
+			errs[i] = errors.New("stuck")
+			continue
+		}
+
+		errs[i] = r.Remove(ctx)
+	}
+
+	return errs
+}
+
+// streamTestModifiedLister lists the test resources with the time they were modified.
+type streamTestModifiedLister struct {
+	streamTestLister
+}
+
+func (l *streamTestModifiedLister) List(ctx context.Context, o interface{}) ([]resource.Resource, error) {
+	return ListPages(ctx, l, o)
+}
+
+func (l *streamTestModifiedLister) ListPages(
+	ctx context.Context, o interface{}, page func([]resource.Resource) error,
+) error {
+	return l.streamTestLister.ListPages(ctx, o, func(resources []resource.Resource) error {
+		streamTestModified.Lock()
+		defer streamTestModified.Unlock()
+
+		modified := make([]resource.Resource, 0, len(resources))
+		for _, r := range resources {
+			m := &streamTestModifiedResource{testResource: *r.(*testResource)}
+			if at, ok := streamTestModified.at[m.Name]; ok {
+				m.modified = &at
+			}
+			modified = append(modified, m)
+		}
+
+		return page(modified)
+	})
+}
+
+type streamTestModifiedResource struct {
+	testResource
+
+	modified *time.Time
+}
+
+func (r *streamTestModifiedResource) LastModified() *time.Time {
+	return r.modified
+}
+
+func newStreamTestNuke(t *testing.T, resourceType string, owners ...string) *Nuke {
+	streamTestPages.Lock()
+	streamTestPages.sizes = nil
+	streamTestPages.Unlock()
+
+	n := New(&libnuke.Parameters{
+		ForceSleep: 3,
+		NoDryRun:   true,
+	}, filter.Filters{}, &libsettings.Settings{})
+	n.SetRunSleep(time.Millisecond)
+
+	for _, owner := range owners {
+		s, err := scanner.New(&scanner.Config{
+			Owner:         owner,
+			ResourceTypes: []string{resourceType},
+			Opts:          &ListerOpts{AccountID: &owner},
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, n.RegisterScanner(Account, s))
+	}
+
+	return n
+}
+
+func TestNuke_ScanStream(t *testing.T) {
+	setTestResources(map[string][]string{
+		"us-east-1": {"protected", "one", "two", "three"},
+	})
+
+	n := newStreamTestNuke(t, streamTestResourceType, "us-east-1")
+	n.Parameters.NoDryRun = false
+
+	assert.NoError(t, n.Run(context.TODO()))
+
+	assert.Equal(t, 1, n.Queue.Total())
+	stream, ok := n.Queue.GetItems()[0].Resource.(*StreamResource)
+	assert.True(t, ok)
+	assert.Equal(t, 4, stream.Count)
+	assert.Equal(t, 3, stream.Removable)
+
+	total, nukeable, filtered := ScanCounts(n.Queue)
+	assert.Equal(t, []int{4, 3, 1}, []int{total, nukeable, filtered})
+	assert.Equal(t, map[string]int{streamTestResourceType: 3}, RemovalCounts(n.Queue))
+	assert.Equal(t, SummaryRow{Region: "total", Filtered: 1, Skipped: 3}, NewSummary(n.Queue).Total)
+
+	testResources.Lock()
+	defer testResources.Unlock()
+	assert.Len(t, testResources.names["us-east-1"], 4)
+}
+
+func TestNuke_ScanStreamFiltered(t *testing.T) {
+	setTestResources(map[string][]string{
+		"us-east-1": {"protected"},
+	})
+
+	n := newStreamTestNuke(t, streamTestResourceType, "us-east-1")
+
+	assert.NoError(t, n.Run(context.TODO()))
+
+	assert.Equal(t, 1, n.Queue.Count(queue.ItemStateFiltered))
+	assert.Equal(t, SummaryRow{Region: "total", Filtered: 1}, NewSummary(n.Queue).Total)
+}
+
+func TestNuke_RunStream(t *testing.T) {
+	setTestResources(map[string][]string{
+		"us-east-1": {"protected", "one", "two", "three", "four", "five"},
+	})
+
+	n := newStreamTestNuke(t, streamTestResourceType, "us-east-1")
+
+	var handled []string
+	n.RegisterBeforeRemoveHandler(func(_ context.Context, item *queue.Item) error {
+		handled = append(handled, item.Resource.(*testResource).Name)
+		return nil
+	})
+
+	assert.NoError(t, n.Run(context.TODO()))
+
+	assert.Equal(t, 1, n.Queue.Count(queue.ItemStateFinished))
+	assert.Equal(t, []string{"one", "two", "three", "four", "five"}, handled)
+	assert.Equal(t, SummaryRow{Region: "total", Removed: 5, Filtered: 1}, NewSummary(n.Queue).Total)
+
+	testResources.Lock()
+	defer testResources.Unlock()
+	assert.Equal(t, []string{"protected"}, testResources.names["us-east-1"])
+}
+
+func TestNuke_RunStreamScanned(t *testing.T) {
+	setTestResources(map[string][]string{
+		"us-east-1": {"protected", "one", "two", "three"},
+	})
+
+	n := newStreamTestNuke(t, streamTestResourceType, "us-east-1")
+
+	// No more resources are removed than the scan found, even when they cannot tell when they were modified
+	n.RegisterRunEventHandler(func(_ context.Context, event RunEvent, _ *queue.Queue) {
+		if event != RunEventScanComplete {
+			return
+		}
+
+		testResources.Lock()
+		defer testResources.Unlock()
+		testResources.names["us-east-1"] = append(testResources.names["us-east-1"], "late")
+	})
+
+	assert.NoError(t, n.Run(context.TODO()))
+	assert.Equal(t, SummaryRow{Region: "total", Removed: 3, Filtered: 2}, NewSummary(n.Queue).Total)
+
+	testResources.Lock()
+	defer testResources.Unlock()
+	assert.Equal(t, []string{"protected", "late"}, testResources.names["us-east-1"])
+}
+
+func TestNuke_RunStreamModified(t *testing.T) {
+	setTestResources(map[string][]string{
+		"us-east-1": {"one", "two", "three"},
+	})
+
+	n := newStreamTestNuke(t, streamTestModifiedResourceType, "us-east-1")
+
+	// A resource that is modified after the scan started is not removed, even when it is listed first
+	n.RegisterRunEventHandler(func(_ context.Context, event RunEvent, _ *queue.Queue) {
+		if event != RunEventScanComplete {
+			return
+		}
+
+		streamTestModified.Lock()
+		streamTestModified.at["late"] = time.Now()
+		streamTestModified.Unlock()
+
+		testResources.Lock()
+		defer testResources.Unlock()
+		testResources.names["us-east-1"] = append([]string{"late"}, testResources.names["us-east-1"]...)
+	})
+
+	assert.NoError(t, n.Run(context.TODO()))
+	assert.Equal(t, SummaryRow{Region: "total", Removed: 3, Filtered: 1}, NewSummary(n.Queue).Total)
+
+	testResources.Lock()
+	defer testResources.Unlock()
+	assert.Equal(t, []string{"late"}, testResources.names["us-east-1"])
+}
+
+func TestNuke_ScanStreamDigest(t *testing.T) {
+	digest := func(names ...string) string {
+		setTestResources(map[string][]string{"us-east-1": names})
+
+		n := newStreamTestNuke(t, streamTestResourceType, "us-east-1")
+		n.Parameters.NoDryRun = false
+		assert.NoError(t, n.Run(context.TODO()))
+
+		properties := n.Queue.GetItems()[0].Resource.(*StreamResource).Properties()
+		assert.NotEmpty(t, properties.Get("Digest"))
+
+		return properties.Get("Digest")
+	}
+
+	// The digest covers the removable resources only
+	assert.Equal(t, digest("one", "two"), digest("protected", "one", "two"))
+	assert.NotEqual(t, digest("one", "two"), digest("one", "two", "three"))
+}
+
+func TestNuke_RunStreamPageRemover(t *testing.T) {
+	setTestResources(map[string][]string{
+		"us-east-1": {"one", "two", "three", "four", "five"},
+	})
+
+	n := newStreamTestNuke(t, streamTestBatchResourceType, "us-east-1")
+
+	assert.NoError(t, n.Run(context.TODO()))
+
+	streamTestPages.Lock()
+	assert.Equal(t, []int{2, 2, 1}, streamTestPages.sizes)
+	streamTestPages.Unlock()
+
+	testResources.Lock()
+	defer testResources.Unlock()
+	assert.Empty(t, testResources.names["us-east-1"])
+}
+
+func TestNuke_RunStreamFailed(t *testing.T) {
+	setTestResources(map[string][]string{
+		"us-east-1": {"stuck", "one", "two"},
+	})
+
+	n := newStreamTestNuke(t, streamTestBatchResourceType, "us-east-1")
+
+	err := n.Run(context.TODO())
+	var removalErr *RemovalError
+	assert.ErrorAs(t, err, &removalErr)
+
+	assert.Equal(t, 1, n.Queue.Count(queue.ItemStateFailed))
+	assert.Equal(t, "1 resources could not be removed, last error: stuck", n.Queue.GetItems()[0].GetReason())
+	assert.Equal(t, SummaryRow{Region: "total", Removed: 2, Failed: 1}, NewSummary(n.Queue).Total)
+
+	testResources.Lock()
+	defer testResources.Unlock()
+	assert.Equal(t, []string{"stuck"}, testResources.names["us-east-1"])
+}
@@ -0,0 +1,172 @@
+package nuke
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+)
+
+// SummaryRow is the number of resources of a resource type in a region by their outcome.
+type SummaryRow struct {
+	Region       string
+	ResourceType string
+	Removed      int
+	Failed       int
+	Filtered     int
+	Skipped      int
+}
+
+func (r *SummaryRow) add(state queue.ItemState) {
+	switch state {
+	case queue.ItemStateFinished:
+		r.Removed++
+	case queue.ItemStateFailed:
+		r.Failed++
+	case queue.ItemStateFiltered:
+		r.Filtered++
+	default:
+		// Everything else was not removed, because it is a dry run or because the run stopped before it was removed
+		r.Skipped++
+	}
+}
+
+func (r *SummaryRow) addRow(other *SummaryRow) {
+	r.Removed += other.Removed
+	r.Failed += other.Failed
+	r.Filtered += other.Filtered
+	r.Skipped += other.Skipped
+}
+
+func (r *SummaryRow) write(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%d\n",
+		r.Region, r.ResourceType, r.Removed, r.Failed, r.Filtered, r.Skipped)
+	return err
+}
+
+// ListFailure is a resource type that could not be listed in a region, its resources are missing from the run.
+type ListFailure struct {
+	Region       string
+	ResourceType string
+	Error        error
+}
+
+// DeferredResourceType is a resource type that was not part of the run, because the run happened outside all the
+// windows of its schedules.
+type DeferredResourceType struct {
+	ResourceType string
+	Schedules    []string
+}
+
+// Summary is the outcome of a run per region and resource type.
+type Summary struct {
+	Rows                  []*SummaryRow
+	Total                 SummaryRow
+	ListFailures          []*ListFailure
+	DeferredResourceTypes []*DeferredResourceType
+}
+
+// NewSummary returns the summary of the items in the queue, the rows are sorted by region and resource type.
+func NewSummary(q *queue.Queue) *Summary {
+	s := &Summary{Total: SummaryRow{Region: "total"}}
+	if q == nil {
+		return s
+	}
+
+	rows := map[string]*SummaryRow{}
+	for _, item := range q.GetItems() {
+		key := item.Owner + "\x00" + item.Type
+
+		row, ok := rows[key]
+		if !ok {
+			row = &SummaryRow{Region: item.Owner, ResourceType: item.Type}
+			rows[key] = row
+			s.Rows = append(s.Rows, row)
+		}
+
+		// The resources of a stream are counted one by one
+		if stream, ok := item.Resource.(*StreamResource); ok {
+			streamRow := stream.summaryRow(item.GetState())
+			row.addRow(&streamRow)
+			s.Total.addRow(&streamRow)
+			continue
+		}
+
+		row.add(item.GetState())
+		s.Total.add(item.GetState())
+	}
+
+	slices.SortFunc(s.Rows, func(a, b *SummaryRow) int {
+		if c := strings.Compare(a.Region, b.Region); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.ResourceType, b.ResourceType)
+	})
+
+	return s
+}
+
+// FailedResourceTypes returns the sorted resource types that have failed resources.
+func (s *Summary) FailedResourceTypes() []string {
+	var types []string
+	for _, row := range s.Rows {
+		if row.Failed > 0 && !slices.Contains(types, row.ResourceType) {
+			types = append(types, row.ResourceType)
+		}
+	}
+
+	slices.Sort(types)
+
+	return types
+}
+
+// Write writes the summary as a table.
+func (s *Summary) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	if _, err := fmt.Fprintln(tw, "REGION\tRESOURCE TYPE\tREMOVED\tFAILED\tFILTERED\tSKIPPED"); err != nil {
+		return err
+	}
+
+	for _, row := range s.Rows {
+		if err := row.write(tw); err != nil {
+			return err
+		}
+	}
+
+	if err := s.Total.write(tw); err != nil {
+		return err
+	}
+
+	if len(s.ListFailures) > 0 {
+		if _, err := fmt.Fprintln(tw, "\nREGION\tRESOURCE TYPE\tLISTING FAILED"); err != nil {
+			return err
+		}
+	}
+
+	for _, failure := range s.ListFailures {
+		// Only the first line, a panic of a lister includes the stack trace
+		message := strings.SplitN(failure.Error.Error(), "\n", 2)[0]
+		if _, err := fmt.Fprintf(tw, "%s\t%s\t%s\n", failure.Region, failure.ResourceType, message); err != nil {
+			return err
+		}
+	}
+
+	if len(s.DeferredResourceTypes) > 0 {
+		if _, err := fmt.Fprintln(tw, "\nRESOURCE TYPE\tDEFERRED BY SCHEDULES"); err != nil {
+			return err
+		}
+	}
+
+	for _, deferred := range s.DeferredResourceTypes {
+		if _, err := fmt.Fprintf(tw, "%s\t%s\n", deferred.ResourceType, strings.Join(deferred.Schedules, ", ")); err != nil {
+			return err
+		}
+	}
+
+	return tw.Flush()
+}
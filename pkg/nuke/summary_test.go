@@ -0,0 +1,99 @@
+package nuke
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+)
+
+func TestSummary(t *testing.T) {
+	q := queue.New()
+	for _, item := range []struct {
+		owner, resourceType string
+		state               queue.ItemState
+	}{
+		{"us-east-1", "S3Bucket", queue.ItemStateFinished},
+		{"us-east-1", "S3Bucket", queue.ItemStateFailed},
+		{"eu-west-1", "EC2Instance", queue.ItemStateFiltered},
+		{"eu-west-1", "EC2Instance", queue.ItemStateNew},
+		{"us-east-1", "IAMRole", queue.ItemStateFinished},
+		{"eu-west-1", "S3Bucket", queue.ItemStateFailed},
+		{"eu-west-1", "EC2Instance", queue.ItemStateWaiting},
+	} {
+		q.Items = append(q.Items, &queue.Item{Owner: item.owner, Type: item.resourceType, State: item.state})
+	}
+
+	summary := NewSummary(q)
+
+	assert.Equal(t, []*SummaryRow{
+		{Region: "eu-west-1", ResourceType: "EC2Instance", Filtered: 1, Skipped: 2},
+		{Region: "eu-west-1", ResourceType: "S3Bucket", Failed: 1},
+		{Region: "us-east-1", ResourceType: "IAMRole", Removed: 1},
+		{Region: "us-east-1", ResourceType: "S3Bucket", Removed: 1, Failed: 1},
+	}, summary.Rows)
+	assert.Equal(t, SummaryRow{Region: "total", Removed: 2, Failed: 2, Filtered: 1, Skipped: 2}, summary.Total)
+	assert.Equal(t, []string{"S3Bucket"}, summary.FailedResourceTypes())
+
+	var buf bytes.Buffer
+	assert.NoError(t, summary.Write(&buf))
+	assert.Equal(t, ""+
+		"REGION     RESOURCE TYPE  REMOVED  FAILED  FILTERED  SKIPPED\n"+
+		"eu-west-1  EC2Instance    0        0       1         2\n"+
+		"eu-west-1  S3Bucket       0        1       0         0\n"+
+		"us-east-1  IAMRole        1        0       0         0\n"+
+		"us-east-1  S3Bucket       1        1       0         0\n"+
+		"total                     2        2       1         2\n", buf.String())
+}
+
+func TestSummary_ListFailures(t *testing.T) {
+	summary := NewSummary(nil)
+	summary.ListFailures = []*ListFailure{
+		{Region: "eu-west-1", ResourceType: "EC2Instance", Error: errors.New("access denied")},
+		{Region: "us-east-1", ResourceType: "S3Bucket", Error: errors.New("lister panic\n\ngoroutine 1")},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, summary.Write(&buf))
+	assert.Equal(t, ""+
+		"REGION  RESOURCE TYPE  REMOVED  FAILED  FILTERED  SKIPPED\n"+
+		"total                  0        0       0         0\n"+
+		"\n"+
+		"REGION     RESOURCE TYPE  LISTING FAILED\n"+
+		"eu-west-1  EC2Instance    access denied\n"+
+		"us-east-1  S3Bucket       lister panic\n", buf.String())
+}
+
+func TestSummary_Empty(t *testing.T) {
+	summary := NewSummary(nil)
+
+	assert.Empty(t, summary.Rows)
+	assert.Empty(t, summary.FailedResourceTypes())
+}
+
+func TestNuke_SummaryDeferredResourceTypes(t *testing.T) {
+	n := newTestNuke(t, "us-east-1")
+	n.SetDeferredResourceTypes(map[string][]string{
+		"S3Bucket":    {"weekends"},
+		"EC2Instance": {"nights", "weekends"},
+	})
+
+	summary := n.Summary()
+	assert.Equal(t, []*DeferredResourceType{
+		{ResourceType: "EC2Instance", Schedules: []string{"nights", "weekends"}},
+		{ResourceType: "S3Bucket", Schedules: []string{"weekends"}},
+	}, summary.DeferredResourceTypes)
+
+	var buf bytes.Buffer
+	assert.NoError(t, summary.Write(&buf))
+	assert.Equal(t, ""+
+		"REGION  RESOURCE TYPE  REMOVED  FAILED  FILTERED  SKIPPED\n"+
+		"total                  0        0       0         0\n"+
+		"\n"+
+		"RESOURCE TYPE  DEFERRED BY SCHEDULES\n"+
+		"EC2Instance    nights, weekends\n"+
+		"S3Bucket       weekends\n", buf.String())
+}
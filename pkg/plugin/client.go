@@ -0,0 +1,168 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ekristen/libnuke/pkg/registry"
+
+	"github.com/ekristen/aws-nuke/v3/pkg/config"
+	"github.com/ekristen/aws-nuke/v3/pkg/nuke"
+)
+
+// exitTimeout is how long a plugin has to exit once its connection is closed.
+const exitTimeout = 5 * time.Second
+
+// Client is a running plugin.
+type Client struct {
+	Name          string
+	ResourceTypes []*ResourceType
+
+	cmd    *exec.Cmd
+	client *rpc.Client
+}
+
+// Start starts the plugin binary and performs the handshake. The plugin runs until the client is closed.
+func Start(ctx context.Context, p *config.Plugin) (*Client, error) {
+	path := os.ExpandEnv(p.Path)
+
+	name := p.Name
+	if name == "" {
+		name = filepath.Base(path)
+	}
+
+	cmd := exec.Command(path, p.Args...) //nolint:gosec
+	cmd.Env = append(os.Environ(), fmt.Sprintf("%s=%s", MagicCookieKey, MagicCookieValue))
+	cmd.Stderr = os.Stderr
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("unable to start plugin %s: %w", name, err)
+	}
+
+	c := NewClient(name, &stdioConn{Reader: stdout, Writer: stdin, closers: []io.Closer{stdin}})
+	c.cmd = cmd
+
+	if err := c.describe(ctx); err != nil {
+		_ = c.Close()
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// NewClient returns a client for a plugin that is served on the connection. The handshake is not performed, it is
+// meant for plugins that are not started by Start, such as in tests.
+func NewClient(name string, conn io.ReadWriteCloser) *Client {
+	return &Client{
+		Name:   name,
+		client: rpc.NewClientWithCodec(jsonrpc.NewClientCodec(conn)),
+	}
+}
+
+// describe performs the handshake, it fails when the plugin serves another version of the protocol.
+func (c *Client) describe(ctx context.Context) error {
+	resp := &DescribeResponse{}
+	if err := c.call(ctx, "Describe", &DescribeRequest{ProtocolVersion: ProtocolVersion}, resp); err != nil {
+		return fmt.Errorf("handshake with plugin %s failed: %w", c.Name, err)
+	}
+
+	if resp.ProtocolVersion != ProtocolVersion {
+		return fmt.Errorf("plugin %s serves protocol version %d, version %d is required",
+			c.Name, resp.ProtocolVersion, ProtocolVersion)
+	}
+
+	c.ResourceTypes = resp.ResourceTypes
+
+	return nil
+}
+
+// call calls a method of the plugin and waits for the response or until the context is done.
+func (c *Client) call(ctx context.Context, method string, req, resp interface{}) error {
+	call := c.client.Go(fmt.Sprintf("%s.%s", serviceName, method), req, resp, make(chan *rpc.Call, 1))
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-call.Done:
+		return call.Error
+	}
+}
+
+// Register registers the resource types of the plugin with the registry. They use the SDK v2 config of the region to
+// retrieve the credentials that are passed to the plugin.
+func (c *Client) Register() error {
+	for _, resourceType := range c.ResourceTypes {
+		if resourceType == nil || resourceType.Name == "" {
+			return fmt.Errorf("plugin %s has a resource type without a name", c.Name)
+		}
+
+		if registry.GetRegistration(resourceType.Name) != nil {
+			return fmt.Errorf("plugin %s provides the resource type %s, which already exists",
+				c.Name, resourceType.Name)
+		}
+
+		registry.Register(&registry.Registration{
+			Name:      resourceType.Name,
+			Scope:     nuke.Account,
+			Resource:  &remoteResource{},
+			Lister:    &lister{client: c, resourceType: resourceType.Name},
+			Settings:  resourceType.Settings,
+			DependsOn: resourceType.DependsOn,
+		})
+
+		nuke.RegisterSDKv2(resourceType.Name)
+
+		logrus.
+			WithField("plugin", c.Name).
+			WithField("type", resourceType.Name).
+			Debug("registered plugin resource type")
+	}
+
+	return nil
+}
+
+// Close closes the connection to the plugin and waits for it to exit. A plugin is expected to exit once its standard
+// input is closed, it is killed when it does not exit within the exit timeout.
+func (c *Client) Close() error {
+	err := c.client.Close()
+	if c.cmd == nil {
+		return err
+	}
+
+	exited := make(chan error, 1)
+	go func() {
+		exited <- c.cmd.Wait()
+	}()
+
+	select {
+	case waitErr := <-exited:
+		if waitErr != nil {
+			return fmt.Errorf("plugin %s exited with an error: %w", c.Name, waitErr)
+		}
+	case <-time.After(exitTimeout):
+		_ = c.cmd.Process.Kill()
+		<-exited
+		return fmt.Errorf("plugin %s did not exit and was killed", c.Name)
+	}
+
+	return nil
+}
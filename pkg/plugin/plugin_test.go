@@ -0,0 +1,150 @@
+package plugin
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+
+	"github.com/ekristen/libnuke/pkg/registry"
+	"github.com/ekristen/libnuke/pkg/resource"
+	libsettings "github.com/ekristen/libnuke/pkg/settings"
+
+	"github.com/ekristen/aws-nuke/v3/pkg/config"
+	"github.com/ekristen/aws-nuke/v3/pkg/nuke"
+)
+
+// testPluginPath is the path of the test plugin, it is built once for all tests.
+var testPluginPath string
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "aws-nuke-plugin")
+	if err != nil {
+		panic(err)
+	}
+
+	testPluginPath = filepath.Join(dir, "testplugin")
+
+	build := exec.Command("go", "build", "-o", testPluginPath, "./testdata/testplugin")
+	build.Stdout = os.Stdout
+	build.Stderr = os.Stderr
+	if err := build.Run(); err != nil {
+		panic(err)
+	}
+
+	code := m.Run()
+
+	_ = os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func newTestOpts(region string) *nuke.ListerOpts {
+	return &nuke.ListerOpts{
+		Region:    &nuke.Region{Name: region},
+		AccountID: aws.String("000000000000"),
+		Config: &aws.Config{
+			Region: region,
+			Credentials: aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+				return aws.Credentials{AccessKeyID: "AKIDTEST", SecretAccessKey: "secret"}, nil
+			}),
+		},
+	}
+}
+
+func startTestPlugin(t *testing.T) *Client {
+	client, err := Start(context.TODO(), &config.Plugin{Path: testPluginPath})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, client.Close())
+	})
+
+	return client
+}
+
+func TestStart(t *testing.T) {
+	client := startTestPlugin(t)
+
+	assert.Equal(t, "testplugin", client.Name)
+	assert.Equal(t, []*ResourceType{{Name: "PluginTestResource", Settings: []string{"Fail"}}}, client.ResourceTypes)
+}
+
+func TestStart_NotFound(t *testing.T) {
+	_, err := Start(context.TODO(), &config.Plugin{Name: "missing", Path: filepath.Join(t.TempDir(), "missing")})
+	assert.ErrorContains(t, err, "unable to start plugin missing")
+}
+
+func TestServe_WithoutMagicCookie(t *testing.T) {
+	out, err := exec.Command(testPluginPath).CombinedOutput()
+	assert.Error(t, err)
+	assert.Contains(t, string(out), "this binary is an aws-nuke plugin")
+}
+
+func TestService_DescribeProtocolVersion(t *testing.T) {
+	s := &service{}
+	err := s.Describe(&DescribeRequest{ProtocolVersion: ProtocolVersion + 1}, &DescribeResponse{})
+	assert.EqualError(t, err, "unsupported protocol version 2, the plugin serves version 1")
+}
+
+func TestClient_Register(t *testing.T) {
+	client := startTestPlugin(t)
+
+	require.NoError(t, client.Register())
+	assert.NotNil(t, registry.GetLister("PluginTestResource"))
+	assert.True(t, nuke.IsSDKv2("PluginTestResource"))
+
+	assert.EqualError(t, client.Register(),
+		"plugin testplugin provides the resource type PluginTestResource, which already exists")
+}
+
+func TestLister_ListAndRemove(t *testing.T) {
+	client := startTestPlugin(t)
+	l := &lister{client: client, resourceType: "PluginTestResource"}
+
+	resources, err := l.List(context.TODO(), newTestOpts("us-east-1"))
+	require.NoError(t, err)
+	require.Len(t, resources, 3)
+
+	names := make([]string, len(resources))
+	for i, r := range resources {
+		names[i] = r.(resource.LegacyStringer).String()
+	}
+	assert.Equal(t, []string{"one", "two", "protected"}, names)
+
+	properties := resources[0].(resource.PropertyGetter).Properties()
+	assert.Equal(t, "us-east-1", properties.Get("Region"))
+	assert.Equal(t, "000000000000", properties.Get("AccountID"))
+	assert.Equal(t, "AKIDTEST", properties.Get("AccessKeyID"))
+
+	assert.NoError(t, resources[0].(resource.Filter).Filter())
+	assert.EqualError(t, resources[2].(resource.Filter).Filter(), "protected by the plugin")
+
+	// The setting is passed on to the plugin with the removal
+	failing := resources[1].(*remoteResource)
+	failing.Settings(&libsettings.Setting{"Fail": true})
+	assert.ErrorContains(t, failing.Remove(context.TODO()), "unable to remove us-east-1/two")
+
+	require.NoError(t, resources[0].Remove(context.TODO()))
+
+	resources, err = l.List(context.TODO(), newTestOpts("us-east-1"))
+	require.NoError(t, err)
+	assert.Len(t, resources, 2)
+
+	// The resources of other regions are not affected
+	resources, err = l.List(context.TODO(), newTestOpts("eu-west-1"))
+	require.NoError(t, err)
+	assert.Len(t, resources, 3)
+}
+
+func TestLister_ListWithoutCredentials(t *testing.T) {
+	client := startTestPlugin(t)
+	l := &lister{client: client, resourceType: "PluginTestResource"}
+
+	_, err := l.List(context.TODO(), &nuke.ListerOpts{Region: &nuke.Region{Name: "us-east-1"}})
+	assert.EqualError(t, err, "the lister options do not have credentials")
+}
@@ -0,0 +1,97 @@
+// Package plugin provides out-of-tree resource types. A plugin is an external binary that is started by aws-nuke and
+// serves JSON-RPC on its standard input and output. It describes the resource types it provides, lists their
+// resources and removes them with the region and the credentials of the run. Plugins are written with Serve, aws-nuke
+// starts them with Start and registers their resource types like any other.
+package plugin
+
+// ProtocolVersion is the version of the plugin protocol. A plugin must serve the same version as aws-nuke, it is
+// incremented whenever the protocol changes in a way that is not compatible.
+const ProtocolVersion = 1
+
+const (
+	// MagicCookieKey and MagicCookieValue are set in the environment of a plugin that is started by aws-nuke. They are
+	// not a security measure, they only keep a plugin binary from waiting for requests when it is run by hand.
+	MagicCookieKey   = "AWS_NUKE_PLUGIN_MAGIC_COOKIE"
+	MagicCookieValue = "d6d5b8e4-2c3a-4f0e-9a57-1f1e0c6b7a90"
+)
+
+// serviceName is the name of the RPC service of a plugin.
+const serviceName = "Plugin"
+
+// ResourceType describes a resource type of a plugin.
+type ResourceType struct {
+	// Name is the name of the resource type, it must not collide with a built-in resource type or the resource types
+	// of other plugins.
+	Name string `json:"name"`
+
+	// DependsOn is a list of resource types that have to be removed before the resources of this type.
+	DependsOn []string `json:"dependsOn,omitempty"`
+
+	// Settings is a list of the settings the resource type supports, they are passed to the plugin with every removal.
+	Settings []string `json:"settings,omitempty"`
+}
+
+// DescribeRequest is the request of the handshake.
+type DescribeRequest struct {
+	ProtocolVersion int `json:"protocolVersion"`
+}
+
+// DescribeResponse is the response of the handshake, it lists the resource types of the plugin.
+type DescribeResponse struct {
+	ProtocolVersion int             `json:"protocolVersion"`
+	ResourceTypes   []*ResourceType `json:"resourceTypes"`
+}
+
+// Credentials are the AWS credentials of the run, they are retrieved again for every request so they are never
+// expired.
+type Credentials struct {
+	AccessKeyID     string `json:"accessKeyId"`
+	SecretAccessKey string `json:"secretAccessKey"`
+	SessionToken    string `json:"sessionToken,omitempty"`
+}
+
+// Context is the region and the credentials a request is made with. The region is "global" for the resources that
+// are not regional.
+type Context struct {
+	ResourceType string      `json:"resourceType"`
+	Region       string      `json:"region"`
+	AccountID    string      `json:"accountId,omitempty"`
+	Endpoint     string      `json:"endpoint,omitempty"`
+	Credentials  Credentials `json:"credentials"`
+}
+
+// Resource is a single resource of a plugin.
+type Resource struct {
+	// ID identifies the resource, it is passed back to the plugin when the resource is removed.
+	ID string `json:"id"`
+
+	// Name is how the resource is printed, the ID is printed when it is not set.
+	Name string `json:"name,omitempty"`
+
+	// Properties are the properties of the resource that filters match against.
+	Properties map[string]string `json:"properties,omitempty"`
+
+	// FilterReason filters the resource when it is set, for example for resources that can never be removed.
+	FilterReason string `json:"filterReason,omitempty"`
+}
+
+// ListRequest lists the resources of a resource type in a region.
+type ListRequest struct {
+	Context
+}
+
+// ListResponse is the response of a list request.
+type ListResponse struct {
+	Resources []*Resource `json:"resources"`
+}
+
+// RemoveRequest removes a single resource that was listed before.
+type RemoveRequest struct {
+	Context
+
+	Resource *Resource              `json:"resource"`
+	Settings map[string]interface{} `json:"settings,omitempty"`
+}
+
+// RemoveResponse is the response of a remove request, the resource was removed when the request did not fail.
+type RemoveResponse struct{}
@@ -0,0 +1,136 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+
+	"github.com/ekristen/libnuke/pkg/resource"
+	libsettings "github.com/ekristen/libnuke/pkg/settings"
+	"github.com/ekristen/libnuke/pkg/types"
+
+	"github.com/ekristen/aws-nuke/v3/pkg/nuke"
+)
+
+// lister lists the resources of a resource type of a plugin.
+type lister struct {
+	client       *Client
+	resourceType string
+}
+
+func (l *lister) List(ctx context.Context, o interface{}) ([]resource.Resource, error) {
+	opts := o.(*nuke.ListerOpts)
+
+	rctx, err := newContext(ctx, l.resourceType, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &ListResponse{}
+	if err := l.client.call(ctx, "List", &ListRequest{Context: *rctx}, resp); err != nil {
+		return nil, err
+	}
+
+	resources := make([]resource.Resource, 0, len(resp.Resources))
+	for _, r := range resp.Resources {
+		if r == nil {
+			continue
+		}
+
+		resources = append(resources, &remoteResource{
+			client:       l.client,
+			opts:         opts,
+			resourceType: l.resourceType,
+			resource:     r,
+		})
+	}
+
+	return resources, nil
+}
+
+// newContext returns the context of a request with the region of the options and the credentials of their config.
+func newContext(ctx context.Context, resourceType string, opts *nuke.ListerOpts) (*Context, error) {
+	if opts.Config == nil || opts.Config.Credentials == nil {
+		return nil, errors.New("the lister options do not have credentials")
+	}
+
+	creds, err := opts.Config.Credentials.Retrieve(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve the credentials for the plugin: %w", err)
+	}
+
+	rctx := &Context{
+		ResourceType: resourceType,
+		AccountID:    aws.ToString(opts.AccountID),
+		Endpoint:     aws.ToString(opts.Config.BaseEndpoint),
+		Credentials: Credentials{
+			AccessKeyID:     creds.AccessKeyID,
+			SecretAccessKey: creds.SecretAccessKey,
+			SessionToken:    creds.SessionToken,
+		},
+	}
+
+	if opts.Region != nil {
+		rctx.Region = opts.Region.Name
+	}
+
+	return rctx, nil
+}
+
+// remoteResource is a resource that was listed by a plugin, it is removed by the plugin as well.
+type remoteResource struct {
+	client       *Client
+	opts         *nuke.ListerOpts
+	resourceType string
+	settings     *libsettings.Setting
+	resource     *Resource
+}
+
+func (r *remoteResource) Remove(ctx context.Context) error {
+	rctx, err := newContext(ctx, r.resourceType, r.opts)
+	if err != nil {
+		return err
+	}
+
+	req := &RemoveRequest{
+		Context:  *rctx,
+		Resource: r.resource,
+	}
+
+	if r.settings != nil {
+		req.Settings = *r.settings
+	}
+
+	return r.client.call(ctx, "Remove", req, &RemoveResponse{})
+}
+
+func (r *remoteResource) Settings(setting *libsettings.Setting) {
+	r.settings = setting
+}
+
+func (r *remoteResource) Filter() error {
+	if r.resource.FilterReason != "" {
+		return errors.New(r.resource.FilterReason)
+	}
+
+	return nil
+}
+
+func (r *remoteResource) Properties() types.Properties {
+	properties := types.NewProperties()
+	for key, value := range r.resource.Properties {
+		properties.Set(key, value)
+	}
+
+	return properties
+}
+
+func (r *remoteResource) String() string {
+	if r.resource.Name != "" {
+		return r.resource.Name
+	}
+
+	return r.resource.ID
+}
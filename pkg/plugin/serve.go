@@ -0,0 +1,100 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"os"
+)
+
+// Plugin is implemented by the plugin binaries. The methods may be called concurrently, for different regions and
+// resource types.
+type Plugin interface {
+	// ResourceTypes returns the resource types the plugin provides.
+	ResourceTypes() []*ResourceType
+
+	// List returns the resources of a resource type in a region.
+	List(ctx context.Context, req *ListRequest) ([]*Resource, error)
+
+	// Remove removes a resource that was returned by List.
+	Remove(ctx context.Context, req *RemoveRequest) error
+}
+
+// Serve serves the plugin on the standard input and output until aws-nuke closes them. Anything the plugin logs must
+// be written to the standard error, aws-nuke passes it on to its own. It refuses to serve when the plugin was not
+// started by aws-nuke.
+func Serve(p Plugin) error {
+	if os.Getenv(MagicCookieKey) != MagicCookieValue {
+		return errors.New("this binary is an aws-nuke plugin, it is started by aws-nuke and cannot be run directly")
+	}
+
+	return ServeConn(p, &stdioConn{Reader: os.Stdin, Writer: os.Stdout, closers: []io.Closer{os.Stdin, os.Stdout}})
+}
+
+// ServeConn serves the plugin on a connection until it is closed.
+func ServeConn(p Plugin, conn io.ReadWriteCloser) error {
+	server := rpc.NewServer()
+	if err := server.RegisterName(serviceName, &service{plugin: p}); err != nil {
+		return err
+	}
+
+	server.ServeCodec(jsonrpc.NewServerCodec(conn))
+
+	return nil
+}
+
+// service exposes a plugin as an RPC service.
+type service struct {
+	plugin Plugin
+}
+
+// Describe is the handshake, it refuses a different protocol version.
+func (s *service) Describe(req *DescribeRequest, resp *DescribeResponse) error {
+	if req.ProtocolVersion != ProtocolVersion {
+		return fmt.Errorf("unsupported protocol version %d, the plugin serves version %d",
+			req.ProtocolVersion, ProtocolVersion)
+	}
+
+	resp.ProtocolVersion = ProtocolVersion
+	resp.ResourceTypes = s.plugin.ResourceTypes()
+
+	return nil
+}
+
+// List lists the resources of a resource type.
+func (s *service) List(req *ListRequest, resp *ListResponse) error {
+	resources, err := s.plugin.List(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	resp.Resources = resources
+
+	return nil
+}
+
+// Remove removes a resource.
+func (s *service) Remove(req *RemoveRequest, _ *RemoveResponse) error {
+	return s.plugin.Remove(context.Background(), req)
+}
+
+// stdioConn joins the reading and the writing end of a connection, such as the standard input and output.
+type stdioConn struct {
+	io.Reader
+	io.Writer
+
+	closers []io.Closer
+}
+
+// Close closes both ends of the connection.
+func (c *stdioConn) Close() error {
+	var errs []error
+	for _, closer := range c.closers {
+		errs = append(errs, closer.Close())
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,80 @@
+// Command testplugin is the plugin the plugin tests are run against. It provides the resource type
+// PluginTestResource with the resources one, two and protected in every region, protected is filtered. The resources
+// are kept in memory, so they are removed for as long as the plugin runs.
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/ekristen/aws-nuke/v3/pkg/plugin"
+)
+
+type testPlugin struct {
+	mu      sync.Mutex
+	removed map[string]bool
+}
+
+func (p *testPlugin) ResourceTypes() []*plugin.ResourceType {
+	return []*plugin.ResourceType{
+		{Name: "PluginTestResource", Settings: []string{"Fail"}},
+	}
+}
+
+func (p *testPlugin) List(_ context.Context, req *plugin.ListRequest) ([]*plugin.Resource, error) {
+	if req.Credentials.AccessKeyID == "" {
+		return nil, fmt.Errorf("no credentials")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var resources []*plugin.Resource
+	for _, name := range []string{"one", "two", "protected"} {
+		id := fmt.Sprintf("%s/%s", req.Region, name)
+		if p.removed[id] {
+			continue
+		}
+
+		r := &plugin.Resource{
+			ID:   id,
+			Name: name,
+			Properties: map[string]string{
+				"Name":        name,
+				"Region":      req.Region,
+				"AccountID":   req.AccountID,
+				"AccessKeyID": req.Credentials.AccessKeyID,
+			},
+		}
+
+		if name == "protected" {
+			r.FilterReason = "protected by the plugin"
+		}
+
+		resources = append(resources, r)
+	}
+
+	return resources, nil
+}
+
+func (p *testPlugin) Remove(_ context.Context, req *plugin.RemoveRequest) error {
+	if fail, ok := req.Settings["Fail"].(bool); ok && fail {
+		return fmt.Errorf("unable to remove %s", req.Resource.ID)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.removed[req.Resource.ID] = true
+
+	return nil
+}
+
+func main() {
+	if err := plugin.Serve(&testPlugin{removed: make(map[string]bool)}); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
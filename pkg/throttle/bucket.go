@@ -0,0 +1,122 @@
+package throttle
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	// ThrottledRate is the rate a service without a limit is lowered to the first time it is throttled.
+	ThrottledRate = 10.0
+
+	// MinRate is the lowest rate the adaptive limit lowers a service to.
+	MinRate = 0.5
+
+	// RecoveredRate is the rate at which a service without a limit is no longer limited after it recovered.
+	RecoveredRate = 100.0
+
+	// backoff is the factor the rate is lowered by when a request is throttled.
+	backoff = 0.5
+
+	// recovery is the factor the rate is raised by when a request succeeds.
+	recovery = 1.02
+
+	// defaultBurst is the burst of a service without a limit once it has been throttled.
+	defaultBurst = 10
+)
+
+// bucket is an adaptive token bucket for a service in a region. A rate of zero means the requests are not limited.
+type bucket struct {
+	mu sync.Mutex
+
+	max    float64 // max is the configured rate, zero when there is none
+	rate   float64 // rate is the current rate, it is lowered when requests are throttled
+	burst  float64
+	tokens float64
+	last   time.Time
+
+	requests  int
+	throttles int
+	waited    time.Duration
+}
+
+func newBucket(rate float64, burst int) *bucket {
+	b := &bucket{
+		max:   rate,
+		rate:  rate,
+		burst: float64(burst),
+	}
+
+	if b.burst <= 0 {
+		b.burst = math.Max(1, math.Ceil(rate))
+	}
+
+	b.tokens = b.burst
+
+	return b
+}
+
+// reserve takes a token from the bucket and returns how long to wait until it is available.
+func (b *bucket) reserve(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.requests++
+
+	if b.rate == 0 {
+		return 0
+	}
+
+	if !b.last.IsZero() {
+		b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	}
+	b.last = now
+
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+
+	wait := time.Duration(-b.tokens / b.rate * float64(time.Second))
+	b.waited += wait
+
+	return wait
+}
+
+// throttled lowers the rate after a request was throttled.
+func (b *bucket) throttled() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.throttles++
+
+	if b.rate == 0 {
+		b.rate = ThrottledRate
+		b.burst = defaultBurst
+	} else {
+		b.rate = math.Max(MinRate, b.rate*backoff)
+	}
+
+	// The tokens that were left are dropped, so the next requests are spread out at the lower rate
+	b.tokens = math.Min(b.tokens, 0)
+}
+
+// succeeded raises a lowered rate back towards the configured rate after a request succeeded.
+func (b *bucket) succeeded() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.rate == 0 || b.rate == b.max {
+		return
+	}
+
+	b.rate *= recovery
+
+	switch {
+	case b.max > 0:
+		b.rate = math.Min(b.rate, b.max)
+	case b.rate >= RecoveredRate:
+		b.rate = 0
+	}
+}
@@ -0,0 +1,98 @@
+package throttle
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/aws/aws-sdk-go/aws/request" //nolint:staticcheck
+	"github.com/aws/aws-sdk-go/aws/session" //nolint:staticcheck
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+// InstrumentSession limits the requests of an SDK v1 session. Every attempt, including retries, waits for the limiter.
+func (l *Limiter) InstrumentSession(sess *session.Session) {
+	sess.Handlers.Sign.PushFrontNamed(request.NamedHandler{
+		Name: "aws-nuke.throttle.Wait",
+		Fn: func(r *request.Request) {
+			if err := l.Wait(r.Context(), serviceIDV1(r), r.Operation.Name, aws.ToString(r.Config.Region)); err != nil {
+				r.Error = err
+			}
+		},
+	})
+
+	sess.Handlers.CompleteAttempt.PushBackNamed(request.NamedHandler{
+		Name: "aws-nuke.throttle.Done",
+		Fn: func(r *request.Request) {
+			throttled := r.Error != nil && (request.IsErrorThrottle(r.Error) ||
+				(r.HTTPResponse != nil && r.HTTPResponse.StatusCode == http.StatusTooManyRequests))
+			l.Done(serviceIDV1(r), r.Operation.Name, aws.ToString(r.Config.Region), throttled)
+		},
+	})
+}
+
+func serviceIDV1(r *request.Request) string {
+	if r.ClientInfo.ServiceID != "" {
+		return r.ClientInfo.ServiceID
+	}
+
+	return r.ClientInfo.ServiceName
+}
+
+// APIOption returns the API option that limits the requests of an SDK v2 config. It is added after the retry
+// middleware, so every attempt, including retries, waits for the limiter.
+func (l *Limiter) APIOption() func(*middleware.Stack) error {
+	return func(stack *middleware.Stack) error {
+		mw := &limitMiddleware{limiter: l}
+		if err := stack.Finalize.Insert(mw, (&retry.Attempt{}).ID(), middleware.After); err != nil {
+			return stack.Finalize.Add(mw, middleware.After)
+		}
+
+		return nil
+	}
+}
+
+type limitMiddleware struct {
+	limiter *Limiter
+}
+
+func (*limitMiddleware) ID() string {
+	return "aws-nuke::throttle"
+}
+
+func (m *limitMiddleware) HandleFinalize(
+	ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler,
+) (
+	middleware.FinalizeOutput, middleware.Metadata, error,
+) {
+	service := awsmiddleware.GetServiceID(ctx)
+	operation := awsmiddleware.GetOperationName(ctx)
+	region := awsmiddleware.GetRegion(ctx)
+
+	if err := m.limiter.Wait(ctx, service, operation, region); err != nil {
+		return middleware.FinalizeOutput{}, middleware.Metadata{}, err
+	}
+
+	out, md, err := next.HandleFinalize(ctx, in)
+	m.limiter.Done(service, operation, region, isThrottleV2(err))
+
+	return out, md, err
+}
+
+func isThrottleV2(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if retry.IsErrorThrottles(retry.DefaultThrottles).IsErrorThrottle(err) == aws.TrueTernary {
+		return true
+	}
+
+	var respErr *smithyhttp.ResponseError
+	return errors.As(err, &respErr) && respErr.HTTPStatusCode() == http.StatusTooManyRequests
+}
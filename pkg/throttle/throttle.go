@@ -0,0 +1,181 @@
+// Package throttle limits the rate of requests to AWS per service and region. The limits are configured, and lowered
+// adaptively when AWS throttles requests, they are shared by the SDK v1 sessions and the SDK v2 configs.
+package throttle
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ekristen/aws-nuke/v3/pkg/config"
+)
+
+// AccountRegion is the region of the stats of the limits that are shared by all regions of the account.
+const AccountRegion = "all"
+
+// Limiter holds the buckets of all services and regions. A limiter is used for a single account.
+type Limiter struct {
+	mu       sync.Mutex
+	limits   *config.RateLimits
+	buckets  map[string]*bucket
+	requests map[string]*bucket
+
+	now func() time.Time
+}
+
+// New returns a limiter for the rate limits, without rate limits only the adaptive limits are used.
+func New(limits *config.RateLimits) *Limiter {
+	return &Limiter{
+		limits:   limits,
+		buckets:  map[string]*bucket{},
+		requests: map[string]*bucket{},
+		now:      time.Now,
+	}
+}
+
+// bucket returns the bucket of the operation when the operation has a limit of its own, or the bucket of the service.
+// The bucket is looked up once per service, operation and region, later requests reuse it.
+func (l *Limiter) bucket(serviceID, operation, region string) *bucket {
+	request := serviceID + ":" + operation + "/" + region
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if b, ok := l.requests[request]; ok {
+		return b
+	}
+
+	serviceID = config.NormalizeServiceID(serviceID)
+	name := serviceID
+
+	limit := l.limits.GetOperation(serviceID, operation)
+	if limit != nil {
+		name = serviceID + ":" + operation
+	} else {
+		limit = l.limits.Get(serviceID)
+	}
+
+	if limit != nil && limit.Account {
+		region = AccountRegion
+	}
+
+	key := name + "/" + region
+	b, ok := l.buckets[key]
+	if !ok {
+		if limit != nil {
+			b = newBucket(limit.Rate, limit.Burst)
+		} else {
+			b = newBucket(0, 0)
+		}
+		l.buckets[key] = b
+	}
+
+	l.requests[request] = b
+
+	return b
+}
+
+// Wait blocks until a request to the operation of the service in the region is allowed, or the context is done.
+func (l *Limiter) Wait(ctx context.Context, serviceID, operation, region string) error {
+	wait := l.bucket(serviceID, operation, region).reserve(l.now())
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Done records the outcome of a request to the operation of the service in the region.
+func (l *Limiter) Done(serviceID, operation, region string, throttled bool) {
+	b := l.bucket(serviceID, operation, region)
+	if !throttled {
+		b.succeeded()
+		return
+	}
+
+	b.throttled()
+	logrus.Debugf("request to %s in %s was throttled, lowering the rate", serviceID, region)
+}
+
+// Stat is the number of requests to a service in a region and how often they were throttled.
+type Stat struct {
+	Service   string
+	Region    string
+	Requests  int
+	Throttles int
+	Waited    time.Duration
+}
+
+// Stats returns the stats of every service and region, sorted by service and region.
+func (l *Limiter) Stats() []Stat {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	stats := make([]Stat, 0, len(l.buckets))
+	for key, b := range l.buckets {
+		service, region, _ := strings.Cut(key, "/")
+
+		b.mu.Lock()
+		stats = append(stats, Stat{
+			Service:   service,
+			Region:    region,
+			Requests:  b.requests,
+			Throttles: b.throttles,
+			Waited:    b.waited,
+		})
+		b.mu.Unlock()
+	}
+
+	slices.SortFunc(stats, func(a, b Stat) int {
+		if c := strings.Compare(a.Service, b.Service); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Region, b.Region)
+	})
+
+	return stats
+}
+
+// WriteSummary writes the services that were throttled or waited for the rate limits as a table. Nothing is written
+// when no request was throttled or delayed.
+func (l *Limiter) WriteSummary(w io.Writer) error {
+	var stats []Stat
+	for _, stat := range l.Stats() {
+		if stat.Throttles > 0 || stat.Waited > 0 {
+			stats = append(stats, stat)
+		}
+	}
+
+	if len(stats) == 0 {
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(tw, "SERVICE\tREGION\tREQUESTS\tTHROTTLED\tWAITED"); err != nil {
+		return err
+	}
+
+	for _, stat := range stats {
+		if _, err := fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%s\n",
+			stat.Service, stat.Region, stat.Requests, stat.Throttles, stat.Waited.Round(time.Millisecond)); err != nil {
+			return err
+		}
+	}
+
+	return tw.Flush()
+}
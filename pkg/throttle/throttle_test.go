@@ -0,0 +1,216 @@
+package throttle
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	awsv1 "github.com/aws/aws-sdk-go/aws"                     //nolint:staticcheck
+	credentialsv1 "github.com/aws/aws-sdk-go/aws/credentials" //nolint:staticcheck
+	"github.com/aws/aws-sdk-go/aws/session"                   //nolint:staticcheck
+	stsv1 "github.com/aws/aws-sdk-go/service/sts"             //nolint:staticcheck
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/smithy-go/middleware"
+
+	"github.com/ekristen/aws-nuke/v3/pkg/config"
+)
+
+func TestBucket(t *testing.T) {
+	now := time.Now()
+	b := newBucket(2, 2)
+
+	assert.Equal(t, time.Duration(0), b.reserve(now))
+	assert.Equal(t, time.Duration(0), b.reserve(now))
+	assert.Equal(t, 500*time.Millisecond, b.reserve(now))
+
+	// Tokens are refilled at the rate
+	assert.Equal(t, time.Duration(0), b.reserve(now.Add(time.Second)))
+
+	b.throttled()
+	assert.Equal(t, 1.0, b.rate)
+	b.throttled()
+	b.throttled()
+	assert.Equal(t, MinRate, b.rate)
+
+	for i := 0; i < 100; i++ {
+		b.succeeded()
+	}
+	assert.Equal(t, 2.0, b.rate, "the rate recovers up to the configured rate")
+	assert.Equal(t, 3, b.throttles)
+	assert.Equal(t, 4, b.requests)
+}
+
+func TestBucket_Unlimited(t *testing.T) {
+	now := time.Now()
+	b := newBucket(0, 0)
+
+	for i := 0; i < 100; i++ {
+		assert.Equal(t, time.Duration(0), b.reserve(now))
+	}
+
+	b.throttled()
+	assert.Equal(t, ThrottledRate, b.rate)
+	assert.Equal(t, 100*time.Millisecond, b.reserve(now), "the next request waits for a token at the lowered rate")
+
+	for i := 0; i < 200; i++ {
+		b.succeeded()
+	}
+	assert.Equal(t, 0.0, b.rate, "the rate is no longer limited once it recovered")
+}
+
+func TestLimiter(t *testing.T) {
+	l := New(&config.RateLimits{
+		Services: map[string]*config.RateLimit{
+			"CloudWatch": {Rate: 1, Burst: 1},
+		},
+	})
+
+	ctx := context.TODO()
+	assert.NoError(t, l.Wait(ctx, "cloudwatch", "PutMetricAlarm", "us-east-1"))
+	assert.NoError(t, l.Wait(ctx, "EC2", "DescribeInstances", "us-east-1"))
+	assert.NoError(t, l.Wait(ctx, "EC2", "DescribeInstances", "us-east-1"))
+	l.Done("EC2", "DescribeInstances", "us-east-1", true)
+
+	// The limit of the service is exhausted, the context ends before the next request is allowed
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.ErrorIs(t, l.Wait(canceled, "CloudWatch", "PutMetricAlarm", "us-east-1"), context.Canceled)
+
+	assert.Equal(t, []Stat{
+		{Service: "cloudwatch", Region: "us-east-1", Requests: 2, Waited: time.Second},
+		{Service: "ec2", Region: "us-east-1", Requests: 2, Throttles: 1},
+	}, roundWaited(l.Stats()))
+
+	var buf bytes.Buffer
+	assert.NoError(t, l.WriteSummary(&buf))
+	assert.Contains(t, buf.String(), "SERVICE     REGION     REQUESTS  THROTTLED  WAITED\n")
+	assert.Contains(t, buf.String(), "ec2         us-east-1  2         1          0s\n")
+
+	buf.Reset()
+	assert.NoError(t, New(nil).WriteSummary(&buf))
+	assert.Empty(t, buf.String())
+}
+
+func TestLimiter_Operation(t *testing.T) {
+	l := New(nil)
+
+	// DeleteRestApi has a default limit of its own, the other operations of the service are not limited
+	ctx := context.TODO()
+	assert.NoError(t, l.Wait(ctx, "API Gateway", "DeleteRestApi", "us-east-1"))
+	assert.NoError(t, l.Wait(ctx, "API Gateway", "GetRestApis", "us-east-1"))
+	assert.NoError(t, l.Wait(ctx, "API Gateway", "GetRestApis", "us-east-1"))
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.ErrorIs(t, l.Wait(canceled, "API Gateway", "DeleteRestApi", "us-east-1"), context.Canceled)
+
+	// The default limit is shared by all regions of the account
+	assert.ErrorIs(t, l.Wait(canceled, "API Gateway", "DeleteRestApi", "eu-west-1"), context.Canceled)
+
+	stats := l.Stats()
+	require.Len(t, stats, 2)
+	assert.Equal(t, "apigateway", stats[0].Service)
+	assert.Equal(t, 2, stats[0].Requests)
+	assert.Equal(t, "apigateway:DeleteRestApi", stats[1].Service)
+	assert.Equal(t, AccountRegion, stats[1].Region)
+	assert.Equal(t, 3, stats[1].Requests)
+}
+
+func TestLimiter_OperationPerRegion(t *testing.T) {
+	l := New(&config.RateLimits{
+		Services: map[string]*config.RateLimit{
+			"apigateway:DeleteRestApi": {Rate: 1, Burst: 1},
+		},
+	})
+
+	// A configured operation limit applies to each region unless it is shared by the account
+	ctx := context.TODO()
+	assert.NoError(t, l.Wait(ctx, "API Gateway", "DeleteRestApi", "us-east-1"))
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.ErrorIs(t, l.Wait(canceled, "API Gateway", "DeleteRestApi", "us-east-1"), context.Canceled)
+	assert.NoError(t, l.Wait(canceled, "API Gateway", "DeleteRestApi", "eu-west-1"))
+}
+
+func roundWaited(stats []Stat) []Stat {
+	for i := range stats {
+		stats[i].Waited = stats[i].Waited.Round(100 * time.Millisecond)
+	}
+	return stats
+}
+
+func newThrottlingServer(t *testing.T, throttles int32) (*httptest.Server, *int32) {
+	t.Helper()
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/xml")
+		if atomic.AddInt32(&calls, 1) <= throttles {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`<ErrorResponse><Error><Type>Sender</Type><Code>Throttling</Code>` +
+				`<Message>Rate exceeded</Message></Error><RequestId>1</RequestId></ErrorResponse>`))
+			return
+		}
+
+		_, _ = w.Write([]byte(`<GetCallerIdentityResponse><GetCallerIdentityResult><Account>000000000000</Account>` +
+			`</GetCallerIdentityResult></GetCallerIdentityResponse>`))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &calls
+}
+
+func TestInstrumentSession(t *testing.T) {
+	server, calls := newThrottlingServer(t, 1)
+
+	sess, err := session.NewSession(&awsv1.Config{
+		Region:      awsv1.String("us-east-1"),
+		Endpoint:    awsv1.String(server.URL),
+		Credentials: credentialsv1.NewStaticCredentials("id", "secret", ""),
+		MaxRetries:  awsv1.Int(1),
+	})
+	require.NoError(t, err)
+
+	l := New(nil)
+	l.InstrumentSession(sess)
+
+	out, err := stsv1.New(sess).GetCallerIdentity(&stsv1.GetCallerIdentityInput{})
+	require.NoError(t, err)
+	assert.Equal(t, "000000000000", awsv1.StringValue(out.Account))
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(calls))
+	assert.Equal(t, []Stat{{Service: "sts", Region: "us-east-1", Requests: 2, Throttles: 1, Waited: 100 * time.Millisecond}},
+		roundWaited(l.Stats()))
+}
+
+func TestAPIOption(t *testing.T) {
+	server, calls := newThrottlingServer(t, 1)
+
+	l := New(nil)
+	cfg := aws.Config{
+		Region:           "us-east-1",
+		BaseEndpoint:     aws.String(server.URL),
+		Credentials:      credentials.NewStaticCredentialsProvider("id", "secret", ""),
+		RetryMaxAttempts: 2,
+		APIOptions:       []func(*middleware.Stack) error{l.APIOption()},
+	}
+
+	out, err := sts.NewFromConfig(cfg).GetCallerIdentity(context.TODO(), &sts.GetCallerIdentityInput{})
+	require.NoError(t, err)
+	assert.Equal(t, "000000000000", aws.ToString(out.Account))
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(calls))
+	assert.Equal(t, []Stat{{Service: "sts", Region: "us-east-1", Requests: 2, Throttles: 1, Waited: 100 * time.Millisecond}},
+		roundWaited(l.Stats()))
+}
@@ -0,0 +1,173 @@
+// Package undo records the definitions of resources right before they are removed, so cheap configuration-only
+// resources such as alarms, rules and records can be recreated after the fact on a best-effort basis.
+package undo
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+
+	"github.com/ekristen/aws-nuke/v3/pkg/nuke"
+)
+
+// Recorder is implemented by resources that can be recreated from the undo log. UndoPayload returns the full
+// definition of the resource, usually the response of the describe or get call of the service, it is passed back to the
+// restorer of the resource type as JSON.
+type Recorder interface {
+	UndoPayload(ctx context.Context) (interface{}, error)
+}
+
+// Restorer recreates a resource from the payload that was recorded before it was removed. The opts are prepared for the
+// resource type and region the resource was removed from.
+type Restorer func(ctx context.Context, opts *nuke.ListerOpts, payload json.RawMessage) error
+
+var (
+	restorersLock sync.RWMutex
+	restorers     = make(map[string]Restorer)
+)
+
+// RegisterRestorer registers the restorer for a resource type. It is meant to be called from init, next to the
+// registration of the resource.
+func RegisterRestorer(resourceType string, restorer Restorer) {
+	restorersLock.Lock()
+	defer restorersLock.Unlock()
+
+	restorers[resourceType] = restorer
+}
+
+// GetRestorer returns the restorer for a resource type, or nil if the resource type cannot be restored.
+func GetRestorer(resourceType string) Restorer {
+	restorersLock.RLock()
+	defer restorersLock.RUnlock()
+
+	return restorers[resourceType]
+}
+
+// Entry is a single resource in the undo log.
+type Entry struct {
+	ResourceType string            `json:"resourceType"`
+	Region       string            `json:"region"`
+	Name         string            `json:"name"`
+	Properties   map[string]string `json:"properties,omitempty"`
+	Payload      json.RawMessage   `json:"payload"`
+	RecordedAt   time.Time         `json:"recordedAt"`
+}
+
+// Log appends entries to an undo log file, one JSON document per line.
+type Log struct {
+	path string
+
+	mu       sync.Mutex
+	recorded map[*queue.Item]bool
+}
+
+// NewLog returns the undo log at the path, the file is created when the first entry is recorded.
+func NewLog(path string) *Log {
+	return &Log{
+		path:     path,
+		recorded: make(map[*queue.Item]bool),
+	}
+}
+
+// Handle records the item in the undo log when its resource is a Recorder. It is meant to be registered as a before
+// remove handler. Each item is recorded once, even when its removal is retried.
+func (l *Log) Handle(ctx context.Context, item *queue.Item) error {
+	recorder, ok := item.Resource.(Recorder)
+	if !ok {
+		return nil
+	}
+
+	l.mu.Lock()
+	done := l.recorded[item]
+	l.mu.Unlock()
+
+	if done {
+		return nil
+	}
+
+	payload, err := recorder.UndoPayload(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to record undo log entry: %w", err)
+	}
+
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("unable to record undo log entry: %w", err)
+	}
+
+	entry := &Entry{
+		ResourceType: item.Type,
+		Region:       item.Owner,
+		Name:         nuke.ItemName(item),
+		Properties:   nuke.ItemProperties(item),
+		Payload:      raw,
+		RecordedAt:   time.Now().UTC(),
+	}
+
+	if err := l.Append(entry); err != nil {
+		return fmt.Errorf("unable to record undo log entry: %w", err)
+	}
+
+	l.mu.Lock()
+	l.recorded[item] = true
+	l.mu.Unlock()
+
+	return nil
+}
+
+// Append writes the entry to the end of the undo log.
+func (l *Log) Append(entry *Entry) error {
+	raw, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(raw, '\n')); err != nil {
+		return err
+	}
+
+	return f.Sync()
+}
+
+// Read returns all entries of the undo log at the path.
+func Read(path string) ([]*Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+
+	entries := make([]*Entry, 0)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		entry := &Entry{}
+		if err := json.Unmarshal(scanner.Bytes(), entry); err != nil {
+			return nil, fmt.Errorf("undo log line %d: %w", line, err)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, scanner.Err()
+}
@@ -0,0 +1,103 @@
+package undo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+	"github.com/ekristen/libnuke/pkg/types"
+
+	"github.com/ekristen/aws-nuke/v3/pkg/nuke"
+)
+
+type testResource struct {
+	name  string
+	err   error
+	calls int
+}
+
+func (r *testResource) Remove(_ context.Context) error {
+	return nil
+}
+
+func (r *testResource) String() string {
+	return r.name
+}
+
+type testRecorder struct {
+	testResource
+}
+
+func (r *testRecorder) Properties() types.Properties {
+	return types.NewProperties().Set("Name", r.name)
+}
+
+func (r *testRecorder) UndoPayload(_ context.Context) (interface{}, error) {
+	r.calls++
+	return map[string]string{"Name": r.name}, r.err
+}
+
+func TestLog_Handle(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "undo.jsonl")
+	log := NewLog(path)
+
+	recorded := &testRecorder{testResource{name: "alarm"}}
+	items := []*queue.Item{
+		{Type: "CloudWatchAlarm", Owner: "us-east-1", Resource: recorded},
+		{Type: "EC2Instance", Owner: "us-east-1", Resource: &testResource{name: "i-1"}},
+	}
+
+	for _, item := range items {
+		assert.NoError(t, log.Handle(context.TODO(), item))
+	}
+
+	// A retried removal is only recorded once
+	assert.NoError(t, log.Handle(context.TODO(), items[0]))
+	assert.Equal(t, 1, recorded.calls)
+
+	entries, err := Read(path)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "CloudWatchAlarm", entries[0].ResourceType)
+	assert.Equal(t, "us-east-1", entries[0].Region)
+	assert.Equal(t, "alarm", entries[0].Name)
+	assert.Equal(t, map[string]string{"Name": "alarm"}, entries[0].Properties)
+	assert.JSONEq(t, `{"Name": "alarm"}`, string(entries[0].Payload))
+	assert.False(t, entries[0].RecordedAt.IsZero())
+}
+
+func TestLog_HandleError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "undo.jsonl")
+	log := NewLog(path)
+
+	item := &queue.Item{
+		Type:     "CloudWatchAlarm",
+		Owner:    "us-east-1",
+		Resource: &testRecorder{testResource{name: "alarm", err: errors.New("access denied")}},
+	}
+
+	assert.EqualError(t, log.Handle(context.TODO(), item), "unable to record undo log entry: access denied")
+
+	_, err := Read(path)
+	assert.Error(t, err)
+}
+
+func TestRegisterRestorer(t *testing.T) {
+	assert.Nil(t, GetRestorer("UndoTestResource"))
+
+	var restored json.RawMessage
+	RegisterRestorer("UndoTestResource", func(_ context.Context, _ *nuke.ListerOpts, payload json.RawMessage) error {
+		restored = payload
+		return nil
+	})
+
+	restorer := GetRestorer("UndoTestResource")
+	assert.NotNil(t, restorer)
+	assert.NoError(t, restorer(context.TODO(), &nuke.ListerOpts{}, json.RawMessage(`{}`)))
+	assert.Equal(t, json.RawMessage(`{}`), restored)
+}
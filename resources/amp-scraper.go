@@ -21,6 +21,8 @@ func init() {
 		Resource: &AMPScraper{},
 		Lister:   &AMPScraperLister{},
 	})
+
+	nuke.RegisterSDKv2(AMPScraperResource)
 }
 
 type AMPScraperLister struct{}
@@ -21,6 +21,8 @@ func init() {
 		Resource: &AMPWorkspace{},
 		Lister:   &AMPWorkspaceLister{},
 	})
+
+	nuke.RegisterSDKv2(AMPWorkspaceResource)
 }
 
 type AMPWorkspaceLister struct{}
@@ -4,8 +4,6 @@ import (
 	"context"
 	"time"
 
-	"go.uber.org/ratelimit"
-
 	"github.com/aws/aws-sdk-go/aws"                //nolint:staticcheck
 	"github.com/aws/aws-sdk-go/service/apigateway" //nolint:staticcheck
 
@@ -18,12 +16,6 @@ import (
 
 const APIGatewayAPIKeyResource = "APIGatewayAPIKey"
 
-// Rate limit to avoid throttling when deleting API Gateway Rest APIs
-// The API Gateway Delete Rest API has a limit of 1 request per 30 seconds for each account
-// https://docs.aws.amazon.com/apigateway/latest/developerguide/limits.html
-// Note: due to time drift, set to 31 seconds to be safe.
-var deleteAPIKeyLimit = ratelimit.New(1, ratelimit.Per(32*time.Second))
-
 func init() {
 	registry.Register(&registry.Registration{
 		Name:                APIGatewayAPIKeyResource,
@@ -81,8 +73,6 @@ type APIGatewayAPIKey struct {
 }
 
 func (r *APIGatewayAPIKey) Remove(_ context.Context) error {
-	deleteAPIKeyLimit.Take()
-
 	_, err := r.svc.DeleteApiKey(&apigateway.DeleteApiKeyInput{
 		ApiKey: r.apiKey,
 	})
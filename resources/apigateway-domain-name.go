@@ -23,6 +23,8 @@ func init() {
 		Resource: &APIGatewayDomainName{},
 		Lister:   &APIGatewayDomainNameLister{},
 	})
+
+	nuke.RegisterSDKv2(APIGatewayDomainNameResource)
 }
 
 type APIGatewayDomainNameLister struct{}
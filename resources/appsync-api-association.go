@@ -27,6 +27,8 @@ func init() {
 		Resource: &AppSyncAPIAssociation{},
 		Lister:   &AppSyncAPIAssociationLister{},
 	})
+
+	nuke.RegisterSDKv2(AppSyncAPIAssociationResource)
 }
 
 type AppSyncAPIAssociationLister struct{}
@@ -21,6 +21,8 @@ func init() {
 		Resource: &AppSyncAPI{},
 		Lister:   &AppSyncAPILister{},
 	})
+
+	nuke.RegisterSDKv2(AppSyncAPIResource)
 }
 
 type AppSyncAPILister struct{}
@@ -21,6 +21,8 @@ func init() {
 		Resource: &AppSyncDomainName{},
 		Lister:   &AppSyncDomainNameLister{},
 	})
+
+	nuke.RegisterSDKv2(AppSyncDomainNameResource)
 }
 
 type AppSyncDomainNameLister struct{}
@@ -2,6 +2,7 @@ package resources
 
 import (
 	"context"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"                 //nolint:staticcheck
 	"github.com/aws/aws-sdk-go/service/autoscaling" //nolint:staticcheck
@@ -87,3 +88,8 @@ func (asg *AutoScalingGroup) Properties() types.Properties {
 
 	return properties
 }
+
+// GetCreationTime returns the time the group was created.
+func (asg *AutoScalingGroup) GetCreationTime() *time.Time {
+	return asg.group.CreatedTime
+}
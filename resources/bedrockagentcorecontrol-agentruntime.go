@@ -23,6 +23,8 @@ func init() {
 		Resource: &BedrockAgentCoreAgentRuntime{},
 		Lister:   &BedrockAgentCoreAgentRuntimeLister{},
 	})
+
+	nuke.RegisterSDKv2(BedrockAgentCoreAgentRuntimeResource)
 }
 
 type BedrockAgentCoreAgentRuntimeLister struct {
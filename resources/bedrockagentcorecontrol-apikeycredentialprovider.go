@@ -23,6 +23,8 @@ func init() {
 		Resource: &BedrockAgentCoreAPIKeyCredentialProvider{},
 		Lister:   &BedrockAgentCoreAPIKeyCredentialProviderLister{},
 	})
+
+	nuke.RegisterSDKv2(BedrockAgentCoreAPIKeyCredentialProviderResource)
 }
 
 type BedrockAgentCoreAPIKeyCredentialProviderLister struct {
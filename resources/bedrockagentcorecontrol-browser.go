@@ -23,6 +23,8 @@ func init() {
 		Resource: &BedrockAgentCoreBrowser{},
 		Lister:   &BedrockAgentCoreBrowserLister{},
 	})
+
+	nuke.RegisterSDKv2(BedrockAgentCoreBrowserResource)
 }
 
 type BedrockAgentCoreBrowserLister struct {
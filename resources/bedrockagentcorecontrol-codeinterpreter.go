@@ -23,6 +23,8 @@ func init() {
 		Resource: &BedrockAgentCoreCodeInterpreter{},
 		Lister:   &BedrockAgentCoreCodeInterpreterLister{},
 	})
+
+	nuke.RegisterSDKv2(BedrockAgentCoreCodeInterpreterResource)
 }
 
 type BedrockAgentCoreCodeInterpreterLister struct {
@@ -23,6 +23,8 @@ func init() {
 		Resource: &BedrockAgentCoreGateway{},
 		Lister:   &BedrockAgentCoreGatewayLister{},
 	})
+
+	nuke.RegisterSDKv2(BedrockAgentCoreGatewayResource)
 }
 
 type BedrockAgentCoreGatewayLister struct {
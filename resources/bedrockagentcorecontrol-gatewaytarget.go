@@ -23,6 +23,8 @@ func init() {
 		Resource: &BedrockAgentCoreGatewayTarget{},
 		Lister:   &BedrockAgentCoreGatewayTargetLister{},
 	})
+
+	nuke.RegisterSDKv2(BedrockAgentCoreGatewayTargetResource)
 }
 
 type BedrockAgentCoreGatewayTargetLister struct {
@@ -23,6 +23,8 @@ func init() {
 		Resource: &BedrockAgentCoreMemory{},
 		Lister:   &BedrockAgentCoreMemoryLister{},
 	})
+
+	nuke.RegisterSDKv2(BedrockAgentCoreMemoryResource)
 }
 
 type BedrockAgentCoreMemoryLister struct {
@@ -23,6 +23,8 @@ func init() {
 		Resource: &BedrockAgentCoreOauth2CredentialProvider{},
 		Lister:   &BedrockAgentCoreOauth2CredentialProviderLister{},
 	})
+
+	nuke.RegisterSDKv2(BedrockAgentCoreOauth2CredentialProviderResource)
 }
 
 type BedrockAgentCoreOauth2CredentialProviderLister struct {
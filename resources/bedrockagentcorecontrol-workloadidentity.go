@@ -23,6 +23,8 @@ func init() {
 		Resource: &BedrockAgentCoreWorkloadIdentity{},
 		Lister:   &BedrockAgentCoreWorkloadIdentityLister{},
 	})
+
+	nuke.RegisterSDKv2(BedrockAgentCoreWorkloadIdentityResource)
 }
 
 type BedrockAgentCoreWorkloadIdentityLister struct {
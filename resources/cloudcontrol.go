@@ -4,13 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"time"
 
 	"github.com/google/uuid"
 	"github.com/gotidy/ptr"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
-	"go.uber.org/ratelimit"
 
 	"github.com/aws/aws-sdk-go/aws/awserr"              //nolint:staticcheck
 	"github.com/aws/aws-sdk-go/service/cloudcontrolapi" //nolint:staticcheck
@@ -55,11 +53,6 @@ func init() {
 	RegisterCloudControl("AWS::NetworkFirewall::RuleGroup")
 }
 
-// describeRateLimit is a rate limiter to avoid throttling when describing resources via the cloud control api.
-// AWS does not publish the rate limits for the cloud control api, the rate seems to be 60 reqs/minute, setting to
-// 55 and setting no slack to avoid throttling.
-var describeRateLimit = ratelimit.New(55, ratelimit.Per(time.Minute), ratelimit.WithoutSlack)
-
 // RegisterCloudControl registers a resource type for the Cloud Control API. This is a unique function that is used
 // in two different places. The first place is in the init() function of this file, where it is used to register
 // a select subset of Cloud Control API resource types. The second place is in nuke command file, where it is used
@@ -94,9 +87,6 @@ func (l *CloudControlResourceLister) List(_ context.Context, o interface{}) ([]r
 	}
 
 	if err := svc.ListResourcesPages(params, func(page *cloudcontrolapi.ListResourcesOutput, lastPage bool) bool {
-		dt := describeRateLimit.Take()
-		l.logger.Debugf("rate limit time: %s", dt)
-
 		for _, desc := range page.ResourceDescriptions {
 			identifier := ptr.ToString(desc.Identifier)
 			properties, err := l.cloudControlParseProperties(ptr.ToString(desc.Properties))
@@ -29,6 +29,8 @@ func init() {
 			CloudFrontDistributionDeploymentResource,
 		},
 	})
+
+	nuke.RegisterSDKv2(CloudFrontDistributionResource)
 }
 
 type CloudFrontDistributionLister struct {
@@ -2,10 +2,11 @@ package resources
 
 import (
 	"context"
-	"time"
+	"encoding/json"
+	"fmt"
+	"slices"
 
 	"github.com/gotidy/ptr"
-	"go.uber.org/ratelimit"
 
 	"github.com/aws/aws-sdk-go/aws"                //nolint:staticcheck
 	"github.com/aws/aws-sdk-go/service/cloudwatch" //nolint:staticcheck
@@ -15,6 +16,7 @@ import (
 	"github.com/ekristen/libnuke/pkg/types"
 
 	"github.com/ekristen/aws-nuke/v3/pkg/nuke"
+	"github.com/ekristen/aws-nuke/v3/pkg/undo"
 )
 
 const CloudWatchAlarmResource = "CloudWatchAlarm"
@@ -26,13 +28,9 @@ func init() {
 		Resource: &CloudWatchAlarm{},
 		Lister:   &CloudWatchAlarmLister{},
 	})
-}
-
-// ref - https://docs.aws.amazon.com/AmazonCloudWatch/latest/monitoring/cloudwatch_limits.html
 
-var CloudWatchAlarmDescribeRateLimit = ratelimit.New(8, ratelimit.Per(time.Second))
-var CloudWatchAlarmDeleteRateLimit = ratelimit.New(3, ratelimit.Per(time.Second))
-var CloudWatchAlarmListTagsRateLimit = ratelimit.New(5, ratelimit.Per(time.Second))
+	undo.RegisterRestorer(CloudWatchAlarmResource, restoreCloudWatchAlarm)
+}
 
 type CloudWatchAlarmLister struct{}
 
@@ -51,8 +49,6 @@ func (l *CloudWatchAlarmLister) List(_ context.Context, o interface{}) ([]resour
 	}
 
 	for {
-		CloudWatchAlarmDescribeRateLimit.Take()
-
 		output, err := svc.DescribeAlarms(params)
 		if err != nil {
 			return nil, err
@@ -94,9 +90,31 @@ func (l *CloudWatchAlarmLister) List(_ context.Context, o interface{}) ([]resour
 	return resources, nil
 }
 
-func GetAlarmTags(svc *cloudwatch.CloudWatch, arn *string) ([]*cloudwatch.Tag, error) {
-	CloudWatchAlarmListTagsRateLimit.Take()
+// RemoveBatch removes the alarms with DeleteAlarms, up to 100 at a time. When a request fails, because one of the
+// alarms cannot be removed, the alarms of the request are removed one at a time.
+func (l *CloudWatchAlarmLister) RemoveBatch(ctx context.Context, o interface{}, resources []resource.Resource) []error {
+	opts := o.(*nuke.ListerOpts)
+	svc := cloudwatch.New(opts.Session)
+
+	errs := make([]error, 0, len(resources))
+	for chunk := range slices.Chunk(resources, 100) {
+		names := make([]*string, len(chunk))
+		for i, r := range chunk {
+			names[i] = r.(*CloudWatchAlarm).Name
+		}
 
+		if _, err := svc.DeleteAlarms(&cloudwatch.DeleteAlarmsInput{AlarmNames: names}); err != nil {
+			errs = append(errs, nuke.RemoveEach(ctx, chunk)...)
+			continue
+		}
+
+		errs = append(errs, make([]error, len(chunk))...)
+	}
+
+	return errs
+}
+
+func GetAlarmTags(svc *cloudwatch.CloudWatch, arn *string) ([]*cloudwatch.Tag, error) {
 	resp, err := svc.ListTagsForResource(&cloudwatch.ListTagsForResourceInput{ResourceARN: arn})
 	if err != nil {
 		return nil, err
@@ -113,8 +131,6 @@ type CloudWatchAlarm struct {
 }
 
 func (r *CloudWatchAlarm) Remove(_ context.Context) error {
-	CloudWatchAlarmDeleteRateLimit.Take()
-
 	_, err := r.svc.DeleteAlarms(&cloudwatch.DeleteAlarmsInput{
 		AlarmNames: []*string{r.Name},
 	})
@@ -129,3 +145,101 @@ func (r *CloudWatchAlarm) Properties() types.Properties {
 func (r *CloudWatchAlarm) String() string {
 	return *r.Name
 }
+
+// cloudWatchAlarmUndo is the definition of an alarm that is recorded in the undo log, only one of the alarms is set
+type cloudWatchAlarmUndo struct {
+	MetricAlarm    *cloudwatch.MetricAlarm
+	CompositeAlarm *cloudwatch.CompositeAlarm
+	Tags           []*cloudwatch.Tag
+}
+
+// UndoPayload returns the definition of the alarm
+func (r *CloudWatchAlarm) UndoPayload(_ context.Context) (interface{}, error) {
+	resp, err := r.svc.DescribeAlarms(&cloudwatch.DescribeAlarmsInput{
+		AlarmNames: []*string{r.Name},
+		AlarmTypes: []*string{r.Type},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	p := &cloudWatchAlarmUndo{
+		Tags: r.Tags,
+	}
+
+	switch {
+	case len(resp.MetricAlarms) > 0:
+		p.MetricAlarm = resp.MetricAlarms[0]
+	case len(resp.CompositeAlarms) > 0:
+		p.CompositeAlarm = resp.CompositeAlarms[0]
+	default:
+		return nil, fmt.Errorf("alarm %s not found", aws.StringValue(r.Name))
+	}
+
+	return p, nil
+}
+
+// restoreCloudWatchAlarm recreates an alarm from the undo log, the state of the alarm is not restored
+func restoreCloudWatchAlarm(_ context.Context, opts *nuke.ListerOpts, payload json.RawMessage) error {
+	p := &cloudWatchAlarmUndo{}
+	if err := json.Unmarshal(payload, p); err != nil {
+		return err
+	}
+
+	var tags []*cloudwatch.Tag
+	if len(p.Tags) > 0 {
+		tags = p.Tags
+	}
+
+	svc := cloudwatch.New(opts.Session)
+
+	if a := p.CompositeAlarm; a != nil {
+		_, err := svc.PutCompositeAlarm(&cloudwatch.PutCompositeAlarmInput{
+			AlarmName:                        a.AlarmName,
+			AlarmDescription:                 a.AlarmDescription,
+			AlarmRule:                        a.AlarmRule,
+			ActionsEnabled:                   a.ActionsEnabled,
+			AlarmActions:                     a.AlarmActions,
+			OKActions:                        a.OKActions,
+			InsufficientDataActions:          a.InsufficientDataActions,
+			ActionsSuppressor:                a.ActionsSuppressor,
+			ActionsSuppressorExtensionPeriod: a.ActionsSuppressorExtensionPeriod,
+			ActionsSuppressorWaitPeriod:      a.ActionsSuppressorWaitPeriod,
+			Tags:                             tags,
+		})
+
+		return err
+	}
+
+	a := p.MetricAlarm
+	if a == nil {
+		return fmt.Errorf("the undo log entry does not contain an alarm")
+	}
+
+	_, err := svc.PutMetricAlarm(&cloudwatch.PutMetricAlarmInput{
+		AlarmName:                        a.AlarmName,
+		AlarmDescription:                 a.AlarmDescription,
+		ActionsEnabled:                   a.ActionsEnabled,
+		AlarmActions:                     a.AlarmActions,
+		OKActions:                        a.OKActions,
+		InsufficientDataActions:          a.InsufficientDataActions,
+		ComparisonOperator:               a.ComparisonOperator,
+		DatapointsToAlarm:                a.DatapointsToAlarm,
+		Dimensions:                       a.Dimensions,
+		EvaluateLowSampleCountPercentile: a.EvaluateLowSampleCountPercentile,
+		EvaluationPeriods:                a.EvaluationPeriods,
+		ExtendedStatistic:                a.ExtendedStatistic,
+		MetricName:                       a.MetricName,
+		Metrics:                          a.Metrics,
+		Namespace:                        a.Namespace,
+		Period:                           a.Period,
+		Statistic:                        a.Statistic,
+		Threshold:                        a.Threshold,
+		ThresholdMetricId:                a.ThresholdMetricId,
+		TreatMissingData:                 a.TreatMissingData,
+		Unit:                             a.Unit,
+		Tags:                             tags,
+	})
+
+	return err
+}
@@ -2,6 +2,7 @@ package resources
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"github.com/aws/aws-sdk-go/aws"                      //nolint:staticcheck
@@ -12,6 +13,7 @@ import (
 	"github.com/ekristen/libnuke/pkg/types"
 
 	"github.com/ekristen/aws-nuke/v3/pkg/nuke"
+	"github.com/ekristen/aws-nuke/v3/pkg/undo"
 )
 
 const CloudWatchEventsRuleResource = "CloudWatchEventsRule"
@@ -23,6 +25,8 @@ func init() {
 		Resource: &CloudWatchEventsRule{},
 		Lister:   &CloudWatchEventsRuleLister{},
 	})
+
+	undo.RegisterRestorer(CloudWatchEventsRuleResource, restoreCloudWatchEventsRule)
 }
 
 type CloudWatchEventsRuleLister struct{}
@@ -95,3 +99,111 @@ func (r *CloudWatchEventsRule) String() string {
 	// TODO: remove Rule:, mark as breaking change for filters
 	return fmt.Sprintf("Rule: %s", *r.Name)
 }
+
+// cloudWatchEventsRuleUndo is the definition of a rule and its targets that is recorded in the undo log
+type cloudWatchEventsRuleUndo struct {
+	Rule    *cloudwatchevents.DescribeRuleOutput
+	Targets []*cloudwatchevents.Target
+	Tags    []*cloudwatchevents.Tag
+}
+
+// UndoPayload returns the definition of the rule with its targets
+func (r *CloudWatchEventsRule) UndoPayload(_ context.Context) (interface{}, error) {
+	rule, err := r.svc.DescribeRule(&cloudwatchevents.DescribeRuleInput{
+		Name:         r.Name,
+		EventBusName: r.EventBusName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	p := &cloudWatchEventsRuleUndo{
+		Rule: rule,
+	}
+
+	params := &cloudwatchevents.ListTargetsByRuleInput{
+		Rule:         r.Name,
+		EventBusName: r.EventBusName,
+	}
+
+	for {
+		resp, err := r.svc.ListTargetsByRule(params)
+		if err != nil {
+			return nil, err
+		}
+
+		p.Targets = append(p.Targets, resp.Targets...)
+
+		if resp.NextToken == nil {
+			break
+		}
+
+		params.NextToken = resp.NextToken
+	}
+
+	tags, err := r.svc.ListTagsForResource(&cloudwatchevents.ListTagsForResourceInput{
+		ResourceARN: r.ARN,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	p.Tags = tags.Tags
+
+	return p, nil
+}
+
+// restoreCloudWatchEventsRule recreates a rule and its targets from the undo log
+func restoreCloudWatchEventsRule(_ context.Context, opts *nuke.ListerOpts, payload json.RawMessage) error {
+	p := &cloudWatchEventsRuleUndo{}
+	if err := json.Unmarshal(payload, p); err != nil {
+		return err
+	}
+
+	if p.Rule == nil {
+		return fmt.Errorf("the undo log entry does not contain a rule")
+	}
+
+	if p.Rule.ManagedBy != nil {
+		return fmt.Errorf("the rule is managed by %s and must be recreated by it", aws.StringValue(p.Rule.ManagedBy))
+	}
+
+	svc := cloudwatchevents.New(opts.Session)
+
+	input := &cloudwatchevents.PutRuleInput{
+		Name:               p.Rule.Name,
+		EventBusName:       p.Rule.EventBusName,
+		Description:        p.Rule.Description,
+		EventPattern:       p.Rule.EventPattern,
+		ScheduleExpression: p.Rule.ScheduleExpression,
+		RoleArn:            p.Rule.RoleArn,
+		State:              p.Rule.State,
+	}
+	if len(p.Tags) > 0 {
+		input.Tags = p.Tags
+	}
+
+	if _, err := svc.PutRule(input); err != nil {
+		return err
+	}
+
+	if len(p.Targets) == 0 {
+		return nil
+	}
+
+	resp, err := svc.PutTargets(&cloudwatchevents.PutTargetsInput{
+		Rule:         p.Rule.Name,
+		EventBusName: p.Rule.EventBusName,
+		Targets:      p.Targets,
+	})
+	if err != nil {
+		return err
+	}
+
+	if aws.Int64Value(resp.FailedEntryCount) > 0 {
+		return fmt.Errorf("the rule was restored, but %d of its targets failed: %s",
+			aws.Int64Value(resp.FailedEntryCount), aws.StringValue(resp.FailedEntries[0].ErrorMessage))
+	}
+
+	return nil
+}
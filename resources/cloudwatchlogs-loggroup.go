@@ -39,15 +39,22 @@ func init() {
 			LambdaFunctionResource, // Reason: Lambda functions can recreate log groups due to invocations, automatic container provisioning, etc.
 		},
 	})
+
+	nuke.RegisterSDKv2(CloudWatchLogsLogGroupResource)
 }
 
 type CloudWatchLogsLogGroupLister struct{}
 
 func (l *CloudWatchLogsLogGroupLister) List(ctx context.Context, o interface{}) ([]resource.Resource, error) {
+	return nuke.ListPages(ctx, l, o)
+}
+
+// ListPages lists the log groups one page of up to 50 at a time.
+func (l *CloudWatchLogsLogGroupLister) ListPages(
+	ctx context.Context, o interface{}, page func([]resource.Resource) error) error {
 	opts := o.(*nuke.ListerOpts)
 
 	svc := cloudwatchlogs.NewFromConfig(*opts.Config)
-	resources := make([]resource.Resource, 0)
 
 	// Note: these can be modified by the customer and account, and we could query them but for now we hard code
 	// them to the bottom, because really it's per-second, and we should be fine querying at this rate for clearing
@@ -67,9 +74,10 @@ func (l *CloudWatchLogsLogGroupLister) List(ctx context.Context, o interface{})
 
 		output, err := paginator.NextPage(ctx)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
+		resources := make([]resource.Resource, 0, len(output.LogGroups))
 		for i := range output.LogGroups {
 			logGroup := &output.LogGroups[i]
 			tagRl.Take() // Wait for ListTagsForResource rate limiter
@@ -125,9 +133,13 @@ func (l *CloudWatchLogsLogGroupLister) List(ctx context.Context, o interface{})
 				protection:      logGroup.DeletionProtectionEnabled,
 			})
 		}
+
+		if err := page(resources); err != nil {
+			return err
+		}
 	}
 
-	return resources, nil
+	return nil
 }
 
 type CloudWatchLogsLogGroup struct {
@@ -142,6 +154,12 @@ type CloudWatchLogsLogGroup struct {
 	protection      *bool
 }
 
+// LastModified returns when the log group was created, a log group that is created again after the scan is not
+// removed.
+func (r *CloudWatchLogsLogGroup) LastModified() *time.Time {
+	return r.CreationTime
+}
+
 func (r *CloudWatchLogsLogGroup) Remove(ctx context.Context) error {
 	if ptr.ToBool(r.protection) && r.settings.GetBool("DisableDeletionProtection") {
 		_, err := r.svc.PutLogGroupDeletionProtection(ctx, &cloudwatchlogs.PutLogGroupDeletionProtectionInput{
@@ -2,6 +2,7 @@ package resources
 
 import (
 	"context"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/docdb"
@@ -12,6 +13,7 @@ import (
 	libsettings "github.com/ekristen/libnuke/pkg/settings"
 	"github.com/ekristen/libnuke/pkg/types"
 
+	"github.com/ekristen/aws-nuke/v3/pkg/backup"
 	"github.com/ekristen/aws-nuke/v3/pkg/nuke"
 )
 
@@ -30,8 +32,11 @@ func init() {
 		},
 		Settings: []string{
 			"DisableDeletionProtection",
+			"BackupBeforeDelete",
 		},
 	})
+
+	nuke.RegisterSDKv2(DocDBClusterResource)
 }
 
 type DocDBClusterLister struct{}
@@ -100,10 +105,17 @@ func (r *DocDBCluster) Remove(ctx context.Context) error {
 		}
 	}
 
-	_, err := r.svc.DeleteDBCluster(ctx, &docdb.DeleteDBClusterInput{
+	params := &docdb.DeleteDBClusterInput{
 		DBClusterIdentifier: r.ID,
 		SkipFinalSnapshot:   aws.Bool(true),
-	})
+	}
+
+	if r.settings.GetBool("BackupBeforeDelete") {
+		params.SkipFinalSnapshot = aws.Bool(false)
+		params.FinalDBSnapshotIdentifier = aws.String(backup.SnapshotIdentifier(aws.ToString(r.ID), time.Now()))
+	}
+
+	_, err := r.svc.DeleteDBCluster(ctx, params)
 	return err
 }
 
@@ -19,6 +19,8 @@ func init() {
 		Resource: &DocDBElasticCluster{},
 		Lister:   &DocDBElasticClusterLister{},
 	})
+
+	nuke.RegisterSDKv2(DocDBElasticClusterResource)
 }
 
 type DocDBElasticClusterLister struct{}
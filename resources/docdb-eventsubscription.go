@@ -21,6 +21,8 @@ func init() {
 		Resource: &DocDBEventSubscription{},
 		Lister:   &DocDBEventSubscriptionLister{},
 	})
+
+	nuke.RegisterSDKv2(DocDBEventSubscriptionResource)
 }
 
 type DocDBEventSubscriptionLister struct{}
@@ -23,6 +23,8 @@ func init() {
 		Resource: &DocDBInstance{},
 		Lister:   &DocDBInstanceLister{},
 	})
+
+	nuke.RegisterSDKv2(DocDBInstanceResource)
 }
 
 type DocDBInstanceLister struct{}
@@ -25,6 +25,8 @@ func init() {
 		Resource: &DocDBParameterGroup{},
 		Lister:   &DocDBParameterGroupLister{},
 	})
+
+	nuke.RegisterSDKv2(DocDBParameterGroupResource)
 }
 
 type DocDBParameterGroupLister struct{}
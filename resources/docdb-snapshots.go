@@ -11,8 +11,10 @@ import (
 
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
+	libsettings "github.com/ekristen/libnuke/pkg/settings"
 	"github.com/ekristen/libnuke/pkg/types"
 
+	"github.com/ekristen/aws-nuke/v3/pkg/backup"
 	"github.com/ekristen/aws-nuke/v3/pkg/nuke"
 )
 
@@ -24,7 +26,12 @@ func init() {
 		Scope:    nuke.Account,
 		Resource: &DocDBSnapshot{},
 		Lister:   &DocDBSnapshotLister{},
+		Settings: []string{
+			"IncludeFinalSnapshots",
+		},
 	})
+
+	nuke.RegisterSDKv2(DocDBSnapshotResource)
 }
 
 type DocDBSnapshotLister struct{}
@@ -74,7 +81,8 @@ func (l *DocDBSnapshotLister) List(ctx context.Context, o interface{}) ([]resour
 }
 
 type DocDBSnapshot struct {
-	svc *docdb.Client
+	svc      *docdb.Client
+	settings *libsettings.Setting
 
 	ARN                *string
 	Identifier         *string
@@ -85,11 +93,15 @@ type DocDBSnapshot struct {
 	Tags               []docdbtypes.Tag
 }
 
+func (r *DocDBSnapshot) Settings(setting *libsettings.Setting) {
+	r.settings = setting
+}
+
 func (r *DocDBSnapshot) Filter() error {
 	if *r.SnapshotType == RDSAutomatedSnapshot {
 		return fmt.Errorf("cannot delete automated snapshots")
 	}
-	return nil
+	return backup.FilterFinalSnapshot(backup.IsFinalSnapshot(aws.ToString(r.Identifier)), r.settings)
 }
 
 func (r *DocDBSnapshot) Remove(ctx context.Context) error {
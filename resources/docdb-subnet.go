@@ -20,6 +20,8 @@ func init() {
 		Resource: &DocDBSubnetGroup{},
 		Lister:   &DocDBSubnetGroupLister{},
 	})
+
+	nuke.RegisterSDKv2(DocDBSubnetGroupResource)
 }
 
 type DocDBSubnetGroupLister struct{}
@@ -31,6 +31,8 @@ func init() {
 			"DisableDeletionProtection",
 		},
 	})
+
+	nuke.RegisterSDKv2(DSQLClusterResource)
 }
 
 type DSQLClusterLister struct{}
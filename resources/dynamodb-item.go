@@ -2,13 +2,18 @@ package resources
 
 import (
 	"context"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
+	"sync"
 
-	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
 
-	"github.com/aws/aws-sdk-go/aws"              //nolint:staticcheck
-	"github.com/aws/aws-sdk-go/service/dynamodb" //nolint:staticcheck
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamodbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
@@ -19,6 +24,14 @@ import (
 
 const DynamoDBTableItemResource = "DynamoDBTableItem"
 
+const (
+	// dynamoDBTableItemScanSegments is the number of segments of a table that are scanned in parallel
+	dynamoDBTableItemScanSegments = 4
+
+	// dynamoDBTableItemBatchSize is the maximum number of requests of a single BatchWriteItem call
+	dynamoDBTableItemBatchSize = 25
+)
+
 func init() {
 	registry.Register(&registry.Registration{
 		Name:     DynamoDBTableItemResource,
@@ -26,104 +39,336 @@ func init() {
 		Resource: &DynamoDBTableItem{},
 		Lister:   &DynamoDBTableItemLister{},
 	})
+
+	nuke.RegisterSDKv2(DynamoDBTableItemResource)
 }
 
 type DynamoDBTableItemLister struct{}
 
 func (l *DynamoDBTableItemLister) List(ctx context.Context, o interface{}) ([]resource.Resource, error) {
+	return nuke.ListPages(ctx, l, o)
+}
+
+// ListPages scans the primary keys of the items of every table. The segments of a table are scanned in parallel, every
+// page of up to 1 MB of keys is passed on as it arrives.
+func (l *DynamoDBTableItemLister) ListPages(ctx context.Context, o interface{}, page func([]resource.Resource) error) error {
 	opts := o.(*nuke.ListerOpts)
+	svc := dynamodb.NewFromConfig(*opts.Config)
 
-	svc := dynamodb.New(opts.Session)
+	// The tables are cached until a table is removed, the items are listed again in every loop of the run
+	tables, err := nuke.Cached(opts, nuke.ListCacheKey{ResourceType: DynamoDBTableResource, Params: "names"},
+		func() ([]string, error) {
+			return listDynamoDBTableNames(ctx, svc)
+		})
+	if err != nil {
+		return err
+	}
 
-	tableLister := &DynamoDBTableLister{}
-	tables, tablesErr := tableLister.List(ctx, o)
-	if tablesErr != nil {
-		return nil, tablesErr
+	// The pages of the segments are passed on one at a time
+	var pageLock sync.Mutex
+	segmentPage := func(resources []resource.Resource) error {
+		pageLock.Lock()
+		defer pageLock.Unlock()
+		return page(resources)
 	}
 
-	resources := make([]resource.Resource, 0)
-	for _, dynamoTableResource := range tables {
-		dynamoTable, ok := dynamoTableResource.(*DynamoDBTable)
-		if !ok {
-			// This should never happen (tm).
-			logrus.Errorf("Unable to cast DynamoDBTable.")
-			continue
+	for _, table := range tables {
+		key, err := describeDynamoDBTableKey(ctx, svc, table)
+		if err == nil && opts.ResolveSettings != nil {
+			var tags map[string]string
+			tags, err = listDynamoDBTableTags(ctx, svc, key.TableARN)
+			key.BackedUp = dynamoDBTableBackedUp(opts, table, tags)
+		}
+		if err != nil {
+			var notFound *dynamodbtypes.ResourceNotFoundException
+			if errors.As(err, &notFound) {
+				continue
+			}
+
+			return err
 		}
 
-		describeParams := &dynamodb.DescribeTableInput{
-			TableName: dynamoTable.Name,
+		group, groupCtx := errgroup.WithContext(ctx)
+		for segment := int32(0); segment < dynamoDBTableItemScanSegments; segment++ {
+			group.Go(func() error {
+				return scanDynamoDBTableSegment(groupCtx, svc, table, key, segment, segmentPage)
+			})
 		}
 
-		descResp, descErr := svc.DescribeTable(describeParams)
-		if descErr != nil {
-			return nil, descErr
+		if err := group.Wait(); err != nil {
+			return err
 		}
+	}
+
+	return nil
+}
+
+// RemoveBatch removes the items with BatchWriteItem, up to 25 at a time. The items that were not processed fail, they
+// are removed again in the next attempt.
+func (l *DynamoDBTableItemLister) RemoveBatch(ctx context.Context, o interface{}, resources []resource.Resource) []error {
+	opts := o.(*nuke.ListerOpts)
+	svc := dynamodb.NewFromConfig(*opts.Config)
 
-		keyName := descResp.Table.KeySchema[0].AttributeName
-		params := &dynamodb.ScanInput{
-			TableName:            dynamoTable.Name,
-			ProjectionExpression: aws.String("#key"),
-			ExpressionAttributeNames: map[string]*string{
-				"#key": keyName,
-			},
+	errs := make([]error, 0, len(resources))
+	for chunk := range slices.Chunk(resources, dynamoDBTableItemBatchSize) {
+		requests := make(map[string][]dynamodbtypes.WriteRequest)
+		for _, r := range chunk {
+			item := r.(*DynamoDBTableItem)
+			requests[*item.Table] = append(requests[*item.Table], dynamodbtypes.WriteRequest{
+				DeleteRequest: &dynamodbtypes.DeleteRequest{Key: item.key},
+			})
 		}
 
-		scanResp, scanErr := svc.Scan(params)
-		if scanErr != nil {
-			return nil, scanErr
+		resp, err := svc.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{RequestItems: requests})
+		for _, r := range chunk {
+			switch {
+			case err != nil:
+				errs = append(errs, err)
+			case r.(*DynamoDBTableItem).unprocessed(resp.UnprocessedItems):
+				errs = append(errs, errors.New("the item was not processed by the batch request"))
+			default:
+				errs = append(errs, nil)
+			}
 		}
+	}
 
-		for _, itemMap := range scanResp.Items {
-			var keyValue string
+	return errs
+}
 
-			for _, value := range itemMap {
-				trimmedValue := strings.TrimSpace(value.String())
-				keyValue = string([]rune(trimmedValue)[8:(len([]rune(trimmedValue)) - 3)])
-			}
+func listDynamoDBTableNames(ctx context.Context, svc *dynamodb.Client) ([]string, error) {
+	var tables []string
 
-			resources = append(resources, &DynamoDBTableItem{
-				svc:      svc,
-				id:       itemMap,
-				table:    dynamoTable,
-				keyName:  aws.StringValue(keyName),
-				keyValue: keyValue,
-			})
+	paginator := dynamodb.NewListTablesPaginator(svc, &dynamodb.ListTablesInput{})
+	for paginator.HasMorePages() {
+		resp, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
 		}
+
+		tables = append(tables, resp.TableNames...)
 	}
 
-	return resources, nil
+	return tables, nil
 }
 
-type DynamoDBTableItem struct {
-	svc      *dynamodb.DynamoDB
-	id       map[string]*dynamodb.AttributeValue
-	table    *DynamoDBTable
-	keyName  string
-	keyValue string
+// dynamoDBTableKey is the primary key of a table, the range key is empty for tables without a sort key. Whether the
+// table is backed up before it is removed is kept with it, the items of such a table are removed with the table.
+type dynamoDBTableKey struct {
+	TableARN string
+	HashKey  string
+	RangeKey string
+	BackedUp bool
 }
 
-func (i *DynamoDBTableItem) Remove(_ context.Context) error {
-	params := &dynamodb.DeleteItemInput{
-		Key:       i.id,
-		TableName: i.table.Name,
+// listDynamoDBTableTags returns the tags of a table, they are needed to resolve the settings of the table.
+func listDynamoDBTableTags(ctx context.Context, svc *dynamodb.Client, tableARN string) (map[string]string, error) {
+	tags := make(map[string]string)
+
+	params := &dynamodb.ListTagsOfResourceInput{
+		ResourceArn: aws.String(tableARN),
 	}
 
-	_, err := i.svc.DeleteItem(params)
+	for {
+		resp, err := svc.ListTagsOfResource(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, tag := range resp.Tags {
+			tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+		}
+
+		if resp.NextToken == nil {
+			break
+		}
+
+		params.NextToken = resp.NextToken
+	}
+
+	return tags, nil
+}
+
+func describeDynamoDBTableKey(ctx context.Context, svc *dynamodb.Client, table string) (*dynamoDBTableKey, error) {
+	resp, err := svc.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(table),
+	})
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	key := &dynamoDBTableKey{
+		TableARN: aws.ToString(resp.Table.TableArn),
+	}
+	for _, element := range resp.Table.KeySchema {
+		switch element.KeyType {
+		case dynamodbtypes.KeyTypeHash:
+			key.HashKey = aws.ToString(element.AttributeName)
+		case dynamodbtypes.KeyTypeRange:
+			key.RangeKey = aws.ToString(element.AttributeName)
+		}
+	}
+
+	if key.HashKey == "" {
+		return nil, fmt.Errorf("table %s has no partition key", table)
+	}
+
+	return key, nil
+}
+
+// projection returns the projection expression and the attribute names that select the primary key of the items.
+func (k *dynamoDBTableKey) projection() (*string, map[string]string) {
+	names := map[string]string{"#hash": k.HashKey}
+	projection := "#hash"
+
+	if k.RangeKey != "" {
+		names["#range"] = k.RangeKey
+		projection += ", #range"
+	}
+
+	return aws.String(projection), names
+}
+
+func scanDynamoDBTableSegment(
+	ctx context.Context, svc *dynamodb.Client, table string, key *dynamoDBTableKey, segment int32,
+	page func([]resource.Resource) error) error {
+	projection, names := key.projection()
+
+	paginator := dynamodb.NewScanPaginator(svc, &dynamodb.ScanInput{
+		TableName:                aws.String(table),
+		ProjectionExpression:     projection,
+		ExpressionAttributeNames: names,
+		Segment:                  aws.Int32(segment),
+		TotalSegments:            aws.Int32(dynamoDBTableItemScanSegments),
+	})
+
+	for paginator.HasMorePages() {
+		resp, err := paginator.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+
+		resources := make([]resource.Resource, 0, len(resp.Items))
+		for _, item := range resp.Items {
+			resources = append(resources, newDynamoDBTableItem(svc, table, key, item))
+		}
+
+		if err := page(resources); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func newDynamoDBTableItem(
+	svc *dynamodb.Client, table string, key *dynamoDBTableKey, item map[string]dynamodbtypes.AttributeValue,
+) *DynamoDBTableItem {
+	r := &DynamoDBTableItem{
+		svc:      svc,
+		key:      item,
+		backedUp: key.BackedUp,
+		Table:    aws.String(table),
+		KeyName:  aws.String(key.HashKey),
+	}
+
+	r.KeyValue, r.KeyType = dynamoDBAttributeValue(item[key.HashKey])
+
+	if key.RangeKey != "" {
+		r.RangeKeyName = aws.String(key.RangeKey)
+		r.RangeKeyValue, r.RangeKeyType = dynamoDBAttributeValue(item[key.RangeKey])
+	}
+
+	return r
+}
+
+// dynamoDBAttributeValue returns the value and the type of a key attribute. Numbers are returned as they are stored,
+// binary values base64 encoded.
+func dynamoDBAttributeValue(value dynamodbtypes.AttributeValue) (*string, *string) {
+	switch v := value.(type) {
+	case *dynamodbtypes.AttributeValueMemberS:
+		return aws.String(v.Value), aws.String(string(dynamodbtypes.ScalarAttributeTypeS))
+	case *dynamodbtypes.AttributeValueMemberN:
+		return aws.String(v.Value), aws.String(string(dynamodbtypes.ScalarAttributeTypeN))
+	case *dynamodbtypes.AttributeValueMemberB:
+		return aws.String(base64.StdEncoding.EncodeToString(v.Value)), aws.String(string(dynamodbtypes.ScalarAttributeTypeB))
+	}
+
+	return nil, nil
+}
+
+type DynamoDBTableItem struct {
+	svc      *dynamodb.Client
+	key      map[string]dynamodbtypes.AttributeValue
+	backedUp bool
+
+	Table         *string `description:"The name of the table of the item"`
+	KeyName       *string `description:"The name of the partition key of the table"`
+	KeyValue      *string `description:"The value of the partition key, binary values are base64 encoded"`
+	KeyType       *string `description:"The type of the partition key, S for string, N for number or B for binary"`
+	RangeKeyName  *string `description:"The name of the sort key of the table, if the table has one"`
+	RangeKeyValue *string `description:"The value of the sort key, binary values are base64 encoded"`
+	RangeKeyType  *string `description:"The type of the sort key, S for string, N for number or B for binary"`
+}
+
+// Filter filters the items of tables that are backed up before they are removed. The items are removed with the table
+// once it has been exported, removing them first would export an empty table.
+func (i *DynamoDBTableItem) Filter() error {
+	if i.backedUp {
+		return errors.New("the table is backed up before it is removed, the item is removed with it")
 	}
 
 	return nil
 }
 
+func (i *DynamoDBTableItem) Remove(ctx context.Context) error {
+	_, err := i.svc.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		Key:       i.key,
+		TableName: i.Table,
+	})
+
+	return err
+}
+
+// unprocessed returns true when the delete request of the item is among the unprocessed items of a batch request.
+func (i *DynamoDBTableItem) unprocessed(unprocessed map[string][]dynamodbtypes.WriteRequest) bool {
+	for _, request := range unprocessed[*i.Table] {
+		if request.DeleteRequest == nil {
+			continue
+		}
+
+		if dynamoDBKeyString(request.DeleteRequest.Key) == dynamoDBKeyString(i.key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// dynamoDBKeyString returns a string that identifies a primary key, for comparing keys.
+func dynamoDBKeyString(key map[string]dynamodbtypes.AttributeValue) string {
+	names := make([]string, 0, len(key))
+	for name := range key {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		value, valueType := dynamoDBAttributeValue(key[name])
+		parts = append(parts, fmt.Sprintf("%s=%s:%s", name, aws.ToString(valueType), aws.ToString(value)))
+	}
+
+	return strings.Join(parts, ",")
+}
+
 func (i *DynamoDBTableItem) Properties() types.Properties {
-	properties := types.NewProperties()
-	properties.Set("Table", i.table)
-	properties.Set("KeyName", i.keyName)
-	properties.Set("KeyValue", i.keyValue)
-	return properties
+	return types.NewPropertiesFromStruct(i)
 }
 
 func (i *DynamoDBTableItem) String() string {
-	return fmt.Sprintf("%s -> %s", i.table.String(), i.keyValue)
+	if i.RangeKeyName != nil {
+		return fmt.Sprintf("%s -> %s, %s", *i.Table, aws.ToString(i.KeyValue), aws.ToString(i.RangeKeyValue))
+	}
+
+	return fmt.Sprintf("%s -> %s", *i.Table, aws.ToString(i.KeyValue))
 }
@@ -0,0 +1,194 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamodbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+	"github.com/ekristen/libnuke/pkg/resource"
+	libsettings "github.com/ekristen/libnuke/pkg/settings"
+
+	"github.com/ekristen/aws-nuke/v3/pkg/nuke"
+)
+
+// newDynamoDBTestServer returns a server for a table with a composite key, the items of the first segment are
+// returned in two pages. The key with the sort key "2" is never processed by BatchWriteItem.
+func newDynamoDBTestServer(t *testing.T) (*httptest.Server, *[]string) {
+	var lock sync.Mutex
+	var operations []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		operation := strings.TrimPrefix(r.Header.Get("X-Amz-Target"), "DynamoDB_20120810.")
+
+		var input map[string]interface{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&input))
+
+		lock.Lock()
+		operations = append(operations, operation)
+		lock.Unlock()
+
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+
+		switch operation {
+		case "ListTables":
+			_, _ = w.Write([]byte(`{"TableNames":["orders"]}`))
+		case "DescribeTable":
+			_, _ = w.Write([]byte(`{"Table":{"TableName":"orders",` +
+				`"TableArn":"arn:aws:dynamodb:us-east-1:123456789012:table/orders","KeySchema":[` +
+				`{"AttributeName":"pk","KeyType":"HASH"},{"AttributeName":"sk","KeyType":"RANGE"}]}}`))
+		case "Scan":
+			assert.Equal(t, "#hash, #range", input["ProjectionExpression"])
+
+			switch {
+			case input["Segment"] != float64(0):
+				_, _ = w.Write([]byte(`{"Items":[]}`))
+			case input["ExclusiveStartKey"] == nil:
+				_, _ = w.Write([]byte(`{"Items":[{"pk":{"S":"customer"},"sk":{"N":"1"}},` +
+					`{"pk":{"S":"customer"},"sk":{"N":"2"}}],` +
+					`"LastEvaluatedKey":{"pk":{"S":"customer"},"sk":{"N":"2"}}}`))
+			default:
+				_, _ = w.Write([]byte(`{"Items":[{"pk":{"S":"customer"},"sk":{"N":"3"}}]}`))
+			}
+		case "ListTagsOfResource":
+			assert.Equal(t, "arn:aws:dynamodb:us-east-1:123456789012:table/orders", input["ResourceArn"])
+			_, _ = w.Write([]byte(`{"Tags":[{"Key":"backup","Value":"true"}]}`))
+		case "BatchWriteItem":
+			_, _ = w.Write([]byte(`{"UnprocessedItems":{"orders":[` +
+				`{"DeleteRequest":{"Key":{"pk":{"S":"customer"},"sk":{"N":"2"}}}}]}}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+
+	return server, &operations
+}
+
+func newDynamoDBTestOpts(url string) *nuke.ListerOpts {
+	return &nuke.ListerOpts{
+		Config: &aws.Config{
+			Region:       "us-east-1",
+			Credentials:  aws.AnonymousCredentials{},
+			BaseEndpoint: aws.String(url),
+		},
+		Cache: nuke.NewListCache(),
+	}
+}
+
+func TestDynamoDBTableItem_ListPages(t *testing.T) {
+	server, _ := newDynamoDBTestServer(t)
+	defer server.Close()
+
+	lister := &DynamoDBTableItemLister{}
+
+	var pages int
+	var resources []resource.Resource
+	err := lister.ListPages(context.TODO(), newDynamoDBTestOpts(server.URL), func(page []resource.Resource) error {
+		pages++
+		resources = append(resources, page...)
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 5, pages)
+	require.Len(t, resources, 3)
+
+	var names []string
+	for _, r := range resources {
+		names = append(names, r.(*DynamoDBTableItem).String())
+		assert.NoError(t, r.(*DynamoDBTableItem).Filter())
+	}
+	assert.ElementsMatch(t, []string{"orders -> customer, 1", "orders -> customer, 2", "orders -> customer, 3"}, names)
+}
+
+func TestDynamoDBTableItem_RemoveBatch(t *testing.T) {
+	server, operations := newDynamoDBTestServer(t)
+	defer server.Close()
+
+	key := &dynamoDBTableKey{HashKey: "pk", RangeKey: "sk"}
+	resources := []resource.Resource{
+		newDynamoDBTableItem(nil, "orders", key, map[string]dynamodbtypes.AttributeValue{
+			"pk": &dynamodbtypes.AttributeValueMemberS{Value: "customer"},
+			"sk": &dynamodbtypes.AttributeValueMemberN{Value: "1"},
+		}),
+		newDynamoDBTableItem(nil, "orders", key, map[string]dynamodbtypes.AttributeValue{
+			"pk": &dynamodbtypes.AttributeValueMemberS{Value: "customer"},
+			"sk": &dynamodbtypes.AttributeValueMemberN{Value: "2"},
+		}),
+	}
+
+	lister := &DynamoDBTableItemLister{}
+	errs := lister.RemoveBatch(context.TODO(), newDynamoDBTestOpts(server.URL), resources)
+
+	require.Len(t, errs, 2)
+	assert.NoError(t, errs[0])
+	assert.EqualError(t, errs[1], "the item was not processed by the batch request")
+	assert.Equal(t, []string{"BatchWriteItem"}, *operations)
+}
+
+func TestDynamoDBTableItem_FilterBackedUpTable(t *testing.T) {
+	server, operations := newDynamoDBTestServer(t)
+	defer server.Close()
+
+	// The settings of the table are resolved with its tags, as the run resolves them for the table itself
+	opts := newDynamoDBTestOpts(server.URL)
+	opts.ResolveSettings = func(item *queue.Item) *libsettings.Setting {
+		assert.Equal(t, DynamoDBTableResource, item.Type)
+
+		settings := &libsettings.Setting{}
+		if tag, _ := item.GetProperty("tag:backup"); tag == "true" {
+			settings.Set("BackupBeforeDelete", true)
+		}
+
+		return settings
+	}
+
+	lister := &DynamoDBTableItemLister{}
+
+	var resources []resource.Resource
+	require.NoError(t, lister.ListPages(context.TODO(), opts, func(page []resource.Resource) error {
+		resources = append(resources, page...)
+		return nil
+	}))
+
+	require.Len(t, resources, 3)
+	for _, r := range resources {
+		assert.EqualError(t, r.(*DynamoDBTableItem).Filter(),
+			"the table is backed up before it is removed, the item is removed with it")
+	}
+	assert.Contains(t, *operations, "ListTagsOfResource")
+}
+
+func TestDynamoDBTableItemProperties(t *testing.T) {
+	r := newDynamoDBTableItem(&dynamodb.Client{}, "orders", &dynamoDBTableKey{HashKey: "pk", RangeKey: "sk"},
+		map[string]dynamodbtypes.AttributeValue{
+			"pk": &dynamodbtypes.AttributeValueMemberB{Value: []byte("customer")},
+			"sk": &dynamodbtypes.AttributeValueMemberN{Value: "42"},
+		})
+
+	properties := r.Properties()
+	assert.Equal(t, "orders", properties.Get("Table"))
+	assert.Equal(t, "pk", properties.Get("KeyName"))
+	assert.Equal(t, "Y3VzdG9tZXI=", properties.Get("KeyValue"))
+	assert.Equal(t, "B", properties.Get("KeyType"))
+	assert.Equal(t, "sk", properties.Get("RangeKeyName"))
+	assert.Equal(t, "42", properties.Get("RangeKeyValue"))
+	assert.Equal(t, "N", properties.Get("RangeKeyType"))
+	assert.Equal(t, "orders -> Y3VzdG9tZXI=, 42", r.String())
+
+	simple := newDynamoDBTableItem(nil, "users", &dynamoDBTableKey{HashKey: "id"},
+		map[string]dynamodbtypes.AttributeValue{"id": &dynamodbtypes.AttributeValueMemberS{Value: "alice"}})
+	assert.Equal(t, "", simple.Properties().Get("RangeKeyName"))
+	assert.Equal(t, "users -> alice", simple.String())
+}
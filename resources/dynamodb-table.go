@@ -2,6 +2,8 @@ package resources
 
 import (
 	"context"
+	"fmt"
+	"path"
 
 	"github.com/gotidy/ptr"
 	"github.com/sirupsen/logrus"
@@ -9,6 +11,8 @@ import (
 	"github.com/aws/aws-sdk-go/service/dynamodb" //nolint:staticcheck
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
 
+	liberrors "github.com/ekristen/libnuke/pkg/errors"
+	"github.com/ekristen/libnuke/pkg/queue"
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
 	"github.com/ekristen/libnuke/pkg/settings"
@@ -27,6 +31,9 @@ func init() {
 		Lister:   &DynamoDBTableLister{},
 		Settings: []string{
 			"DisableDeletionProtection",
+			"BackupBeforeDelete",
+			"BackupS3Bucket",
+			"BackupS3Prefix",
 		},
 		DependsOn: []string{
 			DynamoDBTableItemResource,
@@ -73,6 +80,7 @@ func (l *DynamoDBTableLister) List(_ context.Context, o interface{}) ([]resource
 
 		resources = append(resources, &DynamoDBTable{
 			svc:        svc,
+			arn:        table.Table.TableArn,
 			id:         tableName,
 			protection: table.Table.DeletionProtectionEnabled,
 			Name:       tableName,
@@ -86,6 +94,8 @@ func (l *DynamoDBTableLister) List(_ context.Context, o interface{}) ([]resource
 type DynamoDBTable struct {
 	svc        dynamodbiface.DynamoDBAPI
 	settings   *settings.Setting
+	arn        *string
+	exportArn  *string
 	id         *string `property:"Identifier"` // TODO(v4): remove this
 	protection *bool
 	Name       *string
@@ -93,6 +103,12 @@ type DynamoDBTable struct {
 }
 
 func (r *DynamoDBTable) Remove(_ context.Context) error {
+	if r.settings.GetBool("BackupBeforeDelete") {
+		if err := r.Backup(); err != nil {
+			return err
+		}
+	}
+
 	if err := r.DisableDeletionProtection(); err != nil {
 		return err
 	}
@@ -109,6 +125,69 @@ func (r *DynamoDBTable) Remove(_ context.Context) error {
 	return nil
 }
 
+// Backup exports the table to S3 and holds the removal until the export has completed. Exports require point in time
+// recovery, it is enabled on the table first if necessary.
+func (r *DynamoDBTable) Backup() error {
+	bucket := r.settings.GetString("BackupS3Bucket")
+	if bucket == "" {
+		return fmt.Errorf("the BackupS3Bucket setting is required to back up a table")
+	}
+
+	if r.exportArn == nil {
+		backups, err := r.svc.DescribeContinuousBackups(&dynamodb.DescribeContinuousBackupsInput{
+			TableName: r.Name,
+		})
+		if err != nil {
+			return err
+		}
+
+		pitr := backups.ContinuousBackupsDescription.PointInTimeRecoveryDescription
+		if pitr == nil || ptr.ToString(pitr.PointInTimeRecoveryStatus) != dynamodb.PointInTimeRecoveryStatusEnabled {
+			if _, err := r.svc.UpdateContinuousBackups(&dynamodb.UpdateContinuousBackupsInput{
+				TableName: r.Name,
+				PointInTimeRecoverySpecification: &dynamodb.PointInTimeRecoverySpecification{
+					PointInTimeRecoveryEnabled: ptr.Bool(true),
+				},
+			}); err != nil {
+				return err
+			}
+
+			return liberrors.ErrHoldResource("waiting for point in time recovery to enable for backup")
+		}
+
+		export, err := r.svc.ExportTableToPointInTime(&dynamodb.ExportTableToPointInTimeInput{
+			TableArn:     r.arn,
+			S3Bucket:     ptr.String(bucket),
+			S3Prefix:     ptr.String(path.Join(r.settings.GetString("BackupS3Prefix"), ptr.ToString(r.Name))),
+			ExportFormat: ptr.String(dynamodb.ExportFormatDynamodbJson),
+		})
+		if err != nil {
+			return err
+		}
+
+		r.exportArn = export.ExportDescription.ExportArn
+
+		return liberrors.ErrHoldResource("waiting for backup export to complete")
+	}
+
+	export, err := r.svc.DescribeExport(&dynamodb.DescribeExportInput{
+		ExportArn: r.exportArn,
+	})
+	if err != nil {
+		return err
+	}
+
+	switch ptr.ToString(export.ExportDescription.ExportStatus) {
+	case dynamodb.ExportStatusCompleted:
+		return nil
+	case dynamodb.ExportStatusFailed:
+		r.exportArn = nil
+		return fmt.Errorf("backup export failed: %s", ptr.ToString(export.ExportDescription.FailureMessage))
+	default:
+		return liberrors.ErrHoldResource("waiting for backup export to complete")
+	}
+}
+
 func (r *DynamoDBTable) DisableDeletionProtection() error {
 	if !r.settings.GetBool("DisableDeletionProtection") {
 		return nil
@@ -133,6 +212,34 @@ func (r *DynamoDBTable) Settings(setting *settings.Setting) {
 	r.settings = setting
 }
 
+// dynamoDBTableBackedUp returns true when the table is backed up before it is removed, with the settings the run
+// resolves for the table by its name and tags. The items of a table that is backed up are removed with the table.
+func dynamoDBTableBackedUp(opts *nuke.ListerOpts, name string, tags map[string]string) bool {
+	if opts.ResolveSettings == nil {
+		return false
+	}
+
+	table := &DynamoDBTable{
+		id:   ptr.String(name),
+		Name: ptr.String(name),
+	}
+	for key, value := range tags {
+		table.Tags = append(table.Tags, &dynamodb.Tag{Key: ptr.String(key), Value: ptr.String(value)})
+	}
+
+	item := &queue.Item{
+		Resource: table,
+		Type:     DynamoDBTableResource,
+	}
+	if opts.Region != nil {
+		item.Owner = opts.Region.Name
+	}
+
+	setting := opts.ResolveSettings(item)
+
+	return setting != nil && setting.GetBool("BackupBeforeDelete")
+}
+
 func (r *DynamoDBTable) Properties() types.Properties {
 	return types.NewPropertiesFromStruct(r)
 }
@@ -11,6 +11,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws/awserr"       //nolint:staticcheck
 	"github.com/aws/aws-sdk-go/service/dynamodb" //nolint:staticcheck
 
+	liberrors "github.com/ekristen/libnuke/pkg/errors"
 	libsettings "github.com/ekristen/libnuke/pkg/settings"
 
 	"github.com/ekristen/aws-nuke/v3/mocks/mock_dynamodbiface"
@@ -163,3 +164,108 @@ func Test_Mock_DynamoDBTable_Remove_DeletionProtection_Disable(t *testing.T) {
 	err := resource.Remove(context.TODO())
 	a.Error(err)
 }
+
+func Test_Mock_DynamoDBTable_Remove_Backup(t *testing.T) {
+	a := assert.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSvc := mock_dynamodbiface.NewMockDynamoDBAPI(ctrl)
+
+	settings := &libsettings.Setting{}
+	settings.Set("BackupBeforeDelete", true)
+	settings.Set("BackupS3Bucket", "backup-bucket")
+	settings.Set("BackupS3Prefix", "dynamodb/")
+
+	resource := &DynamoDBTable{
+		svc:        mockSvc,
+		settings:   settings,
+		arn:        ptr.String("arn:aws:dynamodb:us-west-2:123456789012:table/ExampleTable"),
+		id:         ptr.String("ExampleTable"),
+		protection: ptr.Bool(false),
+		Name:       ptr.String("ExampleTable"),
+	}
+
+	// First pass, point in time recovery is disabled and has to be enabled before the export
+	mockSvc.EXPECT().DescribeContinuousBackups(&dynamodb.DescribeContinuousBackupsInput{
+		TableName: ptr.String("ExampleTable"),
+	}).Return(&dynamodb.DescribeContinuousBackupsOutput{
+		ContinuousBackupsDescription: &dynamodb.ContinuousBackupsDescription{
+			PointInTimeRecoveryDescription: &dynamodb.PointInTimeRecoveryDescription{
+				PointInTimeRecoveryStatus: ptr.String(dynamodb.PointInTimeRecoveryStatusDisabled),
+			},
+		},
+	}, nil)
+	mockSvc.EXPECT().UpdateContinuousBackups(&dynamodb.UpdateContinuousBackupsInput{
+		TableName: ptr.String("ExampleTable"),
+		PointInTimeRecoverySpecification: &dynamodb.PointInTimeRecoverySpecification{
+			PointInTimeRecoveryEnabled: ptr.Bool(true),
+		},
+	}).Return(&dynamodb.UpdateContinuousBackupsOutput{}, nil)
+
+	var holdErr liberrors.ErrHoldResource
+	a.ErrorAs(resource.Remove(context.TODO()), &holdErr)
+
+	// Second pass, the export is started
+	mockSvc.EXPECT().DescribeContinuousBackups(gomock.Any()).Return(&dynamodb.DescribeContinuousBackupsOutput{
+		ContinuousBackupsDescription: &dynamodb.ContinuousBackupsDescription{
+			PointInTimeRecoveryDescription: &dynamodb.PointInTimeRecoveryDescription{
+				PointInTimeRecoveryStatus: ptr.String(dynamodb.PointInTimeRecoveryStatusEnabled),
+			},
+		},
+	}, nil)
+	mockSvc.EXPECT().ExportTableToPointInTime(&dynamodb.ExportTableToPointInTimeInput{
+		TableArn:     ptr.String("arn:aws:dynamodb:us-west-2:123456789012:table/ExampleTable"),
+		S3Bucket:     ptr.String("backup-bucket"),
+		S3Prefix:     ptr.String("dynamodb/ExampleTable"),
+		ExportFormat: ptr.String(dynamodb.ExportFormatDynamodbJson),
+	}).Return(&dynamodb.ExportTableToPointInTimeOutput{
+		ExportDescription: &dynamodb.ExportDescription{
+			ExportArn: ptr.String("arn:export"),
+		},
+	}, nil)
+
+	a.ErrorAs(resource.Remove(context.TODO()), &holdErr)
+
+	// Third pass, the export is still in progress
+	mockSvc.EXPECT().DescribeExport(&dynamodb.DescribeExportInput{
+		ExportArn: ptr.String("arn:export"),
+	}).Return(&dynamodb.DescribeExportOutput{
+		ExportDescription: &dynamodb.ExportDescription{
+			ExportStatus: ptr.String(dynamodb.ExportStatusInProgress),
+		},
+	}, nil)
+
+	a.ErrorAs(resource.Remove(context.TODO()), &holdErr)
+
+	// Fourth pass, the export has completed and the table is removed
+	mockSvc.EXPECT().DescribeExport(gomock.Any()).Return(&dynamodb.DescribeExportOutput{
+		ExportDescription: &dynamodb.ExportDescription{
+			ExportStatus: ptr.String(dynamodb.ExportStatusCompleted),
+		},
+	}, nil)
+	mockSvc.EXPECT().DeleteTable(&dynamodb.DeleteTableInput{
+		TableName: ptr.String("ExampleTable"),
+	}).Return(&dynamodb.DeleteTableOutput{}, nil)
+
+	a.NoError(resource.Remove(context.TODO()))
+}
+
+func Test_Mock_DynamoDBTable_Remove_BackupNoBucket(t *testing.T) {
+	a := assert.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSvc := mock_dynamodbiface.NewMockDynamoDBAPI(ctrl)
+
+	settings := &libsettings.Setting{}
+	settings.Set("BackupBeforeDelete", true)
+
+	resource := &DynamoDBTable{
+		svc:      mockSvc,
+		settings: settings,
+		Name:     ptr.String("ExampleTable"),
+	}
+
+	a.EqualError(resource.Remove(context.TODO()), "the BackupS3Bucket setting is required to back up a table")
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/gotidy/ptr"
@@ -75,6 +76,35 @@ func (l *EC2InstanceLister) List(_ context.Context, o interface{}) ([]resource.R
 	return resources, nil
 }
 
+// RemoveBatch terminates the instances with TerminateInstances, up to 1000 at a time. When a request fails, because
+// one of the instances is protected, the instances of the request are removed one at a time.
+func (l *EC2InstanceLister) RemoveBatch(ctx context.Context, o interface{}, resources []resource.Resource) []error {
+	opts := o.(*nuke.ListerOpts)
+	svc := ec2.New(opts.Session)
+
+	errs := make([]error, 0, len(resources))
+	for chunk := range slices.Chunk(resources, 1000) {
+		ids := make([]*string, len(chunk))
+		for i, r := range chunk {
+			ids[i] = r.(*EC2Instance).ID
+		}
+
+		_, err := svc.DeleteTags(&ec2.DeleteTagsInput{Resources: ids})
+		if err == nil {
+			_, err = svc.TerminateInstances(&ec2.TerminateInstancesInput{InstanceIds: ids})
+		}
+
+		if err != nil {
+			errs = append(errs, nuke.RemoveEach(ctx, chunk)...)
+			continue
+		}
+
+		errs = append(errs, make([]error, len(chunk))...)
+	}
+
+	return errs
+}
+
 type EC2Instance struct {
 	svc      *ec2.EC2
 	settings *libsettings.Setting
@@ -98,6 +128,26 @@ func (i *EC2Instance) Filter() error {
 	return nil
 }
 
+// RefreshTags reads the tags of the instance again.
+func (i *EC2Instance) RefreshTags(_ context.Context) error {
+	resp, err := i.svc.DescribeTags(&ec2.DescribeTagsInput{
+		Filters: []*ec2.Filter{
+			{Name: ptr.String("resource-id"), Values: []*string{i.ID}},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	tags := make([]*ec2.Tag, len(resp.Tags))
+	for j, tag := range resp.Tags {
+		tags[j] = &ec2.Tag{Key: tag.Key, Value: tag.Value}
+	}
+	i.Tags = tags
+
+	return nil
+}
+
 func (i *EC2Instance) Remove(_ context.Context) error {
 	deleteTagsParams := &ec2.DeleteTagsInput{
 		Resources: []*string{i.ID},
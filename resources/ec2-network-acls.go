@@ -60,8 +60,14 @@ type EC2NetworkACL struct {
 	ownerID   *string
 }
 
+// IsDefault returns true for the default network ACL of a VPC.
+func (e *EC2NetworkACL) IsDefault() bool {
+	return ptr.ToBool(e.isDefault)
+}
+
+// Filter refuses the default network ACL even when default resources are included, it is only removed with its VPC.
 func (e *EC2NetworkACL) Filter() error {
-	if ptr.ToBool(e.isDefault) {
+	if e.IsDefault() {
 		return fmt.Errorf("cannot delete default VPC")
 	}
 
@@ -88,7 +94,7 @@ func (e *EC2NetworkACL) Properties() types.Properties {
 	}
 	properties.Set("ID", e.id)
 	properties.Set("OwnerID", e.ownerID)
-	return properties
+	return nuke.SetManagedProperties(properties, e)
 }
 
 func (e *EC2NetworkACL) String() string {
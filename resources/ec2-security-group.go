@@ -74,8 +74,14 @@ func (l *EC2SecurityGroupLister) List(_ context.Context, o interface{}) ([]resou
 	return resources, nil
 }
 
+// IsDefault returns true for the default security group of a VPC.
+func (r *EC2SecurityGroup) IsDefault() bool {
+	return ptr.ToString(r.Name) == "default"
+}
+
+// Filter refuses the default security group even when default resources are included, it is only removed with its VPC.
 func (r *EC2SecurityGroup) Filter() error {
-	if ptr.ToString(r.Name) == "default" {
+	if r.IsDefault() {
 		return fmt.Errorf("cannot delete group 'default'")
 	}
 
@@ -118,7 +124,7 @@ func (r *EC2SecurityGroup) Remove(_ context.Context) error {
 }
 
 func (r *EC2SecurityGroup) Properties() types.Properties {
-	return types.NewPropertiesFromStruct(r)
+	return nuke.SetManagedProperties(types.NewPropertiesFromStruct(r), r)
 }
 
 func (r *EC2SecurityGroup) String() string {
@@ -4,13 +4,16 @@ import (
 	"context"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
+	libsettings "github.com/ekristen/libnuke/pkg/settings"
 	"github.com/ekristen/libnuke/pkg/types"
 
+	"github.com/ekristen/aws-nuke/v3/pkg/backup"
 	"github.com/ekristen/aws-nuke/v3/pkg/nuke"
 )
 
@@ -22,7 +25,12 @@ func init() {
 		Scope:    nuke.Account,
 		Resource: &EC2Snapshot{},
 		Lister:   &EC2SnapshotLister{},
+		Settings: []string{
+			"IncludeFinalSnapshots",
+		},
 	})
+
+	nuke.RegisterSDKv2(EC2SnapshotResource)
 }
 
 type EC2SnapshotLister struct{}
@@ -77,6 +85,7 @@ func (l *EC2SnapshotLister) List(ctx context.Context, o interface{}) ([]resource
 
 type EC2Snapshot struct {
 	svc                 *ec2.Client
+	settings            *libsettings.Setting
 	SnapshotID          *string                 `description:"The ID of the snapshot"`
 	Description         *string                 `description:"The description for the snapshot"`
 	VolumeID            *string                 `description:"The ID of the volume that was used to create the snapshot"`
@@ -95,6 +104,25 @@ type EC2Snapshot struct {
 	Tags                *[]ec2types.Tag         `description:"The tags associated with the snapshot"`
 }
 
+// Filter filters the snapshots that were created before a volume was removed.
+func (r *EC2Snapshot) Settings(setting *libsettings.Setting) {
+	r.settings = setting
+}
+
+func (r *EC2Snapshot) Filter() error {
+	if r.Tags == nil {
+		return nil
+	}
+
+	for _, tag := range *r.Tags {
+		if aws.ToString(tag.Key) == backup.SourceVolumeTag {
+			return backup.FilterFinalSnapshot(true, r.settings)
+		}
+	}
+
+	return nil
+}
+
 func (r *EC2Snapshot) Remove(ctx context.Context) error {
 	params := &ec2.DeleteSnapshotInput{
 		SnapshotId: r.SnapshotID,
@@ -8,6 +8,10 @@ import (
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/gotidy/ptr"
 	"github.com/stretchr/testify/assert"
+
+	libsettings "github.com/ekristen/libnuke/pkg/settings"
+
+	"github.com/ekristen/aws-nuke/v3/pkg/backup"
 )
 
 func Test_EC2Snapshot_String(t *testing.T) {
@@ -150,3 +154,26 @@ func Test_EC2Snapshot_Properties_SpecialCharactersInTags(t *testing.T) {
 	a.Equal("prod/staging", properties.Get("tag:Environment:Stage"))
 	a.Equal("weekly/monthly", properties.Get("tag:Backup-Schedule"))
 }
+
+func Test_EC2Snapshot_Filter(t *testing.T) {
+	a := assert.New(t)
+
+	ec2Snapshot := EC2Snapshot{
+		SnapshotID: ptr.String("snap-1234567890abcdef0"),
+		Tags: &[]ec2types.Tag{
+			{Key: aws.String("Environment"), Value: aws.String("production")},
+		},
+	}
+	a.NoError(ec2Snapshot.Filter())
+
+	*ec2Snapshot.Tags = append(*ec2Snapshot.Tags, ec2types.Tag{
+		Key:   aws.String("aws-nuke:source-volume"),
+		Value: aws.String("vol-1234567890abcdef0"),
+	})
+	a.ErrorIs(ec2Snapshot.Filter(), backup.ErrFinalSnapshot)
+
+	setting := &libsettings.Setting{}
+	setting.Set("IncludeFinalSnapshots", true)
+	ec2Snapshot.Settings(setting)
+	a.NoError(ec2Snapshot.Filter())
+}
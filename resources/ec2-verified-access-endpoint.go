@@ -23,6 +23,8 @@ func init() {
 		Resource: &EC2VerifiedAccessEndpoint{},
 		Lister:   &EC2VerifiedAccessEndpointLister{},
 	})
+
+	nuke.RegisterSDKv2(EC2VerifiedAccessEndpointResource)
 }
 
 type EC2VerifiedAccessEndpointLister struct{}
@@ -25,6 +25,8 @@ func init() {
 			EC2VerifiedAccessEndpointResource,
 		},
 	})
+
+	nuke.RegisterSDKv2(EC2VerifiedAccessGroupResource)
 }
 
 type EC2VerifiedAccessGroupLister struct{}
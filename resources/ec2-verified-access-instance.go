@@ -26,6 +26,8 @@ func init() {
 			EC2VerifiedAccessEndpointResource,
 		},
 	})
+
+	nuke.RegisterSDKv2(EC2VerifiedAccessInstanceResource)
 }
 
 type EC2VerifiedAccessInstanceLister struct{}
@@ -23,6 +23,8 @@ func init() {
 		Resource: &EC2VerifiedAccessTrustProvider{},
 		Lister:   &EC2VerifiedAccessTrustProviderLister{},
 	})
+
+	nuke.RegisterSDKv2(EC2VerifiedAccessTrustProviderResource)
 }
 
 type EC2VerifiedAccessTrustProviderLister struct{}
@@ -2,15 +2,21 @@ package resources
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 
+	liberrors "github.com/ekristen/libnuke/pkg/errors"
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
+	libsettings "github.com/ekristen/libnuke/pkg/settings"
 	"github.com/ekristen/libnuke/pkg/types"
 
+	"github.com/ekristen/aws-nuke/v3/pkg/backup"
 	"github.com/ekristen/aws-nuke/v3/pkg/nuke"
 )
 
@@ -22,7 +28,12 @@ func init() {
 		Scope:    nuke.Account,
 		Resource: &EC2Volume{},
 		Lister:   &EC2VolumeLister{},
+		Settings: []string{
+			"BackupBeforeDelete",
+		},
 	})
+
+	nuke.RegisterSDKv2(EC2VolumeResource)
 }
 
 type EC2VolumeLister struct{}
@@ -71,6 +82,8 @@ func (l *EC2VolumeLister) List(ctx context.Context, o interface{}) ([]resource.R
 
 type EC2Volume struct {
 	svc                *ec2.Client
+	settings           *libsettings.Setting
+	snapshotID         *string
 	VolumeID           *string               `description:"The ID of the EBS volume"`
 	VolumeType         *ec2types.VolumeType  `description:"The volume type (gp2, gp3, io1, io2, st1, sc1, standard)"`
 	State              *ec2types.VolumeState `description:"The state of the volume (creating, available, in-use, deleting, deleted, error)"`
@@ -85,7 +98,37 @@ type EC2Volume struct {
 	Tags               *[]ec2types.Tag       `description:"The tags associated with the EBS volume"`
 }
 
+func (r *EC2Volume) Settings(settings *libsettings.Setting) {
+	r.settings = settings
+}
+
+// RefreshTags reads the tags of the volume again.
+func (r *EC2Volume) RefreshTags(ctx context.Context) error {
+	resp, err := r.svc.DescribeTags(ctx, &ec2.DescribeTagsInput{
+		Filters: []ec2types.Filter{
+			{Name: aws.String("resource-id"), Values: []string{*r.VolumeID}},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	tags := make([]ec2types.Tag, len(resp.Tags))
+	for i, tag := range resp.Tags {
+		tags[i] = ec2types.Tag{Key: tag.Key, Value: tag.Value}
+	}
+	r.Tags = &tags
+
+	return nil
+}
+
 func (r *EC2Volume) Remove(ctx context.Context) error {
+	if r.settings.GetBool("BackupBeforeDelete") {
+		if err := r.backup(ctx); err != nil {
+			return err
+		}
+	}
+
 	params := &ec2.DeleteVolumeInput{
 		VolumeId: r.VolumeID,
 	}
@@ -101,3 +144,56 @@ func (r *EC2Volume) Properties() types.Properties {
 func (r *EC2Volume) String() string {
 	return *r.VolumeID
 }
+
+// backup creates a snapshot of the volume and holds the removal until the snapshot has completed
+func (r *EC2Volume) backup(ctx context.Context) error {
+	if r.snapshotID == nil {
+		tags := []ec2types.Tag{
+			{Key: aws.String(backup.SourceVolumeTag), Value: r.VolumeID},
+		}
+		if r.Tags != nil {
+			for _, tag := range *r.Tags {
+				if !strings.HasPrefix(aws.ToString(tag.Key), "aws:") {
+					tags = append(tags, tag)
+				}
+			}
+		}
+
+		resp, err := r.svc.CreateSnapshot(ctx, &ec2.CreateSnapshotInput{
+			VolumeId:    r.VolumeID,
+			Description: aws.String(fmt.Sprintf("aws-nuke final snapshot of %s", aws.ToString(r.VolumeID))),
+			TagSpecifications: []ec2types.TagSpecification{
+				{ResourceType: ec2types.ResourceTypeSnapshot, Tags: tags},
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		r.snapshotID = resp.SnapshotId
+
+		return liberrors.ErrHoldResource("waiting for backup snapshot to complete")
+	}
+
+	resp, err := r.svc.DescribeSnapshots(ctx, &ec2.DescribeSnapshotsInput{
+		SnapshotIds: []string{aws.ToString(r.snapshotID)},
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(resp.Snapshots) == 0 {
+		r.snapshotID = nil
+		return fmt.Errorf("backup snapshot not found")
+	}
+
+	switch resp.Snapshots[0].State {
+	case ec2types.SnapshotStateCompleted:
+		return nil
+	case ec2types.SnapshotStateError:
+		r.snapshotID = nil
+		return fmt.Errorf("backup snapshot failed: %s", aws.ToString(resp.Snapshots[0].StateMessage))
+	default:
+		return liberrors.ErrHoldResource("waiting for backup snapshot to complete")
+	}
+}
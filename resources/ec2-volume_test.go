@@ -1,10 +1,14 @@
 package resources
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/gotidy/ptr"
 	"github.com/stretchr/testify/assert"
@@ -135,3 +139,29 @@ func Test_EC2Volume_Properties_SpecialCharactersInTags(t *testing.T) {
 	a.Equal("prod/staging", properties.Get("tag:Environment:Stage"))
 	a.Equal("dev-team", properties.Get("tag:Cost-Center"))
 }
+
+func Test_EC2Volume_RefreshTags(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, "DescribeTags", r.Form.Get("Action"))
+		assert.Equal(t, "vol-1234567890abcdef0", r.Form.Get("Filter.1.Value.1"))
+
+		w.Header().Set("Content-Type", "text/xml")
+		_, _ = w.Write([]byte(`<DescribeTagsResponse xmlns="http://ec2.amazonaws.com/doc/2016-11-15/"><tagSet>` +
+			`<item><resourceId>vol-1234567890abcdef0</resourceId><resourceType>volume</resourceType>` +
+			`<key>do-not-nuke</key><value>true</value></item></tagSet></DescribeTagsResponse>`))
+	}))
+	defer server.Close()
+
+	ec2Volume := EC2Volume{
+		svc: ec2.NewFromConfig(aws.Config{
+			Region:       "us-east-1",
+			Credentials:  aws.AnonymousCredentials{},
+			BaseEndpoint: aws.String(server.URL),
+		}),
+		VolumeID: ptr.String("vol-1234567890abcdef0"),
+	}
+
+	assert.NoError(t, ec2Volume.RefreshTags(context.TODO()))
+	assert.Equal(t, "true", ec2Volume.Properties().Get("tag:do-not-nuke"))
+}
@@ -36,6 +36,8 @@ func init() {
 		Resource: &ECSTaskDefinition{},
 		Lister:   &ECSTaskDefinitionLister{},
 	})
+
+	nuke.RegisterSDKv2(ECSTaskDefinitionResource)
 }
 
 type ECSTaskDefinitionLister struct{}
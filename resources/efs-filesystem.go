@@ -24,6 +24,8 @@ func init() {
 		Resource: &EFSFileSystem{},
 		Lister:   &EFSFileSystemLister{},
 	})
+
+	nuke.RegisterSDKv2(EFSFileSystemResource)
 }
 
 type EFSFileSystemLister struct{}
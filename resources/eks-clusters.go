@@ -29,6 +29,8 @@ func init() {
 			"DisableDeletionProtection",
 		},
 	})
+
+	nuke.RegisterSDKv2(EKSClusterResource)
 }
 
 type EKSClusterLister struct{}
@@ -31,13 +31,13 @@ func (l *IAMGroupPolicyLister) List(_ context.Context, o interface{}) ([]resourc
 	opts := o.(*nuke.ListerOpts)
 
 	svc := iam.New(opts.Session)
-	resp, err := svc.ListGroups(nil)
+	groups, err := ListCachedIAMGroups(opts, svc)
 	if err != nil {
 		return nil, err
 	}
 
 	resources := make([]resource.Resource, 0)
-	for _, group := range resp.Groups {
+	for _, group := range groups {
 		resp, err := svc.ListGroupPolicies(
 			&iam.ListGroupPoliciesInput{
 				GroupName: group.GroupName,
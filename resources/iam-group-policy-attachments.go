@@ -36,13 +36,13 @@ func (l *IAMGroupPolicyAttachmentLister) List(_ context.Context, o interface{})
 
 	svc := iam.New(opts.Session)
 
-	resp, err := svc.ListGroups(nil)
+	groups, err := ListCachedIAMGroups(opts, svc)
 	if err != nil {
 		return nil, err
 	}
 
 	resources := make([]resource.Resource, 0)
-	for _, role := range resp.Groups {
+	for _, role := range groups {
 		resp, err := svc.ListAttachedGroupPolicies(
 			&iam.ListAttachedGroupPoliciesInput{
 				GroupName: role.GroupName,
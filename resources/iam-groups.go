@@ -62,6 +62,27 @@ func (e *IAMGroup) Properties() types.Properties {
 
 // --------------
 
+// ListIAMGroups retrieves a base list of groups
+func ListIAMGroups(svc iamiface.IAMAPI) ([]*iam.Group, error) {
+	var groups []*iam.Group
+	if err := svc.ListGroupsPages(nil, func(page *iam.ListGroupsOutput, lastPage bool) bool {
+		groups = append(groups, page.Groups...)
+		return true
+	}); err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+// ListCachedIAMGroups retrieves the base list of groups, the list is shared by the listers of the resource types of
+// groups until a group is removed.
+func ListCachedIAMGroups(opts *nuke.ListerOpts, svc iamiface.IAMAPI) ([]*iam.Group, error) {
+	return nuke.Cached(opts, nuke.ListCacheKey{ResourceType: IAMGroupResource}, func() ([]*iam.Group, error) {
+		return ListIAMGroups(svc)
+	})
+}
+
 type IAMGroupLister struct{}
 
 func (l *IAMGroupLister) List(_ context.Context, o interface{}) ([]resource.Resource, error) {
@@ -45,12 +45,12 @@ func (l *IAMLoginProfileLister) List(_ context.Context, o interface{}) ([]resour
 		svc = iam.New(opts.Session)
 	}
 
-	resp, err := svc.ListUsers(nil)
+	users, err := ListCachedIAMUsers(opts, svc)
 	if err != nil {
 		return nil, err
 	}
 
-	for _, out := range resp.Users {
+	for _, out := range users {
 		lpresp, err := svc.GetLoginProfile(&iam.GetLoginProfileInput{UserName: out.UserName})
 		if err != nil {
 			var awsError awserr.Error
@@ -25,11 +25,14 @@ func Test_Mock_IAMLoginProfile_List(t *testing.T) {
 		UserName: ptr.String("login-profile:foobar"),
 	}
 
-	mockIAM.EXPECT().ListUsers(nil).Return(&iam.ListUsersOutput{
-		Users: []*iam.User{
-			user,
-		},
-	}, nil)
+	mockIAM.EXPECT().ListUsersPages(gomock.Any(), gomock.Any()).
+		Do(func(_ *iam.ListUsersInput, fn func(page *iam.ListUsersOutput, lastPage bool) bool) {
+			fn(&iam.ListUsersOutput{
+				Users: []*iam.User{
+					user,
+				},
+			}, true)
+		}).Return(nil)
 
 	now := time.Now().UTC()
 
@@ -2,6 +2,8 @@ package resources
 
 import (
 	"context"
+	"encoding/json"
+	"net/url"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -15,6 +17,7 @@ import (
 	"github.com/ekristen/libnuke/pkg/types"
 
 	"github.com/ekristen/aws-nuke/v3/pkg/nuke"
+	"github.com/ekristen/aws-nuke/v3/pkg/undo"
 )
 
 const IAMPolicyResource = "IAMPolicy"
@@ -34,6 +37,8 @@ func init() {
 			"IamPolicy",
 		},
 	})
+
+	undo.RegisterRestorer(IAMPolicyResource, restoreIAMPolicy)
 }
 
 type IAMPolicyLister struct{}
@@ -121,6 +126,68 @@ func (r *IAMPolicy) Remove(_ context.Context) error {
 	return nil
 }
 
+// iamPolicyUndo is the definition of a policy that is recorded in the undo log
+type iamPolicyUndo struct {
+	PolicyName     *string
+	Path           *string
+	Description    *string
+	PolicyDocument *string
+	Tags           []*iam.Tag
+}
+
+// UndoPayload returns the definition of the policy with the document of its default version
+func (r *IAMPolicy) UndoPayload(_ context.Context) (interface{}, error) {
+	policy, err := r.svc.GetPolicy(&iam.GetPolicyInput{
+		PolicyArn: r.ARN,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := r.svc.GetPolicyVersion(&iam.GetPolicyVersionInput{
+		PolicyArn: r.ARN,
+		VersionId: policy.Policy.DefaultVersionId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	document, err := url.QueryUnescape(aws.StringValue(version.PolicyVersion.Document))
+	if err != nil {
+		return nil, err
+	}
+
+	return &iamPolicyUndo{
+		PolicyName:     policy.Policy.PolicyName,
+		Path:           policy.Policy.Path,
+		Description:    policy.Policy.Description,
+		PolicyDocument: aws.String(document),
+		Tags:           policy.Policy.Tags,
+	}, nil
+}
+
+// restoreIAMPolicy recreates a policy from the undo log, the attachments of the policy are not restored
+func restoreIAMPolicy(_ context.Context, opts *nuke.ListerOpts, payload json.RawMessage) error {
+	p := &iamPolicyUndo{}
+	if err := json.Unmarshal(payload, p); err != nil {
+		return err
+	}
+
+	input := &iam.CreatePolicyInput{
+		PolicyName:     p.PolicyName,
+		Path:           p.Path,
+		Description:    p.Description,
+		PolicyDocument: p.PolicyDocument,
+	}
+	if len(p.Tags) > 0 {
+		input.Tags = p.Tags
+	}
+
+	_, err := iam.New(opts.Session).CreatePolicy(input)
+
+	return err
+}
+
 func (r *IAMPolicy) Properties() types.Properties {
 	return types.NewPropertiesFromStruct(r)
 }
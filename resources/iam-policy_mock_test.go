@@ -125,3 +125,48 @@ func Test_Mock_IAMPolicy_Properties(t *testing.T) {
 	a.Equal(now.Format(time.RFC3339), iamPolicy.Properties().Get("CreateDate"))
 	a.Equal("arn:foobar", iamPolicy.String())
 }
+
+func Test_Mock_IAMPolicy_UndoPayload(t *testing.T) {
+	a := assert.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockIAM := mock_iamiface.NewMockIAMAPI(ctrl)
+
+	iamPolicy := IAMPolicy{
+		svc:  mockIAM,
+		Name: ptr.String("foobar"),
+		ARN:  ptr.String("arn:aws:iam::123456789012:policy/foobar"),
+	}
+
+	mockIAM.EXPECT().GetPolicy(gomock.Eq(&iam.GetPolicyInput{
+		PolicyArn: iamPolicy.ARN,
+	})).Return(&iam.GetPolicyOutput{
+		Policy: &iam.Policy{
+			PolicyName:       ptr.String("foobar"),
+			Path:             ptr.String("/team/"),
+			Description:      ptr.String("test policy"),
+			DefaultVersionId: ptr.String("v3"),
+			Tags:             []*iam.Tag{{Key: ptr.String("owner"), Value: ptr.String("team")}},
+		},
+	}, nil)
+
+	mockIAM.EXPECT().GetPolicyVersion(gomock.Eq(&iam.GetPolicyVersionInput{
+		PolicyArn: iamPolicy.ARN,
+		VersionId: ptr.String("v3"),
+	})).Return(&iam.GetPolicyVersionOutput{
+		PolicyVersion: &iam.PolicyVersion{
+			Document: ptr.String("%7B%22Version%22%3A%222012-10-17%22%7D"),
+		},
+	}, nil)
+
+	payload, err := iamPolicy.UndoPayload(context.TODO())
+	a.Nil(err)
+	a.Equal(&iamPolicyUndo{
+		PolicyName:     ptr.String("foobar"),
+		Path:           ptr.String("/team/"),
+		Description:    ptr.String("test policy"),
+		PolicyDocument: ptr.String(`{"Version":"2012-10-17"}`),
+		Tags:           []*iam.Tag{{Key: ptr.String("owner"), Value: ptr.String("team")}},
+	}, payload)
+}
@@ -89,55 +89,40 @@ func (l *IAMRolePolicyAttachmentLister) List(_ context.Context, o interface{}) (
 	opts := o.(*nuke.ListerOpts)
 
 	svc := iam.New(opts.Session)
-	roleParams := &iam.ListRolesInput{}
 	resources := make([]resource.Resource, 0)
 
-	for {
-		roleResp, err := svc.ListRoles(roleParams)
-		if err != nil {
-			return nil, err
+	roles, err := ListCachedIAMRoles(opts, svc)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, role := range roles {
+		polParams := &iam.ListAttachedRolePoliciesInput{
+			RoleName: role.RoleName,
 		}
 
-		for _, listedRole := range roleResp.Roles {
-			role, err := GetIAMRole(svc, listedRole.RoleName)
+		for {
+			polResp, err := svc.ListAttachedRolePolicies(polParams)
 			if err != nil {
-				logrus.Errorf("Failed to get listed role %s: %v", *listedRole.RoleName, err)
-				continue
+				logrus.Errorf("failed to list attached policies for role %s: %v",
+					*role.RoleName, err)
+				break
 			}
-
-			polParams := &iam.ListAttachedRolePoliciesInput{
-				RoleName: role.RoleName,
+			for _, pol := range polResp.AttachedPolicies {
+				resources = append(resources, &IAMRolePolicyAttachment{
+					svc:        svc,
+					policyArn:  *pol.PolicyArn,
+					policyName: *pol.PolicyName,
+					role:       role,
+				})
 			}
 
-			for {
-				polResp, err := svc.ListAttachedRolePolicies(polParams)
-				if err != nil {
-					logrus.Errorf("failed to list attached policies for role %s: %v",
-						*role.RoleName, err)
-					break
-				}
-				for _, pol := range polResp.AttachedPolicies {
-					resources = append(resources, &IAMRolePolicyAttachment{
-						svc:        svc,
-						policyArn:  *pol.PolicyArn,
-						policyName: *pol.PolicyName,
-						role:       role,
-					})
-				}
-
-				if !*polResp.IsTruncated {
-					break
-				}
-
-				polParams.Marker = polResp.Marker
+			if !*polResp.IsTruncated {
+				break
 			}
-		}
 
-		if !*roleResp.IsTruncated {
-			break
+			polParams.Marker = polResp.Marker
 		}
-
-		roleParams.Marker = roleResp.Marker
 	}
 
 	return resources, nil
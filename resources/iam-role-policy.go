@@ -85,60 +85,45 @@ func (l *IAMRolePolicyLister) List(_ context.Context, o interface{}) ([]resource
 	opts := o.(*nuke.ListerOpts)
 
 	svc := iam.New(opts.Session)
-	roleParams := &iam.ListRolesInput{}
 	resources := make([]resource.Resource, 0)
 
-	for {
-		roles, err := svc.ListRoles(roleParams)
-		if err != nil {
-			return nil, err
+	roles, err := ListCachedIAMRoles(opts, svc)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, role := range roles {
+		polParams := &iam.ListRolePoliciesInput{
+			RoleName: role.RoleName,
 		}
 
-		for _, listedRole := range roles.Roles {
-			role, err := GetIAMRole(svc, listedRole.RoleName)
+		for {
+			policies, err := svc.ListRolePolicies(polParams)
 			if err != nil {
-				logrus.Errorf("Failed to get listed role %s: %v", *listedRole.RoleName, err)
-				continue
+				logrus.
+					WithError(err).
+					WithField("roleName", *role.RoleName).
+					Error("Failed to list policies")
+				break
 			}
 
-			polParams := &iam.ListRolePoliciesInput{
-				RoleName: role.RoleName,
+			for _, policyName := range policies.PolicyNames {
+				resources = append(resources, &IAMRolePolicy{
+					svc:        svc,
+					roleID:     *role.RoleId,
+					roleName:   *role.RoleName,
+					rolePath:   *role.Path,
+					policyName: *policyName,
+					roleTags:   role.Tags,
+				})
 			}
 
-			for {
-				policies, err := svc.ListRolePolicies(polParams)
-				if err != nil {
-					logrus.
-						WithError(err).
-						WithField("roleName", *role.RoleName).
-						Error("Failed to list policies")
-					break
-				}
-
-				for _, policyName := range policies.PolicyNames {
-					resources = append(resources, &IAMRolePolicy{
-						svc:        svc,
-						roleID:     *role.RoleId,
-						roleName:   *role.RoleName,
-						rolePath:   *role.Path,
-						policyName: *policyName,
-						roleTags:   role.Tags,
-					})
-				}
-
-				if !*policies.IsTruncated {
-					break
-				}
-
-				polParams.Marker = policies.Marker
+			if !*policies.IsTruncated {
+				break
 			}
-		}
 
-		if !*roles.IsTruncated {
-			break
+			polParams.Marker = policies.Marker
 		}
-
-		roleParams.Marker = roles.Marker
 	}
 
 	return resources, nil
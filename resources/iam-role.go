@@ -58,10 +58,17 @@ func (r *IAMRole) Settings(settings *libsettings.Setting) {
 	r.settings = settings
 }
 
-func (r *IAMRole) Filter() error {
-	if strings.HasPrefix(*r.Path, "/aws-service-role/") && !r.settings.GetBool("IncludeServiceLinkedRoles") {
-		return fmt.Errorf("cannot delete service roles")
+// IsManaged returns true for service-linked roles, which are managed by the AWS service they are linked to, unless the
+// deprecated IncludeServiceLinkedRoles setting is set. Use the global include-aws-managed setting instead.
+func (r *IAMRole) IsManaged() bool {
+	if r.settings != nil && r.settings.GetBool("IncludeServiceLinkedRoles") {
+		return false
 	}
+
+	return strings.HasPrefix(ptr.ToString(r.Path), "/aws-service-role/")
+}
+
+func (r *IAMRole) Filter() error {
 	if strings.HasPrefix(*r.Path, "/aws-reserved/sso.amazonaws.com/") {
 		return fmt.Errorf("cannot delete SSO roles")
 	}
@@ -124,7 +131,7 @@ func (r *IAMRole) Remove(_ context.Context) error {
 }
 
 func (r *IAMRole) Properties() types.Properties {
-	return types.NewPropertiesFromStruct(r)
+	return nuke.SetManagedProperties(types.NewPropertiesFromStruct(r), r)
 }
 
 func (r *IAMRole) String() string {
@@ -200,6 +207,40 @@ func GetIAMRole(svc iamiface.IAMAPI, roleName *string) (*iam.Role, error) {
 	return resp.Role, err
 }
 
+// ListCachedIAMRoles retrieves the roles with all their details, roles that cannot be retrieved are logged and skipped.
+// The list is shared by the listers of the resource types of roles until a role is removed.
+func ListCachedIAMRoles(opts *nuke.ListerOpts, svc iamiface.IAMAPI) ([]*iam.Role, error) {
+	return nuke.Cached(opts, nuke.ListCacheKey{ResourceType: IAMRoleResource}, func() ([]*iam.Role, error) {
+		var roles []*iam.Role
+		params := &iam.ListRolesInput{}
+
+		for {
+			resp, err := svc.ListRoles(params)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, listedRole := range resp.Roles {
+				role, err := GetIAMRole(svc, listedRole.RoleName)
+				if err != nil {
+					logrus.Errorf("Failed to get listed role %s: %v", *listedRole.RoleName, err)
+					continue
+				}
+
+				roles = append(roles, role)
+			}
+
+			if !*resp.IsTruncated {
+				break
+			}
+
+			params.Marker = resp.Marker
+		}
+
+		return roles, nil
+	})
+}
+
 // getLastUsedDate returns the last used date of the role
 func getLastUsedDate(role *iam.Role) *time.Time {
 	var lastUsedDate *time.Time
@@ -13,9 +13,11 @@ import (
 	"github.com/aws/aws-sdk-go/service/iam" //nolint:staticcheck
 
 	liberrors "github.com/ekristen/libnuke/pkg/errors"
+	"github.com/ekristen/libnuke/pkg/queue"
 	libsettings "github.com/ekristen/libnuke/pkg/settings"
 
 	"github.com/ekristen/aws-nuke/v3/mocks/mock_iamiface"
+	"github.com/ekristen/aws-nuke/v3/pkg/nuke"
 )
 
 func Test_Mock_IAMRole_List(t *testing.T) {
@@ -281,7 +283,7 @@ func Test_Mock_IAMRole_InProgress_HandleWait(t *testing.T) {
 	a.ErrorAs(err, &errWait)
 }
 
-func Test_Mock_IAMRole_Filter_ServiceLinked(t *testing.T) {
+func Test_Mock_IAMRole_IsManaged_ServiceLinked(t *testing.T) {
 	a := assert.New(t)
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -298,18 +300,20 @@ func Test_Mock_IAMRole_Filter_ServiceLinked(t *testing.T) {
 		Tags:     []*iam.Tag{},
 	}
 
-	err := iamRole.Filter()
-	a.NotNil(err, "should not be able to delete service linked roles")
+	a.True(iamRole.IsManaged(), "service linked roles should be managed")
+	a.Nil(iamRole.Filter(), "service linked roles are filtered as managed resources")
+	a.Equal(nuke.ErrAWSManaged, nuke.ManagedFilter(false, false)(&queue.Item{Resource: &iamRole}))
+	a.Nil(nuke.ManagedFilter(true, false)(&queue.Item{Resource: &iamRole}))
 
 	iamRole.settings.Set("IncludeServiceLinkedRoles", false)
-
-	err = iamRole.Filter()
-	a.NotNil(err, "should not be able to delete service linked roles")
+	a.True(iamRole.IsManaged(), "service linked roles should be managed")
 
 	iamRole.settings.Set("IncludeServiceLinkedRoles", true)
+	a.False(iamRole.IsManaged(), "the deprecated setting should include service linked roles")
 
-	err = iamRole.Filter()
-	a.Nil(err, "should be able to delete service linked roles")
+	iamRole.Path = ptr.String("/")
+	iamRole.settings.Set("IncludeServiceLinkedRoles", false)
+	a.False(iamRole.IsManaged(), "other roles should not be managed")
 }
 
 func Test_Mock_IAMRole_Properties(t *testing.T) {
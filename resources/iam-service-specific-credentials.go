@@ -5,7 +5,6 @@ import (
 	"fmt"
 
 	"github.com/gotidy/ptr"
-	"github.com/sirupsen/logrus"
 
 	"github.com/aws/aws-sdk-go/service/iam" //nolint:staticcheck
 	"github.com/aws/aws-sdk-go/service/iam/iamiface"
@@ -35,21 +34,15 @@ func (l *IAMServiceSpecificCredentialLister) List(ctx context.Context, o interfa
 
 	svc := iam.New(opts.Session)
 
-	userLister := &IAMUserLister{}
-	users, usersErr := userLister.List(ctx, o)
+	users, usersErr := ListCachedIAMUsers(opts, svc)
 	if usersErr != nil {
 		return nil, usersErr
 	}
 
 	resources := make([]resource.Resource, 0)
-	for _, userResource := range users {
-		user, ok := userResource.(*IAMUser)
-		if !ok {
-			logrus.Errorf("Unable to cast IAMUser.")
-			continue
-		}
+	for _, user := range users {
 		params := &iam.ListServiceSpecificCredentialsInput{
-			UserName: user.Name,
+			UserName: user.UserName,
 		}
 		serviceCredentials, err := svc.ListServiceSpecificCredentials(params)
 		if err != nil {
@@ -62,7 +55,7 @@ func (l *IAMServiceSpecificCredentialLister) List(ctx context.Context, o interfa
 				name:        credential.UserName,
 				serviceName: credential.ServiceName,
 				id:          credential.ServiceSpecificCredentialId,
-				userName:    user.Name,
+				userName:    user.UserName,
 			})
 		}
 	}
@@ -7,7 +7,6 @@ import (
 
 	"github.com/gotidy/ptr"
 
-	"github.com/aws/aws-sdk-go/aws"         //nolint:staticcheck
 	"github.com/aws/aws-sdk-go/service/iam" //nolint:staticcheck
 	"github.com/aws/aws-sdk-go/service/iam/iamiface"
 
@@ -37,39 +36,27 @@ func (l *IAMSigningCertificateLister) List(_ context.Context, o interface{}) ([]
 	svc := iam.New(opts.Session)
 	var resources []resource.Resource
 
-	params := &iam.ListUsersInput{
-		MaxItems: aws.Int64(100),
+	users, err := ListCachedIAMUsers(opts, svc)
+	if err != nil {
+		return nil, err
 	}
 
-	for {
-		resp, err := svc.ListUsers(params)
+	for _, out := range users {
+		resp, err := svc.ListSigningCertificates(&iam.ListSigningCertificatesInput{
+			UserName: out.UserName,
+		})
 		if err != nil {
 			return nil, err
 		}
 
-		for _, out := range resp.Users {
-			resp, err := svc.ListSigningCertificates(&iam.ListSigningCertificatesInput{
-				UserName: out.UserName,
+		for _, signingCert := range resp.Certificates {
+			resources = append(resources, &IAMSigningCertificate{
+				svc:           svc,
+				certificateID: signingCert.CertificateId,
+				userName:      signingCert.UserName,
+				status:        signingCert.Status,
 			})
-			if err != nil {
-				return nil, err
-			}
-
-			for _, signingCert := range resp.Certificates {
-				resources = append(resources, &IAMSigningCertificate{
-					svc:           svc,
-					certificateID: signingCert.CertificateId,
-					userName:      signingCert.UserName,
-					status:        signingCert.Status,
-				})
-			}
 		}
-
-		if resp.Marker == nil {
-			break
-		}
-
-		params.Marker = resp.Marker
 	}
 
 	return resources, nil
@@ -76,13 +76,13 @@ func (l *IAMUserAccessKeyLister) List(_ context.Context, o interface{}) ([]resou
 	opts := o.(*nuke.ListerOpts)
 
 	svc := iam.New(opts.Session)
-	resp, err := svc.ListUsers(nil)
+	users, err := ListCachedIAMUsers(opts, svc)
 	if err != nil {
 		return nil, err
 	}
 
 	resources := make([]resource.Resource, 0)
-	for _, role := range resp.Users {
+	for _, role := range users {
 		resp, err := svc.ListAccessKeys(
 			&iam.ListAccessKeysInput{
 				UserName: role.UserName,
@@ -65,13 +65,13 @@ func (l *IAMUserGroupAttachmentLister) List(_ context.Context, o interface{}) ([
 	opts := o.(*nuke.ListerOpts)
 
 	svc := iam.New(opts.Session)
-	resp, err := svc.ListUsers(nil)
+	users, err := ListCachedIAMUsers(opts, svc)
 	if err != nil {
 		return nil, err
 	}
 
 	resources := make([]resource.Resource, 0)
-	for _, role := range resp.Users {
+	for _, role := range users {
 		resp, err := svc.ListGroupsForUser(
 			&iam.ListGroupsForUserInput{
 				UserName: role.UserName,
@@ -71,13 +71,13 @@ func (l *IAMUserHTTPSGitCredentialLister) List(_ context.Context, o interface{})
 	opts := o.(*nuke.ListerOpts)
 
 	svc := iam.New(opts.Session)
-	resp, err := svc.ListUsers(nil)
+	users, err := ListCachedIAMUsers(opts, svc)
 	if err != nil {
 		return nil, err
 	}
 
 	resources := make([]resource.Resource, 0)
-	for _, role := range resp.Users {
+	for _, role := range users {
 		resp, err := svc.ListServiceSpecificCredentials(
 			&iam.ListServiceSpecificCredentialsInput{
 				UserName:    role.UserName,
@@ -44,7 +44,7 @@ func (l *IAMUserMFADeviceLister) List(_ context.Context, o interface{}) ([]resou
 		svc = iam.New(opts.Session)
 	}
 
-	allUsers, err := ListIAMUsers(svc)
+	allUsers, err := ListCachedIAMUsers(opts, svc)
 	if err != nil {
 		return nil, err
 	}
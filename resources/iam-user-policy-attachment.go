@@ -75,13 +75,13 @@ func (l *IAMUserPolicyAttachmentLister) List(_ context.Context, o interface{}) (
 
 	svc := iam.New(opts.Session)
 
-	resp, err := svc.ListUsers(nil)
+	users, err := ListCachedIAMUsers(opts, svc)
 	if err != nil {
 		return nil, err
 	}
 
 	resources := make([]resource.Resource, 0)
-	for _, user := range resp.Users {
+	for _, user := range users {
 		iamUser, err := GetIAMUser(svc, user.UserName)
 		if err != nil {
 			logrus.Errorf("Failed to get user %s: %v", *user.UserName, err)
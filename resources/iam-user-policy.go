@@ -56,13 +56,13 @@ func (l *IAMUserPolicyLister) List(_ context.Context, o interface{}) ([]resource
 
 	svc := iam.New(opts.Session)
 
-	users, err := svc.ListUsers(nil)
+	users, err := ListCachedIAMUsers(opts, svc)
 	if err != nil {
 		return nil, err
 	}
 
 	resources := make([]resource.Resource, 0)
-	for _, user := range users.Users {
+	for _, user := range users {
 		policies, err := svc.ListUserPolicies(&iam.ListUserPoliciesInput{
 			UserName: user.UserName,
 		})
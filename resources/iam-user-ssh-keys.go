@@ -31,13 +31,13 @@ func (l *IAMUserSSHPublicKeyLister) List(_ context.Context, o interface{}) ([]re
 	opts := o.(*nuke.ListerOpts)
 	svc := iam.New(opts.Session)
 
-	usersOutput, err := svc.ListUsers(nil)
+	users, err := ListCachedIAMUsers(opts, svc)
 	if err != nil {
 		return nil, err
 	}
 
 	var resources []resource.Resource
-	for _, user := range usersOutput.Users {
+	for _, user := range users {
 		listOutput, err := svc.ListSSHPublicKeys(&iam.ListSSHPublicKeysInput{
 			UserName: user.UserName,
 		})
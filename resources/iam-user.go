@@ -156,6 +156,14 @@ func GetIAMUser(svc iamiface.IAMAPI, userName *string) (*iam.User, error) {
 	return resp.User, err
 }
 
+// ListCachedIAMUsers retrieves the base list of users, the list is shared by the listers of the resource types of users
+// until a user is removed.
+func ListCachedIAMUsers(opts *nuke.ListerOpts, svc iamiface.IAMAPI) ([]*iam.User, error) {
+	return nuke.Cached(opts, nuke.ListCacheKey{ResourceType: IAMUserResource}, func() ([]*iam.User, error) {
+		return ListIAMUsers(svc)
+	})
+}
+
 // ListIAMUsers retrieves a base list of users
 func ListIAMUsers(svc iamiface.IAMAPI) ([]*iam.User, error) {
 	var users []*iam.User
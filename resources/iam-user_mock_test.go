@@ -14,6 +14,7 @@ import (
 	libsettings "github.com/ekristen/libnuke/pkg/settings"
 
 	"github.com/ekristen/aws-nuke/v3/mocks/mock_iamiface"
+	"github.com/ekristen/aws-nuke/v3/pkg/nuke"
 )
 
 func Test_Mock_IAMUser_List(t *testing.T) {
@@ -110,3 +111,37 @@ func Test_Mock_IAMUser_Properties(t *testing.T) {
 	a.Equal("bar", iamUser.Properties().Get("tag:foo"))
 	a.Equal("/foo", iamUser.Properties().Get("Path"))
 }
+
+func Test_Mock_ListCachedIAMUsers(t *testing.T) {
+	a := assert.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockIAM := mock_iamiface.NewMockIAMAPI(ctrl)
+
+	// The users are listed once for the listers of the resource types of users, and again after a user is removed
+	mockIAM.EXPECT().ListUsersPages(gomock.Any(), gomock.Any()).
+		Do(func(_ *iam.ListUsersInput, fn func(page *iam.ListUsersOutput, lastPage bool) bool) {
+			fn(&iam.ListUsersOutput{
+				Users: []*iam.User{
+					{
+						UserName: ptr.String("foo"),
+					},
+				},
+			}, true)
+		}).Return(nil).Times(2)
+
+	opts := &nuke.ListerOpts{Cache: nuke.NewListCache()}
+
+	for i := 0; i < 2; i++ {
+		users, err := ListCachedIAMUsers(opts, mockIAM)
+		a.Nil(err)
+		a.Len(users, 1)
+	}
+
+	opts.Cache.Invalidate(IAMUserResource)
+
+	users, err := ListCachedIAMUsers(opts, mockIAM)
+	a.Nil(err)
+	a.Len(users, 1)
+}
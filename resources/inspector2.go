@@ -22,6 +22,8 @@ func init() {
 		Resource: &Inspector2{},
 		Lister:   &Inspector2Lister{},
 	})
+
+	nuke.RegisterSDKv2(Inspector2Resource)
 }
 
 type Inspector2Lister struct{}
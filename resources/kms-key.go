@@ -139,13 +139,19 @@ func (r *KMSKey) Filter() error {
 		return fmt.Errorf("is already in %v state", state)
 	}
 
-	if ptr.ToString(r.Manager) == kms.KeyManagerTypeAws {
+	// AWS managed keys cannot be deleted, so they are filtered even when AWS-managed resources are included
+	if r.IsManaged() {
 		return fmt.Errorf("cannot delete AWS managed key")
 	}
 
 	return nil
 }
 
+// IsManaged returns true for keys that are managed by AWS on behalf of a service.
+func (r *KMSKey) IsManaged() bool {
+	return ptr.ToString(r.Manager) == kms.KeyManagerTypeAws
+}
+
 func (r *KMSKey) Remove(_ context.Context) error {
 	_, err := r.svc.ScheduleKeyDeletion(&kms.ScheduleKeyDeletionInput{
 		KeyId:               r.ID,
@@ -159,5 +165,5 @@ func (r *KMSKey) String() string {
 }
 
 func (r *KMSKey) Properties() types.Properties {
-	return types.NewPropertiesFromStruct(r)
+	return nuke.SetManagedProperties(types.NewPropertiesFromStruct(r), r)
 }
@@ -21,6 +21,8 @@ func init() {
 		Resource: &LakeFormationLocation{},
 		Lister:   &LakeFormationLocationLister{},
 	})
+
+	nuke.RegisterSDKv2(LakeFormationLocationResource)
 }
 
 type LakeFormationLocationLister struct{}
@@ -23,6 +23,8 @@ func init() {
 		Resource: &LakeFormationPermission{},
 		Lister:   &LakeFormationPermissionLister{},
 	})
+
+	nuke.RegisterSDKv2(LakeFormationPermissionResource)
 }
 
 type LakeFormationPermissionLister struct{}
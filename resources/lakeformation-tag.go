@@ -21,6 +21,8 @@ func init() {
 		Resource: &LakeFormationTag{},
 		Lister:   &LakeFormationTagLister{},
 	})
+
+	nuke.RegisterSDKv2(LakeFormationTagResource)
 }
 
 type LakeFormationTagLister struct{}
@@ -21,6 +21,8 @@ func init() {
 		Resource: &LambdaFunction{},
 		Lister:   &LambdaFunctionLister{},
 	})
+
+	nuke.RegisterSDKv2(LambdaFunctionResource)
 }
 
 type LambdaFunctionLister struct{}
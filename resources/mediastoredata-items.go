@@ -2,6 +2,7 @@ package resources
 
 import (
 	"context"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"                    //nolint:staticcheck
 	"github.com/aws/aws-sdk-go/service/mediastore"     //nolint:staticcheck
@@ -26,14 +27,17 @@ func init() {
 
 type MediaStoreDataItemsLister struct{}
 
-func (l *MediaStoreDataItemsLister) List(_ context.Context, o interface{}) ([]resource.Resource, error) {
+func (l *MediaStoreDataItemsLister) List(ctx context.Context, o interface{}) ([]resource.Resource, error) {
+	return nuke.ListPages(ctx, l, o)
+}
+
+// ListPages lists the items of every container, one page of up to 100 at a time.
+func (l *MediaStoreDataItemsLister) ListPages(
+	_ context.Context, o interface{}, page func([]resource.Resource) error) error {
 	opts := o.(*nuke.ListerOpts)
 
 	containerSvc := mediastore.New(opts.Session)
-	svc := mediastoredata.New(opts.Session)
-	svc.SigningName = "mediastore"
 
-	resources := make([]resource.Resource, 0)
 	var containers []*mediastore.Container
 
 	// list all containers
@@ -44,7 +48,7 @@ func (l *MediaStoreDataItemsLister) List(_ context.Context, o interface{}) ([]re
 	for {
 		output, err := containerSvc.ListContainers(containerParams)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
 		containers = append(containers, output.Containers...)
@@ -56,41 +60,58 @@ func (l *MediaStoreDataItemsLister) List(_ context.Context, o interface{}) ([]re
 		containerParams.NextToken = output.NextToken
 	}
 
-	// List all Items per Container
-	params := &mediastoredata.ListItemsInput{
-		MaxResults: aws.Int64(100),
-	}
-
+	// List all Items per Container, every container has its own endpoint
 	for _, container := range containers {
 		if container.Endpoint == nil {
 			continue
 		}
-		svc.Endpoint = *container.Endpoint
-		output, err := svc.ListItems(params)
-		if err != nil {
-			return nil, err
-		}
 
-		for _, item := range output.Items {
-			resources = append(resources, &MediaStoreDataItems{
-				svc:  svc,
-				path: item.Name,
-			})
-		}
+		svc := mediastoredata.New(opts.Session, &aws.Config{Endpoint: container.Endpoint})
+		svc.SigningName = "mediastore"
 
-		if output.NextToken == nil {
-			break
+		params := &mediastoredata.ListItemsInput{
+			MaxResults: aws.Int64(100),
 		}
 
-		params.NextToken = output.NextToken
+		for {
+			output, err := svc.ListItems(params)
+			if err != nil {
+				return err
+			}
+
+			resources := make([]resource.Resource, 0, len(output.Items))
+			for _, item := range output.Items {
+				resources = append(resources, &MediaStoreDataItems{
+					svc:          svc,
+					path:         item.Name,
+					lastModified: item.LastModified,
+				})
+			}
+
+			if err := page(resources); err != nil {
+				return err
+			}
+
+			if output.NextToken == nil {
+				break
+			}
+
+			params.NextToken = output.NextToken
+		}
 	}
 
-	return resources, nil
+	return nil
 }
 
 type MediaStoreDataItems struct {
-	svc  *mediastoredata.MediaStoreData
-	path *string
+	svc          *mediastoredata.MediaStoreData
+	path         *string
+	lastModified *time.Time
+}
+
+// LastModified returns when the item was last modified, items that are modified after the scan are not removed.
+func (f *MediaStoreDataItems) LastModified() *time.Time {
+	return f.lastModified
 }
 
 func (f *MediaStoreDataItems) Remove(_ context.Context) error {
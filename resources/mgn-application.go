@@ -28,6 +28,8 @@ func init() {
 		Resource: &MGNApplication{},
 		Lister:   &MGNApplicationLister{},
 	})
+
+	nuke.RegisterSDKv2(MGNApplicationResource)
 }
 
 type MGNApplicationLister struct{}
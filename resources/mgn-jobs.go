@@ -28,6 +28,8 @@ func init() {
 		Resource: &MGNJob{},
 		Lister:   &MGNJobLister{},
 	})
+
+	nuke.RegisterSDKv2(MGNJobResource)
 }
 
 type MGNJobLister struct{}
@@ -28,6 +28,8 @@ func init() {
 		Resource: &MGNLaunchConfigurationTemplate{},
 		Lister:   &MGNLaunchConfigurationTemplateLister{},
 	})
+
+	nuke.RegisterSDKv2(MGNLaunchConfigurationTemplateResource)
 }
 
 type MGNLaunchConfigurationTemplateLister struct{}
@@ -28,6 +28,8 @@ func init() {
 		Resource: &MGNReplicationConfigurationTemplate{},
 		Lister:   &MGNReplicationConfigurationTemplateLister{},
 	})
+
+	nuke.RegisterSDKv2(MGNReplicationConfigurationTemplateResource)
 }
 
 type MGNReplicationConfigurationTemplateLister struct{}
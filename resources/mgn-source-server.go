@@ -28,6 +28,8 @@ func init() {
 		Resource: &MGNSourceServer{},
 		Lister:   &MGNSourceServerLister{},
 	})
+
+	nuke.RegisterSDKv2(MGNSourceServerResource)
 }
 
 type MGNSourceServerLister struct{}
@@ -28,6 +28,8 @@ func init() {
 		Resource: &MGNWave{},
 		Lister:   &MGNWaveLister{},
 	})
+
+	nuke.RegisterSDKv2(MGNWaveResource)
 }
 
 type MGNWaveLister struct{}
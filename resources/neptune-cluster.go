@@ -3,6 +3,7 @@ package resources
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/gotidy/ptr"
 
@@ -14,6 +15,7 @@ import (
 	libsettings "github.com/ekristen/libnuke/pkg/settings"
 	"github.com/ekristen/libnuke/pkg/types"
 
+	"github.com/ekristen/aws-nuke/v3/pkg/backup"
 	"github.com/ekristen/aws-nuke/v3/pkg/nuke"
 )
 
@@ -30,6 +32,7 @@ func init() {
 		},
 		Settings: []string{
 			"DisableDeletionProtection",
+			"BackupBeforeDelete",
 		},
 	})
 }
@@ -118,10 +121,17 @@ func (r *NeptuneCluster) Remove(_ context.Context) error {
 		}
 	}
 
-	_, err := r.svc.DeleteDBCluster(&neptune.DeleteDBClusterInput{
+	params := &neptune.DeleteDBClusterInput{
 		DBClusterIdentifier: r.ID,
 		SkipFinalSnapshot:   ptr.Bool(true),
-	})
+	}
+
+	if r.settings.GetBool("BackupBeforeDelete") {
+		params.SkipFinalSnapshot = ptr.Bool(false)
+		params.FinalDBSnapshotIdentifier = ptr.String(backup.SnapshotIdentifier(ptr.ToString(r.ID), time.Now()))
+	}
+
+	_, err := r.svc.DeleteDBCluster(params)
 
 	return err
 }
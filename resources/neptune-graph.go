@@ -28,6 +28,8 @@ func init() {
 			"DisableDeletionProtection",
 		},
 	})
+
+	nuke.RegisterSDKv2(NeptuneGraphResource)
 }
 
 type NeptuneGraphLister struct{}
@@ -10,8 +10,10 @@ import (
 
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
+	libsettings "github.com/ekristen/libnuke/pkg/settings"
 	"github.com/ekristen/libnuke/pkg/types"
 
+	"github.com/ekristen/aws-nuke/v3/pkg/backup"
 	"github.com/ekristen/aws-nuke/v3/pkg/nuke"
 )
 
@@ -23,6 +25,9 @@ func init() {
 		Scope:    nuke.Account,
 		Resource: &NeptuneSnapshot{},
 		Lister:   &NeptuneSnapshotLister{},
+		Settings: []string{
+			"IncludeFinalSnapshots",
+		},
 		DeprecatedAliases: []string{
 			"NetpuneSnapshot",
 		},
@@ -75,17 +80,22 @@ func (l *NeptuneSnapshotLister) List(_ context.Context, o interface{}) ([]resour
 
 type NeptuneSnapshot struct {
 	svc          *neptune.Neptune
+	settings     *libsettings.Setting
 	ID           *string
 	Status       *string
 	SnapshotType *string
 	CreateTime   *time.Time
 }
 
+func (r *NeptuneSnapshot) Settings(setting *libsettings.Setting) {
+	r.settings = setting
+}
+
 func (r *NeptuneSnapshot) Filter() error {
 	if *r.SnapshotType == "automated" {
 		return fmt.Errorf("cannot delete automated snapshots")
 	}
-	return nil
+	return backup.FilterFinalSnapshot(backup.IsFinalSnapshot(aws.StringValue(r.ID)), r.settings)
 }
 
 func (r *NeptuneSnapshot) Remove(_ context.Context) error {
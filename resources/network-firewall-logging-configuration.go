@@ -26,6 +26,8 @@ func init() {
 		Lister:              &NetworkFirewallLoggingConfigurationLister{},
 		AlternativeResource: "AWS::NetworkFirewall::LoggingConfiguration",
 	})
+
+	nuke.RegisterSDKv2(NetworkFirewallLoggingConfigurationResource)
 }
 
 type NetworkFirewallLoggingConfigurationLister struct{}
@@ -25,6 +25,8 @@ func init() {
 		Lister:              &NetworkFirewallPolicyLister{},
 		AlternativeResource: "AWS::NetworkFirewall::FirewallPolicy",
 	})
+
+	nuke.RegisterSDKv2(NetworkFirewallPolicyResource)
 }
 
 type NetworkFirewallPolicyLister struct{}
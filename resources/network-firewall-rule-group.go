@@ -25,6 +25,8 @@ func init() {
 		Lister:              &NetworkFirewallRuleGroupLister{},
 		AlternativeResource: "AWS::NetworkFirewall::RuleGroup",
 	})
+
+	nuke.RegisterSDKv2(NetworkFirewallRuleGroupResource)
 }
 
 type NetworkFirewallRuleGroupLister struct{}
@@ -28,6 +28,8 @@ func init() {
 		},
 		AlternativeResource: "AWS::NetworkFirewall::Firewall",
 	})
+
+	nuke.RegisterSDKv2(NetworkFirewallResource)
 }
 
 type NetworkFirewallLister struct{}
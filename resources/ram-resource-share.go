@@ -24,6 +24,8 @@ func init() {
 		Resource: &RAMResourceShare{},
 		Lister:   &RAMResourceShareLister{},
 	})
+
+	nuke.RegisterSDKv2(RAMResourceShareResource)
 }
 
 type RAMResourceShareLister struct {
@@ -10,8 +10,10 @@ import (
 
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
+	libsettings "github.com/ekristen/libnuke/pkg/settings"
 	"github.com/ekristen/libnuke/pkg/types"
 
+	"github.com/ekristen/aws-nuke/v3/pkg/backup"
 	"github.com/ekristen/aws-nuke/v3/pkg/nuke"
 )
 
@@ -23,6 +25,9 @@ func init() {
 		Scope:    nuke.Account,
 		Resource: &RDSClusterSnapshot{},
 		Lister:   &RDSClusterSnapshotLister{},
+		Settings: []string{
+			"IncludeFinalSnapshots",
+		},
 	})
 }
 
@@ -62,13 +67,19 @@ type RDSClusterSnapshot struct {
 	svc      *rds.RDS
 	snapshot *rds.DBClusterSnapshot
 	tags     []*rds.Tag
+	settings *libsettings.Setting
+}
+
+func (i *RDSClusterSnapshot) Settings(setting *libsettings.Setting) {
+	i.settings = setting
 }
 
 func (i *RDSClusterSnapshot) Filter() error {
 	if *i.snapshot.SnapshotType == "automated" {
 		return fmt.Errorf("cannot delete automated snapshots")
 	}
-	return nil
+	return backup.FilterFinalSnapshot(
+		backup.IsFinalSnapshot(aws.StringValue(i.snapshot.DBClusterSnapshotIdentifier)), i.settings)
 }
 
 func (i *RDSClusterSnapshot) Remove(_ context.Context) error {
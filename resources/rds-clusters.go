@@ -2,14 +2,17 @@ package resources
 
 import (
 	"context"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"         //nolint:staticcheck
 	"github.com/aws/aws-sdk-go/service/rds" //nolint:staticcheck
 
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
+	libsettings "github.com/ekristen/libnuke/pkg/settings"
 	"github.com/ekristen/libnuke/pkg/types"
 
+	"github.com/ekristen/aws-nuke/v3/pkg/backup"
 	"github.com/ekristen/aws-nuke/v3/pkg/nuke"
 )
 
@@ -24,6 +27,9 @@ func init() {
 		DeprecatedAliases: []string{
 			"RDSCluster",
 		},
+		Settings: []string{
+			"BackupBeforeDelete",
+		},
 	})
 }
 
@@ -65,6 +71,12 @@ type RDSDBCluster struct {
 	id                 string
 	deletionProtection bool
 	tags               []*rds.Tag
+
+	settings *libsettings.Setting
+}
+
+func (i *RDSDBCluster) Settings(settings *libsettings.Setting) {
+	i.settings = settings
 }
 
 func (i *RDSDBCluster) Remove(_ context.Context) error {
@@ -84,6 +96,11 @@ func (i *RDSDBCluster) Remove(_ context.Context) error {
 		SkipFinalSnapshot:   aws.Bool(true),
 	}
 
+	if i.settings.GetBool("BackupBeforeDelete") {
+		params.SkipFinalSnapshot = aws.Bool(false)
+		params.FinalDBSnapshotIdentifier = aws.String(backup.SnapshotIdentifier(i.id, time.Now()))
+	}
+
 	_, err := i.svc.DeleteDBCluster(params)
 	if err != nil {
 		return err
@@ -18,6 +18,7 @@ import (
 	"github.com/ekristen/libnuke/pkg/types"
 
 	"github.com/ekristen/aws-nuke/v3/pkg/awsutil"
+	"github.com/ekristen/aws-nuke/v3/pkg/backup"
 	"github.com/ekristen/aws-nuke/v3/pkg/nuke"
 )
 
@@ -32,6 +33,7 @@ func init() {
 		Settings: []string{
 			"DisableDeletionProtection",
 			"StartClusterToDelete",
+			"BackupBeforeDelete",
 		},
 	})
 }
@@ -138,6 +140,15 @@ func (i *RDSInstance) deleteDBInstance() error {
 		SkipFinalSnapshot:    aws.Bool(true),
 	}
 
+	// A final snapshot can't be taken of an instance in a cluster or of a read replica, the cluster or the source
+	// instance holds the data instead.
+	if i.settings.GetBool("BackupBeforeDelete") &&
+		i.instance.DBClusterIdentifier == nil && i.instance.ReadReplicaSourceDBInstanceIdentifier == nil {
+		params.SkipFinalSnapshot = aws.Bool(false)
+		params.FinalDBSnapshotIdentifier = aws.String(
+			backup.SnapshotIdentifier(aws.StringValue(i.instance.DBInstanceIdentifier), time.Now()))
+	}
+
 	if _, err := i.svc.DeleteDBInstance(params); err != nil {
 		return err
 	}
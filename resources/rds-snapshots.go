@@ -10,8 +10,10 @@ import (
 
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
+	libsettings "github.com/ekristen/libnuke/pkg/settings"
 	"github.com/ekristen/libnuke/pkg/types"
 
+	"github.com/ekristen/aws-nuke/v3/pkg/backup"
 	"github.com/ekristen/aws-nuke/v3/pkg/nuke"
 )
 
@@ -25,6 +27,9 @@ func init() {
 		Scope:    nuke.Account,
 		Resource: &RDSSnapshot{},
 		Lister:   &RDSSnapshotLister{},
+		Settings: []string{
+			"IncludeFinalSnapshots",
+		},
 	})
 }
 
@@ -63,13 +68,18 @@ type RDSSnapshot struct {
 	svc      *rds.RDS
 	snapshot *rds.DBSnapshot
 	tags     []*rds.Tag
+	settings *libsettings.Setting
+}
+
+func (i *RDSSnapshot) Settings(setting *libsettings.Setting) {
+	i.settings = setting
 }
 
 func (i *RDSSnapshot) Filter() error {
 	if *i.snapshot.SnapshotType == RDSAutomatedSnapshot {
 		return fmt.Errorf("cannot delete automated snapshots")
 	}
-	return nil
+	return backup.FilterFinalSnapshot(backup.IsFinalSnapshot(aws.StringValue(i.snapshot.DBSnapshotIdentifier)), i.settings)
 }
 
 func (i *RDSSnapshot) Remove(_ context.Context) error {
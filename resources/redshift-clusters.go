@@ -2,6 +2,7 @@ package resources
 
 import (
 	"context"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"              //nolint:staticcheck
 	"github.com/aws/aws-sdk-go/service/redshift" //nolint:staticcheck
@@ -87,3 +88,8 @@ func (f *RedshiftCluster) Remove(_ context.Context) error {
 func (f *RedshiftCluster) String() string {
 	return *f.cluster.ClusterIdentifier
 }
+
+// GetCreationTime returns the time the cluster was created.
+func (f *RedshiftCluster) GetCreationTime() *time.Time {
+	return f.cluster.ClusterCreateTime
+}
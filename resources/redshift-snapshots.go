@@ -2,6 +2,7 @@ package resources
 
 import (
 	"context"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"              //nolint:staticcheck
 	"github.com/aws/aws-sdk-go/service/redshift" //nolint:staticcheck
@@ -86,3 +87,8 @@ func (f *RedshiftSnapshot) Remove(_ context.Context) error {
 func (f *RedshiftSnapshot) String() string {
 	return *f.snapshot.SnapshotIdentifier
 }
+
+// GetCreationTime returns the time the snapshot was created.
+func (f *RedshiftSnapshot) GetCreationTime() *time.Time {
+	return f.snapshot.SnapshotCreateTime
+}
@@ -27,6 +27,8 @@ func init() {
 		Resource: &Route53ProfileAssociation{},
 		Lister:   &Route53ProfileAssociationLister{},
 	})
+
+	nuke.RegisterSDKv2(Route53ProfileAssociationResource)
 }
 
 type Route53ProfileAssociationLister struct{}
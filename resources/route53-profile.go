@@ -23,6 +23,8 @@ func init() {
 		Resource: &Route53Profile{},
 		Lister:   &Route53ProfileLister{},
 	})
+
+	nuke.RegisterSDKv2(Route53ProfileResource)
 }
 
 type Route53ProfileLister struct{}
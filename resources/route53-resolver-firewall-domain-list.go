@@ -22,6 +22,8 @@ func init() {
 		Resource: &Route53ResolverFirewallDomainList{},
 		Lister:   &Route53ResolverFirewallDomainListLister{},
 	})
+
+	nuke.RegisterSDKv2(Route53ResolverFirewallDomainListResource)
 }
 
 type Route53ResolverFirewallDomainListLister struct {
@@ -23,6 +23,8 @@ func init() {
 		Resource: &Route53ResolverFirewallRuleGroup{},
 		Lister:   &Route53ResolverFirewallRuleGroupLister{},
 	})
+
+	nuke.RegisterSDKv2(Route53ResolverFirewallRuleGroupResource)
 }
 
 type Route53ResolverFirewallRuleGroupLister struct {
@@ -23,6 +23,8 @@ func init() {
 		Resource: &Route53ResolverQueryLogConfig{},
 		Lister:   &Route53ResolverQueryLogConfigLister{},
 	})
+
+	nuke.RegisterSDKv2(Route53ResolverQueryLogConfigResource)
 }
 
 type Route53ResolverQueryLogConfigLister struct {
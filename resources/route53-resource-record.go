@@ -2,6 +2,7 @@ package resources
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"github.com/gotidy/ptr"
@@ -14,6 +15,7 @@ import (
 	"github.com/ekristen/libnuke/pkg/types"
 
 	"github.com/ekristen/aws-nuke/v3/pkg/nuke"
+	"github.com/ekristen/aws-nuke/v3/pkg/undo"
 )
 
 const Route53ResourceRecordSetResource = "Route53ResourceRecordSet"
@@ -25,6 +27,8 @@ func init() {
 		Resource: &Route53ResourceRecordSet{},
 		Lister:   &Route53ResourceRecordSetLister{},
 	})
+
+	undo.RegisterRestorer(Route53ResourceRecordSetResource, restoreRoute53ResourceRecordSet)
 }
 
 type Route53ResourceRecordSetLister struct{}
@@ -145,3 +149,44 @@ func (r *Route53ResourceRecordSet) Properties() types.Properties {
 func (r *Route53ResourceRecordSet) String() string {
 	return ptr.ToString(r.Name)
 }
+
+// route53ResourceRecordSetUndo is the definition of a record that is recorded in the undo log
+type route53ResourceRecordSetUndo struct {
+	HostedZoneID      *string
+	ResourceRecordSet *route53.ResourceRecordSet
+}
+
+// UndoPayload returns the definition of the record, it is the same record set that is used to delete it
+func (r *Route53ResourceRecordSet) UndoPayload(_ context.Context) (interface{}, error) {
+	return &route53ResourceRecordSetUndo{
+		HostedZoneID:      r.hostedZoneID,
+		ResourceRecordSet: r.resourceRecordSet,
+	}, nil
+}
+
+// restoreRoute53ResourceRecordSet recreates a record from the undo log, its hosted zone must still exist
+func restoreRoute53ResourceRecordSet(_ context.Context, opts *nuke.ListerOpts, payload json.RawMessage) error {
+	p := &route53ResourceRecordSetUndo{}
+	if err := json.Unmarshal(payload, p); err != nil {
+		return err
+	}
+
+	if p.ResourceRecordSet == nil {
+		return fmt.Errorf("the undo log entry does not contain a record set")
+	}
+
+	_, err := route53.New(opts.Session).ChangeResourceRecordSets(&route53.ChangeResourceRecordSetsInput{
+		HostedZoneId: p.HostedZoneID,
+		ChangeBatch: &route53.ChangeBatch{
+			Comment: aws.String("restored by aws-nuke"),
+			Changes: []*route53.Change{
+				{
+					Action:            aws.String("CREATE"),
+					ResourceRecordSet: p.ResourceRecordSet,
+				},
+			},
+		},
+	})
+
+	return err
+}
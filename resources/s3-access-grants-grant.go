@@ -25,6 +25,8 @@ func init() {
 		Resource: &S3AccessGrantsGrant{},
 		Lister:   &S3AccessGrantsGrantLister{},
 	})
+
+	nuke.RegisterSDKv2(S3AccessGrantsGrantResource)
 }
 
 type S3AccessGrantsGrantLister struct{}
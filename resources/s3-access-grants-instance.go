@@ -22,6 +22,8 @@ func init() {
 		Resource: &S3AccessGrantsInstance{},
 		Lister:   &S3AccessGrantsInstanceLister{},
 	})
+
+	nuke.RegisterSDKv2(S3AccessGrantsInstanceResource)
 }
 
 type S3AccessGrantsInstanceLister struct{}
@@ -23,6 +23,8 @@ func init() {
 		Resource: &S3AccessGrantsLocation{},
 		Lister:   &S3AccessGrantsLocationLister{},
 	})
+
+	nuke.RegisterSDKv2(S3AccessGrantsLocationResource)
 }
 
 type S3AccessGrantsLocationLister struct{}
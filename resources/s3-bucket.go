@@ -39,6 +39,8 @@ func init() {
 			"RemoveObjectLegalHold",
 		},
 	})
+
+	nuke.RegisterSDKv2(S3BucketResource)
 }
 
 type S3BucketLister struct{}
@@ -130,6 +132,14 @@ func DescribeS3Buckets(ctx context.Context, svc DescribeS3BucketsAPIClient, opts
 	return buckets, nil
 }
 
+// ListCachedS3Buckets returns the buckets of the region, the list is shared by the listers of the contents of buckets
+// until a bucket is removed.
+func ListCachedS3Buckets(ctx context.Context, svc DescribeS3BucketsAPIClient, opts *nuke.ListerOpts) ([]s3types.Bucket, error) {
+	return nuke.Cached(opts, nuke.ListCacheKey{ResourceType: S3BucketResource}, func() ([]s3types.Bucket, error) {
+		return DescribeS3Buckets(ctx, svc, opts)
+	})
+}
+
 type S3Bucket struct {
 	svc          *s3.Client
 	settings     *libsettings.Setting
@@ -139,6 +149,26 @@ type S3Bucket struct {
 	ObjectLock   s3types.ObjectLockEnabled
 }
 
+// RefreshTags reads the tags of the bucket again, a bucket without tags has no tag set.
+func (r *S3Bucket) RefreshTags(ctx context.Context) error {
+	resp, err := r.svc.GetBucketTagging(ctx, &s3.GetBucketTaggingInput{
+		Bucket: r.Name,
+	})
+	if err != nil {
+		var aerr smithy.APIError
+		if errors.As(err, &aerr) && aerr.ErrorCode() == "NoSuchTagSet" {
+			r.Tags = make([]s3types.Tag, 0)
+			return nil
+		}
+
+		return err
+	}
+
+	r.Tags = resp.TagSet
+
+	return nil
+}
+
 func (r *S3Bucket) Remove(ctx context.Context) error {
 	_, err := r.svc.DeleteBucketPolicy(ctx, &s3.DeleteBucketPolicyInput{
 		Bucket: r.Name,
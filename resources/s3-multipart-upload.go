@@ -22,6 +22,8 @@ func init() {
 		Resource: &S3MultipartUpload{},
 		Lister:   &S3MultipartUploadLister{},
 	})
+
+	nuke.RegisterSDKv2(S3MultipartUploadResource)
 }
 
 type S3MultipartUploadLister struct{}
@@ -32,7 +34,7 @@ func (l *S3MultipartUploadLister) List(ctx context.Context, o interface{}) ([]re
 
 	resources := make([]resource.Resource, 0)
 
-	buckets, err := DescribeS3Buckets(ctx, svc, opts)
+	buckets, err := ListCachedS3Buckets(ctx, svc, opts)
 	if err != nil {
 		return nil, err
 	}
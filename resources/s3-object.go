@@ -2,6 +2,7 @@ package resources
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -13,6 +14,7 @@ import (
 	"github.com/ekristen/libnuke/pkg/resource"
 	"github.com/ekristen/libnuke/pkg/types"
 
+	"github.com/ekristen/aws-nuke/v3/pkg/awsmod"
 	"github.com/ekristen/aws-nuke/v3/pkg/nuke"
 )
 
@@ -25,32 +27,38 @@ func init() {
 		Resource: &S3Object{},
 		Lister:   &S3ObjectLister{},
 	})
+
+	nuke.RegisterSDKv2(S3ObjectResource)
 }
 
 type S3ObjectLister struct{}
 
 func (l *S3ObjectLister) List(ctx context.Context, o interface{}) ([]resource.Resource, error) {
+	return nuke.ListPages(ctx, l, o)
+}
+
+// ListPages lists the object versions and delete markers of every bucket, one page of up to 1000 at a time.
+func (l *S3ObjectLister) ListPages(ctx context.Context, o interface{}, page func([]resource.Resource) error) error {
 	opts := o.(*nuke.ListerOpts)
 	svc := s3.NewFromConfig(*opts.Config)
 
-	resources := make([]resource.Resource, 0)
-
-	buckets, err := DescribeS3Buckets(ctx, svc, opts)
+	buckets, err := ListCachedS3Buckets(ctx, svc, opts)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	for _, bucket := range buckets {
-		params := &s3.ListObjectVersionsInput{
+		paginator := s3.NewListObjectVersionsPaginator(svc, &s3.ListObjectVersionsInput{
 			Bucket: bucket.Name,
-		}
+		})
 
-		for {
-			resp, err := svc.ListObjectVersions(ctx, params)
+		for paginator.HasMorePages() {
+			resp, err := paginator.NextPage(ctx)
 			if err != nil {
-				return nil, err
+				return err
 			}
 
+			resources := make([]resource.Resource, 0, len(resp.Versions)+len(resp.DeleteMarkers))
 			for i := range resp.Versions {
 				out := &resp.Versions[i]
 				if out.Key == nil {
@@ -64,6 +72,7 @@ func (l *S3ObjectLister) List(ctx context.Context, o interface{}) ([]resource.Re
 					Key:          out.Key,
 					VersionID:    out.VersionId,
 					IsLatest:     out.IsLatest,
+					lastModified: out.LastModified,
 				})
 			}
 
@@ -79,24 +88,70 @@ func (l *S3ObjectLister) List(ctx context.Context, o interface{}) ([]resource.Re
 					Key:          out.Key,
 					VersionID:    out.VersionId,
 					IsLatest:     out.IsLatest,
+					lastModified: out.LastModified,
 				})
 			}
 
-			// make sure to list all with more than 1000 objects
-			if *resp.IsTruncated {
-				params.KeyMarker = resp.NextKeyMarker
-				continue
+			if err := page(resources); err != nil {
+				return err
 			}
+		}
+	}
+
+	return nil
+}
+
+// RemoveBatch removes the objects with DeleteObjects, the objects of a bucket are removed in batches of 1000.
+func (l *S3ObjectLister) RemoveBatch(ctx context.Context, o interface{}, resources []resource.Resource) []error {
+	opts := o.(*nuke.ListerOpts)
+	svc := s3.NewFromConfig(*opts.Config)
+
+	objects := make([]awsmod.BatchDeleteObject, 0, len(resources))
+	for _, r := range resources {
+		object := r.(*S3Object)
+		objects = append(objects, awsmod.BatchDeleteObject{
+			Object: &s3.DeleteObjectInput{
+				Bucket:    object.Bucket,
+				Key:       object.Key,
+				VersionId: object.VersionID,
+			},
+		})
+	}
+
+	errs := make([]error, len(resources))
 
-			break
+	err := awsmod.NewBatchDeleteWithClient(svc, -1).Delete(ctx, &awsmod.DeleteObjectsIterator{Objects: objects})
+	if err == nil {
+		return errs
+	}
+
+	// The errors of a batch only name the bucket and the key, all versions of a key that failed are failed
+	var batchErr *awsmod.BatchError
+	if !errors.As(err, &batchErr) {
+		for i := range errs {
+			errs[i] = err
 		}
+
+		return errs
 	}
 
-	return resources, nil
+	for j := range batchErr.Errors {
+		objErr := &batchErr.Errors[j]
+		for i, r := range resources {
+			object := r.(*S3Object)
+			if objErr.Key == nil || (ptr.ToString(objErr.Bucket) == ptr.ToString(object.Bucket) &&
+				ptr.ToString(objErr.Key) == ptr.ToString(object.Key)) {
+				errs[i] = objErr
+			}
+		}
+	}
+
+	return errs
 }
 
 type S3Object struct {
 	svc          *s3.Client
+	lastModified *time.Time
 	Bucket       *string
 	CreationDate *time.Time
 	Key          *string
@@ -119,6 +174,11 @@ func (r *S3Object) Remove(ctx context.Context) error {
 	return nil
 }
 
+// LastModified returns when the version was created, versions that are created after the scan are not removed.
+func (r *S3Object) LastModified() *time.Time {
+	return r.lastModified
+}
+
 func (r *S3Object) Properties() types.Properties {
 	return types.NewPropertiesFromStruct(r)
 }
@@ -0,0 +1,57 @@
+package resources
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gotidy/ptr"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+
+	"github.com/ekristen/libnuke/pkg/registry"
+
+	"github.com/ekristen/aws-nuke/v3/pkg/nuke"
+)
+
+// TestSDKv2Registrations runs the listers of the resource types registered as SDK v2 with options that only have the
+// SDK v2 config, as the run does, to check that they do not use the SDK v1 session which is nil for them. The requests
+// fail against the test server, only a panic fails the test.
+func TestSDKv2Registrations(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	opts := &nuke.ListerOpts{
+		Region: &nuke.Region{
+			Name: "us-east-1",
+		},
+		Config: &aws.Config{
+			Region:           "us-east-1",
+			Credentials:      aws.AnonymousCredentials{},
+			BaseEndpoint:     aws.String(server.URL),
+			RetryMaxAttempts: 1,
+		},
+		AccountID: ptr.String("012345678901"),
+		Logger:    logrus.NewEntry(logrus.StandardLogger()),
+		Cache:     nuke.NewListCache(),
+	}
+
+	for _, resourceType := range nuke.GetSDKv2ResourceTypes() {
+		reg := registry.GetRegistration(resourceType)
+		if !assert.NotNil(t, reg, "%s is registered as SDK v2 but not registered", resourceType) {
+			continue
+		}
+
+		t.Run(resourceType, func(t *testing.T) {
+			require.NotPanics(t, func() {
+				_, _ = reg.Lister.List(context.TODO(), opts)
+			})
+		})
+	}
+}
@@ -14,8 +14,10 @@ import (
 
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
+	libsettings "github.com/ekristen/libnuke/pkg/settings"
 	"github.com/ekristen/libnuke/pkg/types"
 
+	"github.com/ekristen/aws-nuke/v3/pkg/backup"
 	"github.com/ekristen/aws-nuke/v3/pkg/nuke"
 )
 
@@ -30,6 +32,10 @@ func init() {
 		Scope:    nuke.Account,
 		Resource: &SecretsManagerSecret{},
 		Lister:   &SecretsManagerSecretLister{},
+		Settings: []string{
+			"BackupBeforeDelete",
+			"BackupArchive",
+		},
 	})
 }
 
@@ -99,6 +105,7 @@ func (l *SecretsManagerSecretLister) List(_ context.Context, o interface{}) ([]r
 
 type SecretsManagerSecret struct {
 	svc            secretsmanageriface.SecretsManagerAPI
+	settings       *libsettings.Setting
 	primarySvc     secretsmanageriface.SecretsManagerAPI
 	region         *string
 	ARN            *string
@@ -116,7 +123,18 @@ func (r *SecretsManagerSecret) ParentARN() *string {
 	return ptr.String(strings.ReplaceAll(*r.ARN, *r.region, *r.PrimaryRegion))
 }
 
+func (r *SecretsManagerSecret) Settings(settings *libsettings.Setting) {
+	r.settings = settings
+}
+
 func (r *SecretsManagerSecret) Remove(_ context.Context) error {
+	// Note: a replica is only removed from the replication, the primary secret still holds the value
+	if r.settings.GetBool("BackupBeforeDelete") && !r.Replica {
+		if err := r.backup(); err != nil {
+			return err
+		}
+	}
+
 	if r.Replica {
 		_, err := r.primarySvc.RemoveRegionsFromReplication(&secretsmanager.RemoveRegionsFromReplicationInput{
 			SecretId:             r.ParentARN(),
@@ -134,17 +152,62 @@ func (r *SecretsManagerSecret) Remove(_ context.Context) error {
 	return err
 }
 
-func (r *SecretsManagerSecret) Filter() error {
+// backup copies the current value of the secret into the encrypted backup archive
+func (r *SecretsManagerSecret) backup() error {
+	resp, err := r.svc.GetSecretValue(&secretsmanager.GetSecretValueInput{
+		SecretId: r.ARN,
+	})
+	if err != nil {
+		return err
+	}
+
+	archive := r.settings.GetString("BackupArchive")
+	if archive == "" {
+		archive = backup.DefaultArchivePath
+	}
+
+	record := &backup.Record{
+		ResourceType: SecretsManagerSecretResource,
+		Region:       ptr.ToString(r.region),
+		Name:         ptr.ToString(r.Name),
+		Attributes: map[string]string{
+			"ARN":       ptr.ToString(r.ARN),
+			"VersionId": ptr.ToString(resp.VersionId),
+			"Encoding":  "string",
+		},
+		Value: []byte(ptr.ToString(resp.SecretString)),
+	}
+
+	if resp.SecretBinary != nil {
+		record.Attributes["Encoding"] = "binary"
+		record.Value = resp.SecretBinary
+	}
+
+	return backup.Append(archive, record)
+}
+
+// IsManaged returns true for secrets that are owned by another AWS service.
+func (r *SecretsManagerSecret) IsManaged() bool {
 	if managedRegex.MatchString(*r.Name) {
-		return errAWSManaged
+		return true
 	}
 
 	for _, tag := range r.tags {
 		if *tag.Key == "aws:secretsmanager:owningService" {
-			return errAWSManaged
+			return true
 		}
 	}
 
+	return false
+}
+
+// Filter refuses AWS managed secrets even when AWS-managed resources are included, they can only be deleted by the
+// service that owns them.
+func (r *SecretsManagerSecret) Filter() error {
+	if r.IsManaged() {
+		return errAWSManaged
+	}
+
 	return nil
 }
 
@@ -2,6 +2,7 @@ package resources
 
 import (
 	"context"
+	"path/filepath"
 	"testing"
 
 	"github.com/golang/mock/gomock"
@@ -10,7 +11,10 @@ import (
 
 	"github.com/aws/aws-sdk-go/service/secretsmanager" //nolint:staticcheck
 
+	libsettings "github.com/ekristen/libnuke/pkg/settings"
+
 	"github.com/ekristen/aws-nuke/v3/mocks/mock_secretsmanageriface"
+	"github.com/ekristen/aws-nuke/v3/pkg/backup"
 )
 
 func Test_Mock_SecretsManager_List(t *testing.T) {
@@ -67,9 +71,10 @@ func Test_Mock_SecretsManager_Secret_Remove(t *testing.T) {
 	mockSvc := mock_secretsmanageriface.NewMockSecretsManagerAPI(ctrl)
 
 	resource := SecretsManagerSecret{
-		svc:  mockSvc,
-		ARN:  ptr.String("arn:foo"),
-		Name: ptr.String("foo"),
+		svc:      mockSvc,
+		settings: &libsettings.Setting{},
+		ARN:      ptr.String("arn:foo"),
+		Name:     ptr.String("foo"),
 	}
 
 	mockSvc.EXPECT().DeleteSecret(gomock.Eq(&secretsmanager.DeleteSecretInput{
@@ -90,6 +95,7 @@ func Test_Mock_SecretsManager_Secret_RemoveReplica(t *testing.T) {
 
 	resource := SecretsManagerSecret{
 		svc:           mockSvc,
+		settings:      &libsettings.Setting{},
 		primarySvc:    mockSvc,
 		region:        ptr.String("us-east-1"), // region this replica is in
 		ARN:           ptr.String("arn:foo"),
@@ -106,3 +112,79 @@ func Test_Mock_SecretsManager_Secret_RemoveReplica(t *testing.T) {
 	err := resource.Remove(context.TODO())
 	a.Nil(err)
 }
+
+func Test_Mock_SecretsManager_Secret_RemoveWithBackup(t *testing.T) {
+	a := assert.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	archive := filepath.Join(t.TempDir(), "backup.archive")
+	t.Setenv(backup.PassphraseEnv, "test-passphrase")
+
+	mockSvc := mock_secretsmanageriface.NewMockSecretsManagerAPI(ctrl)
+
+	resource := SecretsManagerSecret{
+		svc: mockSvc,
+		settings: &libsettings.Setting{
+			"BackupBeforeDelete": true,
+			"BackupArchive":      archive,
+		},
+		region: ptr.String("us-east-1"),
+		ARN:    ptr.String("arn:foo"),
+		Name:   ptr.String("foo"),
+	}
+
+	gomock.InOrder(
+		mockSvc.EXPECT().GetSecretValue(gomock.Eq(&secretsmanager.GetSecretValueInput{
+			SecretId: ptr.String("arn:foo"),
+		})).Return(&secretsmanager.GetSecretValueOutput{
+			SecretString: ptr.String("s3cr3t"),
+			VersionId:    ptr.String("v1"),
+		}, nil),
+		mockSvc.EXPECT().DeleteSecret(gomock.Eq(&secretsmanager.DeleteSecretInput{
+			SecretId:                   ptr.String("arn:foo"),
+			ForceDeleteWithoutRecovery: ptr.Bool(true),
+		})).Return(&secretsmanager.DeleteSecretOutput{}, nil),
+	)
+
+	err := resource.Remove(context.TODO())
+	a.Nil(err)
+
+	records, err := backup.Read(archive, "test-passphrase")
+	a.NoError(err)
+	a.Len(records, 1)
+	a.Equal(SecretsManagerSecretResource, records[0].ResourceType)
+	a.Equal("foo", records[0].Name)
+	a.Equal("us-east-1", records[0].Region)
+	a.Equal([]byte("s3cr3t"), records[0].Value)
+	a.Equal("v1", records[0].Attributes["VersionId"])
+}
+
+func Test_Mock_SecretsManager_Secret_RemoveWithBackupNoPassphrase(t *testing.T) {
+	a := assert.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Setenv(backup.PassphraseEnv, "")
+
+	mockSvc := mock_secretsmanageriface.NewMockSecretsManagerAPI(ctrl)
+
+	resource := SecretsManagerSecret{
+		svc: mockSvc,
+		settings: &libsettings.Setting{
+			"BackupBeforeDelete": true,
+			"BackupArchive":      filepath.Join(t.TempDir(), "backup.archive"),
+		},
+		region: ptr.String("us-east-1"),
+		ARN:    ptr.String("arn:foo"),
+		Name:   ptr.String("foo"),
+	}
+
+	// The secret must not be deleted when the backup fails
+	mockSvc.EXPECT().GetSecretValue(gomock.Any()).Return(&secretsmanager.GetSecretValueOutput{
+		SecretString: ptr.String("s3cr3t"),
+	}, nil)
+
+	err := resource.Remove(context.TODO())
+	a.ErrorIs(err, backup.ErrNoPassphrase)
+}
@@ -22,6 +22,8 @@ func init() {
 		Resource: &ShieldProtectionGroup{},
 		Lister:   &ShieldProtectionGroupLister{},
 	})
+
+	nuke.RegisterSDKv2(ShieldProtectionGroupResource)
 }
 
 type ShieldProtectionGroupLister struct{}
@@ -22,6 +22,8 @@ func init() {
 		Resource: &ShieldProtection{},
 		Lister:   &ShieldProtectionLister{},
 	})
+
+	nuke.RegisterSDKv2(ShieldProtectionResource)
 }
 
 type ShieldProtectionLister struct{}
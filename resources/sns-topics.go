@@ -2,8 +2,11 @@ package resources
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strings"
 
+	"github.com/aws/aws-sdk-go/aws"         //nolint:staticcheck
 	"github.com/aws/aws-sdk-go/service/sns" //nolint:staticcheck
 
 	"github.com/ekristen/libnuke/pkg/registry"
@@ -11,6 +14,7 @@ import (
 	"github.com/ekristen/libnuke/pkg/types"
 
 	"github.com/ekristen/aws-nuke/v3/pkg/nuke"
+	"github.com/ekristen/aws-nuke/v3/pkg/undo"
 )
 
 const SNSTopicResource = "SNSTopic"
@@ -22,6 +26,8 @@ func init() {
 		Resource: &SNSTopic{},
 		Lister:   &SNSTopicLister{},
 	})
+
+	undo.RegisterRestorer(SNSTopicResource, restoreSNSTopic)
 }
 
 type SNSTopicLister struct{}
@@ -88,3 +94,72 @@ func (topic *SNSTopic) Properties() types.Properties {
 func (topic *SNSTopic) String() string {
 	return fmt.Sprintf("TopicARN: %s", *topic.id)
 }
+
+// snsTopicRestoreAttributes are the attributes of a topic that can be set when it is created, the others are read-only
+var snsTopicRestoreAttributes = []string{
+	"ArchivePolicy",
+	"ContentBasedDeduplication",
+	"DeliveryPolicy",
+	"DisplayName",
+	"FifoTopic",
+	"KmsMasterKeyId",
+	"Policy",
+	"SignatureVersion",
+	"TracingConfig",
+}
+
+// snsTopicUndo is the definition of a topic that is recorded in the undo log
+type snsTopicUndo struct {
+	TopicArn   *string
+	Attributes map[string]*string
+	Tags       []*sns.Tag
+}
+
+// UndoPayload returns the definition of the topic, its subscriptions are not part of it
+func (topic *SNSTopic) UndoPayload(_ context.Context) (interface{}, error) {
+	resp, err := topic.svc.GetTopicAttributes(&sns.GetTopicAttributesInput{
+		TopicArn: topic.id,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &snsTopicUndo{
+		TopicArn:   topic.id,
+		Attributes: resp.Attributes,
+		Tags:       topic.tags,
+	}, nil
+}
+
+// restoreSNSTopic recreates a topic from the undo log, the topic keeps its name and therefore its ARN
+func restoreSNSTopic(_ context.Context, opts *nuke.ListerOpts, payload json.RawMessage) error {
+	p := &snsTopicUndo{}
+	if err := json.Unmarshal(payload, p); err != nil {
+		return err
+	}
+
+	arn := aws.StringValue(p.TopicArn)
+	name := arn[strings.LastIndex(arn, ":")+1:]
+	if name == "" {
+		return fmt.Errorf("unable to determine the name of the topic from '%s'", arn)
+	}
+
+	attributes := make(map[string]*string)
+	for _, key := range snsTopicRestoreAttributes {
+		if value, ok := p.Attributes[key]; ok && aws.StringValue(value) != "" {
+			attributes[key] = value
+		}
+	}
+
+	input := &sns.CreateTopicInput{
+		Name:       aws.String(name),
+		Attributes: attributes,
+	}
+	if len(p.Tags) > 0 {
+		input.Tags = p.Tags
+	}
+
+	_, err := sns.New(opts.Session).CreateTopic(input)
+
+	return err
+}
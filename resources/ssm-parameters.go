@@ -2,15 +2,20 @@ package resources
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 
 	"github.com/aws/aws-sdk-go/aws"         //nolint:staticcheck
 	"github.com/aws/aws-sdk-go/service/ssm" //nolint:staticcheck
 
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
+	libsettings "github.com/ekristen/libnuke/pkg/settings"
 	"github.com/ekristen/libnuke/pkg/types"
 
+	"github.com/ekristen/aws-nuke/v3/pkg/backup"
 	"github.com/ekristen/aws-nuke/v3/pkg/nuke"
+	"github.com/ekristen/aws-nuke/v3/pkg/undo"
 )
 
 const SSMParameterResource = "SSMParameter"
@@ -21,7 +26,13 @@ func init() {
 		Scope:    nuke.Account,
 		Resource: &SSMParameter{},
 		Lister:   &SSMParameterLister{},
+		Settings: []string{
+			"BackupBeforeDelete",
+			"BackupArchive",
+		},
 	})
+
+	undo.RegisterRestorer(SSMParameterResource, restoreSSMParameter)
 }
 
 type SSMParameterLister struct{}
@@ -54,9 +65,10 @@ func (l *SSMParameterLister) List(_ context.Context, o interface{}) ([]resource.
 			}
 
 			resources = append(resources, &SSMParameter{
-				svc:  svc,
-				name: parameter.Name,
-				tags: tagResp.TagList,
+				svc:    svc,
+				region: opts.Region.Name,
+				name:   parameter.Name,
+				tags:   tagResp.TagList,
 			})
 		}
 
@@ -71,12 +83,24 @@ func (l *SSMParameterLister) List(_ context.Context, o interface{}) ([]resource.
 }
 
 type SSMParameter struct {
-	svc  *ssm.SSM
-	name *string
-	tags []*ssm.Tag
+	svc      *ssm.SSM
+	settings *libsettings.Setting
+	region   string
+	name     *string
+	tags     []*ssm.Tag
+}
+
+func (f *SSMParameter) Settings(settings *libsettings.Setting) {
+	f.settings = settings
 }
 
 func (f *SSMParameter) Remove(_ context.Context) error {
+	if f.settings.GetBool("BackupBeforeDelete") {
+		if err := f.backup(); err != nil {
+			return err
+		}
+	}
+
 	_, err := f.svc.DeleteParameter(&ssm.DeleteParameterInput{
 		Name: f.name,
 	})
@@ -97,3 +121,123 @@ func (f *SSMParameter) Properties() types.Properties {
 		Set("Name", f.name)
 	return properties
 }
+
+// backup copies the decrypted value of the parameter into the encrypted backup archive
+func (f *SSMParameter) backup() error {
+	resp, err := f.svc.GetParameter(&ssm.GetParameterInput{
+		Name:           f.name,
+		WithDecryption: aws.Bool(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	archive := f.settings.GetString("BackupArchive")
+	if archive == "" {
+		archive = backup.DefaultArchivePath
+	}
+
+	return backup.Append(archive, &backup.Record{
+		ResourceType: SSMParameterResource,
+		Region:       f.region,
+		Name:         aws.StringValue(f.name),
+		Attributes: map[string]string{
+			"Type":     aws.StringValue(resp.Parameter.Type),
+			"DataType": aws.StringValue(resp.Parameter.DataType),
+		},
+		Value: []byte(aws.StringValue(resp.Parameter.Value)),
+	})
+}
+
+// ssmParameterUndo is the definition of a parameter that is recorded in the undo log. The value of a SecureString
+// parameter is never recorded, the undo log is not encrypted.
+type ssmParameterUndo struct {
+	Name           *string
+	Type           *string
+	Value          *string
+	Description    *string
+	Tier           *string
+	AllowedPattern *string
+	DataType       *string
+	Tags           []*ssm.Tag
+}
+
+// UndoPayload returns the definition of the parameter
+func (f *SSMParameter) UndoPayload(_ context.Context) (interface{}, error) {
+	resp, err := f.svc.DescribeParameters(&ssm.DescribeParametersInput{
+		ParameterFilters: []*ssm.ParameterStringFilter{
+			{
+				Key:    aws.String("Name"),
+				Option: aws.String("Equals"),
+				Values: []*string{f.name},
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.Parameters) == 0 {
+		return nil, fmt.Errorf("parameter %s not found", aws.StringValue(f.name))
+	}
+
+	metadata := resp.Parameters[0]
+	p := &ssmParameterUndo{
+		Name:           f.name,
+		Type:           metadata.Type,
+		Description:    metadata.Description,
+		Tier:           metadata.Tier,
+		AllowedPattern: metadata.AllowedPattern,
+		DataType:       metadata.DataType,
+		Tags:           f.tags,
+	}
+
+	if aws.StringValue(metadata.Type) != ssm.ParameterTypeSecureString {
+		value, err := f.svc.GetParameter(&ssm.GetParameterInput{
+			Name: f.name,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		p.Value = value.Parameter.Value
+	}
+
+	return p, nil
+}
+
+// restoreSSMParameter recreates a parameter from the undo log. The value of a SecureString parameter is taken from the
+// backup archive that is passed to the restore.
+func restoreSSMParameter(ctx context.Context, opts *nuke.ListerOpts, payload json.RawMessage) error {
+	p := &ssmParameterUndo{}
+	if err := json.Unmarshal(payload, p); err != nil {
+		return err
+	}
+
+	if p.Value == nil {
+		record := backup.FindRecord(ctx, SSMParameterResource, opts.Region.Name, aws.StringValue(p.Name))
+		if record == nil {
+			return fmt.Errorf("the value of the %s parameter %s is not recorded in the undo log, "+
+				"restore it with the --backup-archive it was backed up to", aws.StringValue(p.Type), aws.StringValue(p.Name))
+		}
+
+		p.Value = aws.String(string(record.Value))
+	}
+
+	input := &ssm.PutParameterInput{
+		Name:           p.Name,
+		Type:           p.Type,
+		Value:          p.Value,
+		Description:    p.Description,
+		Tier:           p.Tier,
+		AllowedPattern: p.AllowedPattern,
+		DataType:       p.DataType,
+	}
+	if len(p.Tags) > 0 {
+		input.Tags = p.Tags
+	}
+
+	_, err := ssm.New(opts.Session).PutParameter(input)
+
+	return err
+}
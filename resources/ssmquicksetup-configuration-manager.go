@@ -34,6 +34,8 @@ func init() {
 			"CreateRoleToDelete",
 		},
 	})
+
+	nuke.RegisterSDKv2(SSMQuickSetupConfigurationManagerResource)
 }
 
 type SSMQuickSetupConfigurationManagerLister struct{}
@@ -25,6 +25,8 @@ func init() {
 		Resource: &TextractAdapterVersion{},
 		Lister:   &TextractAdapterVersionLister{},
 	})
+
+	nuke.RegisterSDKv2(TextractAdapterVersionResource)
 }
 
 type TextractAdapterVersionLister struct{}
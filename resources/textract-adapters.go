@@ -24,6 +24,8 @@ func init() {
 		Resource: &TextractAdapter{},
 		Lister:   &TextractAdapterLister{},
 	})
+
+	nuke.RegisterSDKv2(TextractAdapterResource)
 }
 
 type TextractAdapterLister struct{}
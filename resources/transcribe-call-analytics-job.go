@@ -109,3 +109,8 @@ func (r *TranscribeCallAnalyticsJob) Properties() types.Properties {
 func (r *TranscribeCallAnalyticsJob) String() string {
 	return *r.name
 }
+
+// GetCreationTime returns the time the call analytics job was created.
+func (r *TranscribeCallAnalyticsJob) GetCreationTime() *time.Time {
+	return r.creationTime
+}
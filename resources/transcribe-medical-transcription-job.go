@@ -114,3 +114,8 @@ func (r *TranscribeMedicalTranscriptionJob) Properties() types.Properties {
 func (r *TranscribeMedicalTranscriptionJob) String() string {
 	return *r.name
 }
+
+// GetCreationTime returns the time the medical transcription job was created.
+func (r *TranscribeMedicalTranscriptionJob) GetCreationTime() *time.Time {
+	return r.creationTime
+}
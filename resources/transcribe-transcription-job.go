@@ -102,3 +102,8 @@ func (r *TranscribeTranscriptionJob) Properties() types.Properties {
 func (r *TranscribeTranscriptionJob) String() string {
 	return *r.name
 }
+
+// GetCreationTime returns the time the transcription job was created.
+func (r *TranscribeTranscriptionJob) GetCreationTime() *time.Time {
+	return r.creationTime
+}
@@ -21,6 +21,8 @@ func init() {
 		Resource: &TransferWebApp{},
 		Lister:   &TransferWebAppLister{},
 	})
+
+	nuke.RegisterSDKv2(TransferWebAppResource)
 }
 
 type TransferWebAppLister struct{}
@@ -36,6 +36,8 @@ func init() {
 		Resource: &{{.Combined}}{},
 		Lister:   &{{.Combined}}Lister{},
 	})
+
+	nuke.RegisterSDKv2({{.Combined}}Resource)
 }
 
 type {{.Combined}}Lister struct{}